@@ -0,0 +1,512 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+type grantKey struct{}
+
+// WithGrant возвращает контекст с доверенностью, по которой выполняется действие
+func WithGrant(ctx context.Context, grantID string) context.Context {
+	return context.WithValue(ctx, grantKey{}, grantID)
+}
+
+// GrantFromContext возвращает ID доверенности из контекста
+func GrantFromContext(ctx context.Context) (string, bool) {
+	grantID, ok := ctx.Value(grantKey{}).(string)
+	return grantID, ok && grantID != ""
+}
+
+// FindAccessGrant ищет доверенность пользователя userID на счет, действующую на момент at
+func FindAccessGrant(account *models.Account, userID string, at time.Time) (models.AccessGrant, bool) {
+	for _, grant := range account.AccessGrants {
+		if grant.GranteeID == userID && grant.Active(at) {
+			return grant, true
+		}
+	}
+
+	return models.AccessGrant{}, false
+}
+
+// GrantAccess выдает пользователю username доверенность на счет до expiresAt.
+// Для переводов нужен положительный общий лимит transferLimit.
+func (s *AccountServiceImpl) GrantAccess(ctx context.Context, username string, scope models.GrantScope, transferLimit float64, expiresAt time.Time) (grant models.AccessGrant, err error) {
+	defer func() {
+		s.auditOperation(ctx, "grant_access", transferLimit, fmt.Sprintf("%s %s для %s", grant.ID, scope, username), err)
+	}()
+
+	now := s.now()
+	switch {
+	case !scope.Valid(), transferLimit < 0, !expiresAt.After(now):
+		return models.AccessGrant{}, errors.ErrInvalidGrant
+	case scope == models.TransferGrant && transferLimit == 0:
+		return models.AccessGrant{}, errors.ErrInvalidGrant
+	case scope == models.ViewGrant:
+		transferLimit = 0
+	}
+
+	grantee, err := s.storage.FindUserByUsername(ctx, username)
+	if err != nil {
+		return models.AccessGrant{}, err
+	}
+
+	if grantee.ID == s.account.OwnerID {
+		return models.AccessGrant{}, errors.ErrInvalidGrant
+	}
+
+	grant = models.AccessGrant{
+		ID:            s.newID("GR"),
+		GranteeID:     grantee.ID,
+		GranteeName:   grantee.Username,
+		Scope:         scope,
+		TransferLimit: transferLimit,
+		CreatedAt:     now,
+		ExpiresAt:     expiresAt,
+	}
+	s.account.AccessGrants = append(s.account.AccessGrants, grant)
+
+	return grant, s.saveAccount(ctx)
+}
+
+// RevokeAccess отзывает действующую доверенность
+func (s *AccountServiceImpl) RevokeAccess(ctx context.Context, grantID string) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "revoke_access", 0, grantID, err)
+	}()
+
+	now := s.now()
+	for i := range s.account.AccessGrants {
+		grant := &s.account.AccessGrants[i]
+		if grant.ID == grantID && grant.Active(now) {
+			grant.RevokedAt = now
+			return s.saveAccount(ctx)
+		}
+	}
+
+	return errors.ErrGrantNotFound
+}
+
+// ListAccessGrants возвращает действующие доверенности на счет
+func (s *AccountServiceImpl) ListAccessGrants(ctx context.Context) []models.AccessGrant {
+	now := s.now()
+
+	var grants []models.AccessGrant
+	for _, grant := range s.account.AccessGrants {
+		if grant.Active(now) {
+			grants = append(grants, grant)
+		}
+	}
+
+	return grants
+}
+
+// chargeGrant учитывает перевод в лимите доверенности из контекста.
+// Доверенность проверяется и обновляется в той же копии счета, что и
+// перевод, поэтому лимит сохраняется одной записью с ним.
+func (s *AccountServiceImpl) chargeGrant(ctx context.Context, amount float64) error {
+	grantID, ok := GrantFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	now := s.now()
+	for i := range s.account.AccessGrants {
+		grant := &s.account.AccessGrants[i]
+		if grant.ID != grantID || !grant.Active(now) {
+			continue
+		}
+
+		if grant.Scope != models.TransferGrant {
+			return errors.ErrAccessDenied
+		}
+		if grant.Transferred+amount > grant.TransferLimit {
+			return errors.ErrGrantLimitExceeded
+		}
+
+		grant.Transferred += amount
+		return nil
+	}
+
+	return errors.ErrGrantNotFound
+}
+
+// DelegatedAccountService доступ к счету по доверенности. Просмотр
+// разрешен любой действующей доверенностью, переводы - только доверенностью
+// TransferGrant в пределах ее лимита, остальные изменения счета запрещены.
+// Доверенность проверяется при каждом вызове: после отзыва или истечения
+// методы с ошибкой возвращают ErrGrantNotFound, остальные - пустые значения.
+// Все действия попадают в аудит с ID доверенности.
+type DelegatedAccountService struct {
+	interfaces.AccountService
+	storage interfaces.Storage
+	clock   models.Clock
+	grantID string
+}
+
+// NewDelegatedAccountService оборачивает сервис счета доступом по доверенности
+// grantID; срок доверенности проверяется по часам clock
+func NewDelegatedAccountService(account interfaces.AccountService, storage interfaces.Storage, clock models.Clock, grantID string) interfaces.AccountService {
+	return &DelegatedAccountService{
+		AccountService: account,
+		storage:        storage,
+		clock:          clock,
+		grantID:        grantID,
+	}
+}
+
+// Transfer переводит средства по доверенности. Лимит доверенности
+// проверяется и учитывается при проводке перевода, той же записью счета.
+func (d *DelegatedAccountService) Transfer(ctx context.Context, to *models.Account, amount float64) (models.OperationResult, error) {
+	return d.AccountService.Transfer(WithGrant(ctx, d.grantID), to, amount)
+}
+
+// loadGrant загружает счет и его действующую доверенность d.grantID
+func (d *DelegatedAccountService) loadGrant(ctx context.Context) (*models.Account, *models.AccessGrant, error) {
+	account, err := d.storage.LoadAccount(ctx, d.AccountID())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := d.clock.Now()
+	for i := range account.AccessGrants {
+		grant := &account.AccessGrants[i]
+		if grant.ID == d.grantID && grant.Active(now) {
+			return account, grant, nil
+		}
+	}
+
+	return nil, nil, errors.ErrGrantNotFound
+}
+
+// checkGrant проверяет, что доверенность еще действует
+func (d *DelegatedAccountService) checkGrant(ctx context.Context) error {
+	_, _, err := d.loadGrant(ctx)
+	return err
+}
+
+// GetBalance возвращает баланс, если доверенность еще действует
+func (d *DelegatedAccountService) GetBalance(ctx context.Context) float64 {
+	if d.checkGrant(ctx) != nil {
+		return 0
+	}
+	return d.AccountService.GetBalance(ctx)
+}
+
+// GetBalanceAt возвращает баланс на момент at, если доверенность еще действует
+func (d *DelegatedAccountService) GetBalanceAt(ctx context.Context, at time.Time) (float64, error) {
+	if err := d.checkGrant(ctx); err != nil {
+		return 0, err
+	}
+	return d.AccountService.GetBalanceAt(ctx, at)
+}
+
+// GetBalanceHistory возвращает историю баланса, если доверенность еще действует
+func (d *DelegatedAccountService) GetBalanceHistory(ctx context.Context, from, to time.Time) ([]models.BalancePoint, error) {
+	if err := d.checkGrant(ctx); err != nil {
+		return nil, err
+	}
+	return d.AccountService.GetBalanceHistory(ctx, from, to)
+}
+
+// GetStatement возвращает выписку, если доверенность еще действует
+func (d *DelegatedAccountService) GetStatement(ctx context.Context) string {
+	if d.checkGrant(ctx) != nil {
+		return ""
+	}
+	return d.AccountService.GetStatement(ctx)
+}
+
+// GetMiniStatement возвращает мини-выписку, если доверенность еще действует
+func (d *DelegatedAccountService) GetMiniStatement(ctx context.Context, count int) string {
+	if d.checkGrant(ctx) != nil {
+		return ""
+	}
+	return d.AccountService.GetMiniStatement(ctx, count)
+}
+
+// BuildStatement собирает выписку за период, если доверенность еще действует
+func (d *DelegatedAccountService) BuildStatement(ctx context.Context, from, to time.Time) models.Statement {
+	if d.checkGrant(ctx) != nil {
+		return models.Statement{}
+	}
+	return d.AccountService.BuildStatement(ctx, from, to)
+}
+
+// ListTransactions возвращает страницу транзакций, если доверенность еще действует
+func (d *DelegatedAccountService) ListTransactions(ctx context.Context, offset, limit int) ([]models.Transaction, int, error) {
+	if err := d.checkGrant(ctx); err != nil {
+		return nil, 0, err
+	}
+	return d.AccountService.ListTransactions(ctx, offset, limit)
+}
+
+// GetAttachment возвращает вложение, если доверенность еще действует
+func (d *DelegatedAccountService) GetAttachment(ctx context.Context, transactionID, attachmentID string) (models.Attachment, []byte, error) {
+	if err := d.checkGrant(ctx); err != nil {
+		return models.Attachment{}, nil, err
+	}
+	return d.AccountService.GetAttachment(ctx, transactionID, attachmentID)
+}
+
+// GetDailyAllowance возвращает остаток дневных лимитов, если доверенность еще действует
+func (d *DelegatedAccountService) GetDailyAllowance(ctx context.Context) models.DailyAllowance {
+	if d.checkGrant(ctx) != nil {
+		return models.DailyAllowance{}
+	}
+	return d.AccountService.GetDailyAllowance(ctx)
+}
+
+// ListPendingCredits возвращает входящие платежи, если доверенность еще действует
+func (d *DelegatedAccountService) ListPendingCredits(ctx context.Context) []models.PendingCredit {
+	if d.checkGrant(ctx) != nil {
+		return nil
+	}
+	return d.AccountService.ListPendingCredits(ctx)
+}
+
+// FilterTransactions ищет транзакции, если доверенность еще действует
+func (d *DelegatedAccountService) FilterTransactions(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, int, error) {
+	if err := d.checkGrant(ctx); err != nil {
+		return nil, 0, err
+	}
+	return d.AccountService.FilterTransactions(ctx, filter)
+}
+
+// SpendingByCategory возвращает расходы по категориям, если доверенность еще действует
+func (d *DelegatedAccountService) SpendingByCategory(ctx context.Context, from, to time.Time) []models.CategorySpending {
+	if d.checkGrant(ctx) != nil {
+		return nil
+	}
+	return d.AccountService.SpendingByCategory(ctx, from, to)
+}
+
+// ListChildren возвращает детские счета, если доверенность еще действует
+func (d *DelegatedAccountService) ListChildren(ctx context.Context) ([]models.ChildAccount, error) {
+	if err := d.checkGrant(ctx); err != nil {
+		return nil, err
+	}
+	return d.AccountService.ListChildren(ctx)
+}
+
+// GetAvailableBalance возвращает доступный баланс, если доверенность еще действует
+func (d *DelegatedAccountService) GetAvailableBalance(ctx context.Context) float64 {
+	if d.checkGrant(ctx) != nil {
+		return 0
+	}
+	return d.AccountService.GetAvailableBalance(ctx)
+}
+
+// ListPendingTransfers возвращает переводы с подтверждением, если доверенность еще действует
+func (d *DelegatedAccountService) ListPendingTransfers(ctx context.Context) []models.PendingTransfer {
+	if d.checkGrant(ctx) != nil {
+		return nil
+	}
+	return d.AccountService.ListPendingTransfers(ctx)
+}
+
+// GetLoanSummary возвращает состояние кредита, если доверенность еще действует
+func (d *DelegatedAccountService) GetLoanSummary(ctx context.Context) (models.LoanSummary, error) {
+	if err := d.checkGrant(ctx); err != nil {
+		return models.LoanSummary{}, err
+	}
+	return d.AccountService.GetLoanSummary(ctx)
+}
+
+// GetLoanSchedule возвращает график платежей, если доверенность еще действует
+func (d *DelegatedAccountService) GetLoanSchedule(ctx context.Context) ([]models.LoanPayment, error) {
+	if err := d.checkGrant(ctx); err != nil {
+		return nil, err
+	}
+	return d.AccountService.GetLoanSchedule(ctx)
+}
+
+// GetReceipt возвращает квитанцию, если доверенность еще действует
+func (d *DelegatedAccountService) GetReceipt(ctx context.Context, transactionID string) (models.Receipt, error) {
+	if err := d.checkGrant(ctx); err != nil {
+		return models.Receipt{}, err
+	}
+	return d.AccountService.GetReceipt(ctx, transactionID)
+}
+
+// EmailStatement отправляет выписку владельцу, если доверенность еще
+// действует; в аудите указывается доверенность
+func (d *DelegatedAccountService) EmailStatement(ctx context.Context, from, to time.Time, html bool) error {
+	if err := d.checkGrant(ctx); err != nil {
+		return err
+	}
+	return d.AccountService.EmailStatement(WithGrant(ctx, d.grantID), from, to, html)
+}
+
+// Deposit недоступен по доверенности
+func (d *DelegatedAccountService) Deposit(ctx context.Context, amount float64, source models.DepositSource) (models.OperationResult, error) {
+	return models.OperationResult{}, errors.ErrAccessDenied
+}
+
+// Withdraw недоступен по доверенности
+func (d *DelegatedAccountService) Withdraw(ctx context.Context, amount float64) (models.OperationResult, error) {
+	return models.OperationResult{}, errors.ErrAccessDenied
+}
+
+// PreviewDeposit недоступен по доверенности
+func (d *DelegatedAccountService) PreviewDeposit(ctx context.Context, amount float64, source models.DepositSource) (models.OperationPreview, error) {
+	return models.OperationPreview{}, errors.ErrAccessDenied
+}
+
+// PreviewWithdraw недоступен по доверенности
+func (d *DelegatedAccountService) PreviewWithdraw(ctx context.Context, amount float64) (models.OperationPreview, error) {
+	return models.OperationPreview{}, errors.ErrAccessDenied
+}
+
+// PreviewTransfer рассчитывает перевод с учетом лимита доверенности
+func (d *DelegatedAccountService) PreviewTransfer(ctx context.Context, to *models.Account, amount float64) (models.OperationPreview, error) {
+	_, grant, err := d.loadGrant(ctx)
+	if err != nil {
+		return models.OperationPreview{}, err
+	}
+
+	if grant.Scope != models.TransferGrant {
+		return models.OperationPreview{}, errors.ErrAccessDenied
+	}
+
+	if grant.Transferred+amount > grant.TransferLimit {
+		return models.OperationPreview{}, errors.ErrGrantLimitExceeded
+	}
+
+	return d.AccountService.PreviewTransfer(ctx, to, amount)
+}
+
+// DepositCash недоступен по доверенности
+func (d *DelegatedAccountService) DepositCash(ctx context.Context, notes []models.CashNote) (models.OperationResult, error) {
+	return models.OperationResult{}, errors.ErrAccessDenied
+}
+
+// InitiateTransfer недоступен по доверенности: лимит учитывается только для Transfer
+func (d *DelegatedAccountService) InitiateTransfer(ctx context.Context, to *models.Account, amount float64) (models.PendingTransfer, error) {
+	return models.PendingTransfer{}, errors.ErrAccessDenied
+}
+
+// ConfirmTransfer недоступен по доверенности
+func (d *DelegatedAccountService) ConfirmTransfer(ctx context.Context, transferID string) (models.OperationResult, error) {
+	return models.OperationResult{}, errors.ErrAccessDenied
+}
+
+// CancelTransfer недоступен по доверенности
+func (d *DelegatedAccountService) CancelTransfer(ctx context.Context, transferID string) error {
+	return errors.ErrAccessDenied
+}
+
+// Reverse недоступен по доверенности
+func (d *DelegatedAccountService) Reverse(ctx context.Context, transactionID string) error {
+	return errors.ErrAccessDenied
+}
+
+// UndoLast недоступен по доверенности
+func (d *DelegatedAccountService) UndoLast(ctx context.Context) (models.Transaction, error) {
+	return models.Transaction{}, errors.ErrAccessDenied
+}
+
+// ChangePIN недоступен по доверенности
+func (d *DelegatedAccountService) ChangePIN(ctx context.Context, oldPIN, newPIN string) error {
+	return errors.ErrAccessDenied
+}
+
+// AttachFile недоступен по доверенности
+func (d *DelegatedAccountService) AttachFile(ctx context.Context, transactionID, name string, data []byte) (models.Attachment, error) {
+	return models.Attachment{}, errors.ErrAccessDenied
+}
+
+// AttachReference недоступен по доверенности
+func (d *DelegatedAccountService) AttachReference(ctx context.Context, transactionID, reference string) (models.Attachment, error) {
+	return models.Attachment{}, errors.ErrAccessDenied
+}
+
+// AcceptCredit недоступен по доверенности
+func (d *DelegatedAccountService) AcceptCredit(ctx context.Context, creditID string) error {
+	return errors.ErrAccessDenied
+}
+
+// RejectCredit недоступен по доверенности
+func (d *DelegatedAccountService) RejectCredit(ctx context.Context, creditID string) error {
+	return errors.ErrAccessDenied
+}
+
+// SetAutoAcceptCredits недоступен по доверенности
+func (d *DelegatedAccountService) SetAutoAcceptCredits(ctx context.Context, enabled bool) error {
+	return errors.ErrAccessDenied
+}
+
+// EditTransactionDetails недоступен по доверенности
+func (d *DelegatedAccountService) EditTransactionDetails(ctx context.Context, transactionID string, details models.TransactionDetails) error {
+	return errors.ErrAccessDenied
+}
+
+// LinkChild недоступен по доверенности
+func (d *DelegatedAccountService) LinkChild(ctx context.Context, childID, childPIN string) error {
+	return errors.ErrAccessDenied
+}
+
+// UnlinkChild недоступен по доверенности
+func (d *DelegatedAccountService) UnlinkChild(ctx context.Context, childID string) error {
+	return errors.ErrAccessDenied
+}
+
+// SetChildControls недоступен по доверенности
+func (d *DelegatedAccountService) SetChildControls(ctx context.Context, childID string, controls models.SpendingControls) error {
+	return errors.ErrAccessDenied
+}
+
+// SetStatementKey недоступен по доверенности
+func (d *DelegatedAccountService) SetStatementKey(ctx context.Context, publicKey []byte) error {
+	return errors.ErrAccessDenied
+}
+
+// SetStatementEmail недоступен по доверенности
+func (d *DelegatedAccountService) SetStatementEmail(ctx context.Context, email string, monthly bool) error {
+	return errors.ErrAccessDenied
+}
+
+// DeliverStatement отправляет выписку владельцу, если доверенность еще
+// действует; в аудите указывается доверенность
+func (d *DelegatedAccountService) DeliverStatement(ctx context.Context, format models.ExportFormat) (models.StatementDelivery, error) {
+	if err := d.checkGrant(ctx); err != nil {
+		return models.StatementDelivery{}, err
+	}
+	return d.AccountService.DeliverStatement(WithGrant(ctx, d.grantID), format)
+}
+
+// ExportStatement выгружает выписку, если доверенность еще действует
+func (d *DelegatedAccountService) ExportStatement(ctx context.Context, format models.ExportFormat, w io.Writer) error {
+	if err := d.checkGrant(ctx); err != nil {
+		return err
+	}
+	return d.AccountService.ExportStatement(WithGrant(ctx, d.grantID), format, w)
+}
+
+// ExportChunk выгружает часть транзакций, если доверенность еще действует
+func (d *DelegatedAccountService) ExportChunk(ctx context.Context, format models.ExportFormat, checkpoint string, limit int, w io.Writer) (models.ExportChunk, error) {
+	if err := d.checkGrant(ctx); err != nil {
+		return models.ExportChunk{}, err
+	}
+	return d.AccountService.ExportChunk(WithGrant(ctx, d.grantID), format, checkpoint, limit, w)
+}
+
+// GrantAccess недоступен по доверенности
+func (d *DelegatedAccountService) GrantAccess(ctx context.Context, username string, scope models.GrantScope, transferLimit float64, expiresAt time.Time) (models.AccessGrant, error) {
+	return models.AccessGrant{}, errors.ErrAccessDenied
+}
+
+// RevokeAccess недоступен по доверенности
+func (d *DelegatedAccountService) RevokeAccess(ctx context.Context, grantID string) error {
+	return errors.ErrAccessDenied
+}
+
+// ListAccessGrants по доверенности не показывает чужие доверенности
+func (d *DelegatedAccountService) ListAccessGrants(ctx context.Context) []models.AccessGrant {
+	return nil
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"bankapp/models"
+)
+
+// manageAccessGrants показывает доверенности на текущий счет и позволяет
+// выдать новую или отозвать действующую
+func (app *BankApp) manageAccessGrants(ctx context.Context) {
+	grants := app.currentAccount.ListAccessGrants(ctx)
+	if len(grants) == 0 {
+		app.println("Действующих доверенностей нет")
+	} else {
+		app.printHeader("Доверенности")
+		for _, grant := range grants {
+			app.printf("%s | %s | %s | переведено %.2f из %.2f | до %s\n",
+				grant.ID, grant.GranteeName, grant.Scope, grant.Transferred, grant.TransferLimit, app.formatTime(grant.ExpiresAt))
+		}
+	}
+
+	app.println("1. Выдать доверенность")
+	app.println("2. Отозвать доверенность")
+	app.println("3. Назад")
+	app.print("Выберите опцию: ")
+
+	app.scanner.Scan()
+	choice := strings.TrimSpace(app.scanner.Text())
+
+	switch choice {
+	case "1":
+		app.grantAccess(ctx)
+	case "2":
+		grantID := app.readLine("Введите ID доверенности: ")
+		if err := app.currentAccount.RevokeAccess(ctx, grantID); err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+		app.println("Доверенность отозвана")
+	case "3":
+		return
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+	}
+}
+
+// grantAccess выдает доверенность другому пользователю
+func (app *BankApp) grantAccess(ctx context.Context) {
+	username := app.readLine("Имя пользователя: ")
+
+	scope := models.GrantScope(strings.ToUpper(app.readLine("Объем доступа (VIEW - просмотр, TRANSFER - и переводы): ")))
+
+	var limit float64
+	if scope == models.TransferGrant {
+		var err error
+		limit, err = app.readAmount("Общий лимит переводов: ")
+		if err != nil {
+			return
+		}
+	}
+
+	days, err := strconv.Atoi(app.readLine("Срок действия в днях: "))
+	if err != nil || days <= 0 {
+		app.println("Некорректный срок действия")
+		return
+	}
+
+	grant, err := app.currentAccount.GrantAccess(ctx, username, scope, limit, app.clock.Now().AddDate(0, 0, days))
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Доверенность %s выдана пользователю %s до %s\n", grant.ID, grant.GranteeName, app.formatTime(grant.ExpiresAt))
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"time"
+)
+
+// ArchiveDormantAccounts переносит в архив счета без клиентских операций
+// дольше policy.InactiveDays дней. Архивируются только счета с нулевым
+// остатком и без незавершенных операций, остальные попадают в отчет с
+// причиной пропуска. Счет в архиве скрыт из списков, но хранится целиком
+// и возвращается UnarchiveAccount. В режиме dryRun счета не изменяются.
+func (s *AdminServiceImpl) ArchiveDormantAccounts(ctx context.Context, policy models.ArchivePolicy, dryRun bool) (report models.ArchiveReport, err error) {
+	report = models.ArchiveReport{InactiveDays: policy.InactiveDays, DryRun: dryRun}
+
+	if policy.InactiveDays <= 0 {
+		return report, errors.ErrArchiveNotConfigured
+	}
+
+	accounts, _, err := s.storage.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return report, err
+	}
+
+	now := s.clock.Now()
+	inactiveSince := now.AddDate(0, 0, -policy.InactiveDays)
+	for _, account := range accounts {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		if account.Archived() {
+			continue
+		}
+
+		last := lastActivity(account)
+		if last.IsZero() {
+			last = account.CreatedAt
+		}
+		if last.After(inactiveSince) {
+			continue
+		}
+
+		result := models.ArchiveResult{
+			AccountID:    account.ID,
+			OwnerName:    account.OwnerName,
+			LastActivity: last,
+		}
+		switch {
+		case account.Balance != 0:
+			result.SkipReason = "на счете остались средства или задолженность"
+		case hasUnfinishedOperations(account, now):
+			result.SkipReason = "есть незавершенные операции"
+		}
+
+		if result.SkipReason != "" {
+			report.Results = append(report.Results, result)
+			report.Skipped++
+			continue
+		}
+
+		if !dryRun {
+			account.ArchivedAt = now
+			err := s.storage.SaveAccount(ctx, account)
+			s.auditAdmin(ctx, "archive_account", account.ID, 0, "", err)
+			if err != nil {
+				account.ArchivedAt = time.Time{}
+				return report, err
+			}
+		}
+
+		result.Archived = true
+		report.Results = append(report.Results, result)
+		report.Archived++
+	}
+
+	return report, nil
+}
+
+// ListArchivedAccounts возвращает счета, перенесенные в архив
+func (s *AdminServiceImpl) ListArchivedAccounts(ctx context.Context) ([]*models.Account, error) {
+	archived, _, err := s.storage.ListAccountsByScope(ctx, models.ArchivedAccounts, 0, 0)
+	return archived, err
+}
+
+// UnarchiveAccount возвращает счет из архива в обычные списки
+func (s *AdminServiceImpl) UnarchiveAccount(ctx context.Context, accountID string) (err error) {
+	defer func() {
+		s.auditAdmin(ctx, "unarchive_account", accountID, 0, "", err)
+	}()
+
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	if !account.Archived() {
+		return errors.ErrNotArchived
+	}
+
+	account.ArchivedAt = time.Time{}
+	return s.storage.SaveAccount(ctx, account)
+}
+
+// hasUnfinishedOperations сообщает, есть ли на счете удержания, входящие
+// платежи или купюры, ожидающие решения
+func hasUnfinishedOperations(account *models.Account, now time.Time) bool {
+	for _, transfer := range account.PendingTransfers {
+		if transfer.Active(now) {
+			return true
+		}
+	}
+	for _, credit := range account.PendingCredits {
+		if credit.Active(now) {
+			return true
+		}
+	}
+	for _, hold := range account.CashHolds {
+		if hold.Status == models.PendingHoldStatus {
+			return true
+		}
+	}
+
+	return false
+}
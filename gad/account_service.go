@@ -1,150 +1,668 @@
-package services
-
-import (
-	"bankapp/errors"
-	"bankapp/interfaces"
-	"bankapp/models"
-	"fmt"
-	"strings"
-	"time"
-)
-
-// AccountServiceImpl реализация AccountService
-type AccountServiceImpl struct {
-	account *models.Account
-	storage interfaces.Storage
-}
-
-// NewAccountService создает новый сервис для работы со счетом
-func NewAccountService(account *models.Account, storage interfaces.Storage) interfaces.AccountService {
-	return &AccountServiceImpl{
-		account: account,
-		storage: storage,
-	}
-}
-
-// Deposit пополнение счета
-func (s *AccountServiceImpl) Deposit(amount float64) error {
-	if amount <= 0 {
-		return errors.ErrInvalidAmount
-	}
-
-	s.account.Balance += amount
-
-	transaction := models.Transaction{
-		ID:        fmt.Sprintf("TX%d", time.Now().UnixNano()),
-		Type:      models.DepositTransaction,
-		Amount:    amount,
-		Timestamp: time.Now(),
-		Message:   fmt.Sprintf("Пополнение счета на %.2f", amount),
-	}
-
-	s.account.Transactions = append(s.account.Transactions, transaction)
-
-	return s.storage.SaveAccount(s.account)
-}
-
-// Withdraw снятие средств
-func (s *AccountServiceImpl) Withdraw(amount float64) error {
-	if amount <= 0 {
-		return errors.ErrInvalidAmount
-	}
-
-	if s.account.Balance < amount {
-		return errors.ErrInsufficientFunds
-	}
-
-	s.account.Balance -= amount
-
-	transaction := models.Transaction{
-		ID:        fmt.Sprintf("TX%d", time.Now().UnixNano()),
-		Type:      models.WithdrawTransaction,
-		Amount:    amount,
-		Timestamp: time.Now(),
-		Message:   fmt.Sprintf("Снятие средств на %.2f", amount),
-	}
-
-	s.account.Transactions = append(s.account.Transactions, transaction)
-
-	return s.storage.SaveAccount(s.account)
-}
-
-// Transfer перевод другому счету
-func (s *AccountServiceImpl) Transfer(to *models.Account, amount float64) error {
-	if amount <= 0 {
-		return errors.ErrInvalidAmount
-	}
-
-	if s.account.Balance < amount {
-		return errors.ErrInsufficientFunds
-	}
-
-	if s.account.ID == to.ID {
-		return errors.ErrSameAccountTransfer
-	}
-
-	// Снимаем средства с текущего счета
-	s.account.Balance -= amount
-
-	transaction := models.Transaction{
-		ID:        fmt.Sprintf("TX%d", time.Now().UnixNano()),
-		Type:      models.TransferTransaction,
-		Amount:    amount,
-		Timestamp: time.Now(),
-		Message:   fmt.Sprintf("Перевод счету %s на %.2f", to.ID, amount),
-	}
-
-	s.account.Transactions = append(s.account.Transactions, transaction)
-
-	// Зачисляем средства на целевой счет
-	to.Balance += amount
-
-	toTransaction := models.Transaction{
-		ID:        fmt.Sprintf("TX%d", time.Now().UnixNano()),
-		Type:      models.TransferTransaction,
-		Amount:    amount,
-		Timestamp: time.Now(),
-		Message:   fmt.Sprintf("Перевод от счета %s на %.2f", s.account.ID, amount),
-	}
-
-	to.Transactions = append(to.Transactions, toTransaction)
-
-	// Сохраняем оба счета
-	if err := s.storage.SaveAccount(s.account); err != nil {
-		return err
-	}
-
-	return s.storage.SaveAccount(to)
-}
-
-// GetBalance получение баланса
-func (s *AccountServiceImpl) GetBalance() float64 {
-	return s.account.Balance
-}
-
-// GetStatement получение выписки
-func (s *AccountServiceImpl) GetStatement() string {
-	if len(s.account.Transactions) == 0 {
-		return "История транзакций пуста"
-	}
-
-	var sb strings.Builder
-	sb.WriteString("Выписка по счету:\n")
-	sb.WriteString("========================================\n")
-	sb.WriteString(fmt.Sprintf("Владелец: %s\n", s.account.OwnerName))
-	sb.WriteString(fmt.Sprintf("ID счета: %s\n", s.account.ID))
-	sb.WriteString("========================================\n")
-
-	for _, tx := range s.account.Transactions {
-		sb.WriteString(fmt.Sprintf("%s | %s | %.2f | %s\n",
-			tx.Timestamp.Format("2006-01-02 15:04:05"),
-			tx.Type,
-			tx.Amount,
-			tx.Message))
-	}
-
-	sb.WriteString("========================================\n")
-	sb.WriteString(fmt.Sprintf("Текущий баланс: %.2f\n", s.account.Balance))
-
-	return sb.String()
-}
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/i18n"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// AccountServiceImpl реализация AccountService
+type AccountServiceImpl struct {
+	account *models.Account
+	storage interfaces.Storage
+	ledger  interfaces.LedgerStorage
+	blobs   interfaces.BlobStore
+
+	riskScorer interfaces.RiskScorer
+	riskPolicy RiskPolicy
+	feePolicy  interfaces.FeePolicy
+	ids        models.IDGenerator
+	logger     *slog.Logger
+	audit      interfaces.AuditLogger
+	events     *EventBus
+	dateFormat models.DateFormat
+	tr         *i18n.Translator
+	limitRules []conditionRule
+	statements interfaces.StatementSender
+	types      *TransactionTypeRegistry
+	switches   interfaces.OperationSwitches
+	receiptKey []byte
+	mailer     *StatementMailer
+	undoWindow time.Duration
+	clock      models.Clock
+	renderer   interfaces.StatementRenderer
+
+	// queued события журнала текущей операции, ожидающие сохранения счета
+	queued []models.AccountEvent
+}
+
+// AccountOption настройка сервиса счета
+type AccountOption func(*AccountServiceImpl)
+
+// NewAccountService создает новый сервис для работы со счетом
+func NewAccountService(account *models.Account, storage interfaces.Storage, ledger interfaces.LedgerStorage, opts ...AccountOption) interfaces.AccountService {
+	s := &AccountServiceImpl{
+		account: account,
+		storage: storage,
+		ledger:  ledger,
+		clock:   models.DefaultClock,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithIDGenerator задает генератор идентификаторов транзакций
+func WithIDGenerator(ids models.IDGenerator) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.ids = ids
+	}
+}
+
+// WithClock задает часы, по которым проставляется время операций
+func WithClock(clock models.Clock) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.clock = clock
+	}
+}
+
+// WithDateFormat задает формат дат в выписке
+func WithDateFormat(format models.DateFormat) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.dateFormat = format
+	}
+}
+
+// WithTranslator задает язык выписок
+func WithTranslator(tr *i18n.Translator) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.tr = tr
+	}
+}
+
+// maxSaveAttempts сколько раз операция выполняется при конфликтах версий счета
+const maxSaveAttempts = 3
+
+// checkVersion сверяет версию счета с сохраненной, чтобы конфликт обнаруживался
+// до записи событий в журнал, а не при сохранении счета
+func (s *AccountServiceImpl) checkVersion(ctx context.Context) error {
+	stored, err := s.storage.LoadAccount(ctx, s.account.ID)
+	if err != nil {
+		return err
+	}
+
+	if stored.Version != s.account.Version {
+		return errors.ErrConcurrentModification
+	}
+
+	return nil
+}
+
+// retryOnConflict выполняет операцию и при конфликте версий перечитывает счет
+// из хранилища и повторяет ее
+func (s *AccountServiceImpl) retryOnConflict(ctx context.Context, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		s.queued = nil
+		err := op()
+		if !stderrors.Is(err, errors.ErrConcurrentModification) || attempt == maxSaveAttempts {
+			return err
+		}
+
+		account, loadErr := s.storage.LoadAccount(ctx, s.account.ID)
+		if loadErr != nil {
+			return loadErr
+		}
+		s.account = account
+	}
+}
+
+// saveAccounts сохраняет счета атомарно через BatchStorage, а если
+// хранилище его не реализует - по одному
+func saveAccounts(ctx context.Context, storage interfaces.Storage, accounts ...*models.Account) error {
+	if batch, ok := storage.(interfaces.BatchStorage); ok {
+		return batch.SaveAccounts(ctx, accounts...)
+	}
+
+	for _, account := range accounts {
+		if err := storage.SaveAccount(ctx, account); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveAccount сохраняет счет сервиса вместе со счетами others и после
+// этого записывает в журнал события операции. Если запись не удалась,
+// события отбрасываются, а счет перечитывается из хранилища: несохраненные
+// изменения не остаются в сервисе, а после конфликта версий следующая
+// операция идет по свежей копии.
+func (s *AccountServiceImpl) saveAccount(ctx context.Context, others ...*models.Account) error {
+	events := s.queued
+	s.queued = nil
+
+	if err := saveAccounts(ctx, s.storage, append([]*models.Account{s.account}, others...)...); err != nil {
+		if account, loadErr := s.storage.LoadAccount(ctx, s.account.ID); loadErr == nil {
+			s.account = account
+		}
+		return err
+	}
+
+	s.recordQueued(ctx, events)
+	return nil
+}
+
+// newID генерирует идентификатор; без заданного генератора используется генератор по умолчанию
+func (s *AccountServiceImpl) newID(prefix string) string {
+	if s.ids == nil {
+		return models.DefaultIDGenerator.NewID(prefix)
+	}
+
+	return s.ids.NewID(prefix)
+}
+
+// now возвращает текущее время по часам сервиса
+func (s *AccountServiceImpl) now() time.Time {
+	if s.clock == nil {
+		return models.DefaultClock.Now()
+	}
+
+	return s.clock.Now()
+}
+
+// Deposit пополнение счета из указанного источника
+func (s *AccountServiceImpl) Deposit(ctx context.Context, amount float64, source models.DepositSource) (result models.OperationResult, err error) {
+	defer func() {
+		s.logOperation(ctx, "deposit", amount, err, slog.String("source", string(source)))
+		s.auditOperation(ctx, "deposit", amount, string(source), err)
+		if err == nil {
+			s.publish(ctx, models.DepositedNotification, result.TransactionID, amount, "")
+		}
+	}()
+
+	err = s.retryOnConflict(ctx, func() error {
+		result, err = s.deposit(ctx, amount, source, nil)
+		return err
+	})
+
+	return result, err
+}
+
+// deposit проводит пополнение; Deposit повторяет его при конфликте версий.
+// onPost, если задан, дополняет счет после проводки, как и в transfer.
+func (s *AccountServiceImpl) deposit(ctx context.Context, amount float64, source models.DepositSource, onPost func(models.Transaction)) (models.OperationResult, error) {
+	if err := ctx.Err(); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkVersion(ctx); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := checkOperable(s.account); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkSwitch(ctx, models.DepositTransaction); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if amount <= 0 {
+		return models.OperationResult{}, errors.ErrInvalidAmount
+	}
+
+	if !source.Valid() {
+		return models.OperationResult{}, errors.ErrInvalidDepositSource
+	}
+
+	details, err := transactionDetails(ctx)
+	if err != nil {
+		return models.OperationResult{}, err
+	}
+
+	fee, err := s.calculateFee(ctx, models.DepositTransaction, amount)
+	if err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkLimitRules(models.DepositTransaction, amount, ""); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	score, review, err := s.assessRisk(ctx, models.DepositTransaction, amount, "")
+	if err != nil {
+		return models.OperationResult{}, err
+	}
+
+	transaction := models.Transaction{
+		ID:          s.newID("TX"),
+		Type:        models.DepositTransaction,
+		Amount:      amount,
+		Timestamp:   s.now(),
+		Message:     fmt.Sprintf("Пополнение счета на %.2f", amount),
+		Direction:   models.CreditEntry,
+		Source:      source,
+		RiskScore:   score,
+		UnderReview: review,
+	}
+	setDetails(&transaction, details)
+
+	s.queueEvent(s.account.ID, models.DepositEvent, amount, transaction.ID, transaction.Timestamp)
+	s.account.Balance += amount
+	s.account.Transactions = append(s.account.Transactions, transaction)
+	s.chargeFee(fee, transaction.ID)
+	if onPost != nil {
+		onPost(transaction)
+	}
+
+	if err := s.saveAccount(ctx); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	return s.operationResult(transaction, fee), nil
+}
+
+// Withdraw снятие средств
+func (s *AccountServiceImpl) Withdraw(ctx context.Context, amount float64) (result models.OperationResult, err error) {
+	defer func() {
+		s.logOperation(ctx, "withdraw", amount, err)
+		s.auditOperation(ctx, "withdraw", amount, "", err)
+		if err == nil {
+			s.publish(ctx, models.WithdrawnNotification, result.TransactionID, amount, "")
+		}
+	}()
+
+	err = s.retryOnConflict(ctx, func() error {
+		result, err = s.withdraw(ctx, amount)
+		return err
+	})
+
+	return result, err
+}
+
+// withdraw проводит снятие; Withdraw повторяет его при конфликте версий
+func (s *AccountServiceImpl) withdraw(ctx context.Context, amount float64) (models.OperationResult, error) {
+	if err := ctx.Err(); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkVersion(ctx); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := checkOperable(s.account); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkSwitch(ctx, models.WithdrawTransaction); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if amount <= 0 {
+		return models.OperationResult{}, errors.ErrInvalidAmount
+	}
+
+	details, err := transactionDetails(ctx)
+	if err != nil {
+		return models.OperationResult{}, err
+	}
+
+	fee, err := s.calculateFee(ctx, models.WithdrawTransaction, amount)
+	if err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkFunds(amount + fee); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkDailyLimits(amount); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkSpendingControls(ctx, amount, details); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkLimitRules(models.WithdrawTransaction, amount, ""); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	score, review, err := s.assessRisk(ctx, models.WithdrawTransaction, amount, "")
+	if err != nil {
+		return models.OperationResult{}, err
+	}
+
+	transaction := models.Transaction{
+		ID:          s.newID("TX"),
+		Type:        models.WithdrawTransaction,
+		Amount:      amount,
+		Timestamp:   s.now(),
+		Message:     fmt.Sprintf("Снятие средств на %.2f", amount),
+		Direction:   models.DebitEntry,
+		RiskScore:   score,
+		UnderReview: review,
+	}
+	setDetails(&transaction, details)
+
+	s.queueEvent(s.account.ID, models.WithdrawEvent, amount, transaction.ID, transaction.Timestamp)
+	s.account.Balance -= amount
+	s.account.Transactions = append(s.account.Transactions, transaction)
+	s.chargeFee(fee, transaction.ID)
+
+	if err := s.saveAccount(ctx); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	return s.operationResult(transaction, fee), nil
+}
+
+// Transfer перевод другому счету
+func (s *AccountServiceImpl) Transfer(ctx context.Context, to *models.Account, amount float64) (result models.OperationResult, err error) {
+	defer func() {
+		s.logOperation(ctx, "transfer", amount, err, slog.String("to_account_id", to.ID))
+		s.auditOperation(ctx, "transfer", amount, "получатель "+to.ID, err)
+		if err == nil {
+			s.publish(ctx, models.TransferCompletedNotification, result.TransactionID, amount, to.ID)
+		}
+	}()
+
+	err = s.retryOnConflict(ctx, func() error {
+		result, err = s.transfer(ctx, to, amount, nil)
+		return err
+	})
+
+	return result, err
+}
+
+// transfer проводит перевод; Transfer повторяет его при конфликте версий.
+// onPost, если задан, дополняет счет после проводки перевода, и эти
+// изменения сохраняются той же записью, что и перевод.
+func (s *AccountServiceImpl) transfer(ctx context.Context, to *models.Account, amount float64, onPost func(models.Transaction)) (models.OperationResult, error) {
+	if err := ctx.Err(); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkVersion(ctx); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkSwitch(ctx, models.TransferTransaction); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	// Переданная копия счета получателя могла устареть, работаем с сохраненной
+	to, err := s.storage.LoadAccount(ctx, to.ID)
+	if err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if amount <= 0 {
+		return models.OperationResult{}, errors.ErrInvalidAmount
+	}
+
+	details, err := transactionDetails(ctx)
+	if err != nil {
+		return models.OperationResult{}, err
+	}
+
+	fee, err := s.calculateFee(ctx, models.TransferTransaction, amount)
+	if err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkFunds(amount + fee); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkDailyLimits(amount); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkSpendingControls(ctx, amount, details); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if s.account.ID == to.ID {
+		return models.OperationResult{}, errors.ErrSameAccountTransfer
+	}
+
+	if err := checkOperable(s.account); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := checkOperable(to); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.checkLimitRules(models.TransferTransaction, amount, to.ID); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	score, review, err := s.assessRisk(ctx, models.TransferTransaction, amount, to.ID)
+	if err != nil {
+		return models.OperationResult{}, err
+	}
+
+	if err := s.chargeGrant(ctx, amount); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	transaction := s.postTransfer(to, amount, score, review)
+
+	// Разметка пользователя относится только к его ноге перевода
+	if tx, err := s.findTransaction(transaction.ID); err == nil {
+		setDetails(tx, details)
+	}
+
+	s.chargeFee(fee, transaction.ID)
+	if onPost != nil {
+		onPost(transaction)
+	}
+
+	// Сохраняем оба счета, одной записью, если хранилище это поддерживает
+	if err := s.saveAccount(ctx, to); err != nil {
+		return models.OperationResult{}, err
+	}
+
+	return s.operationResult(transaction, fee), nil
+}
+
+// postTransfer проводит обе ноги перевода на счет to и возвращает дебетовую ногу
+func (s *AccountServiceImpl) postTransfer(to *models.Account, amount, score float64, review bool) models.Transaction {
+	// Обе ноги перевода связаны общим TransferID
+	transferID := s.newID("TR")
+
+	// Снимаем средства с текущего счета
+	transaction := models.Transaction{
+		ID:             s.newID("TX"),
+		Type:           models.TransferTransaction,
+		Amount:         amount,
+		Timestamp:      s.now(),
+		Message:        fmt.Sprintf("Перевод счету %s на %.2f", to.ID, amount),
+		Direction:      models.DebitEntry,
+		TransferID:     transferID,
+		CounterpartyID: to.ID,
+		RiskScore:      score,
+		UnderReview:    review,
+	}
+
+	s.queueEvent(s.account.ID, models.TransferOutEvent, amount, transaction.ID, transaction.Timestamp)
+	s.account.Balance -= amount
+	s.account.Transactions = append(s.account.Transactions, transaction)
+
+	// Зачисляем средства на целевой счет
+	toTransaction := models.Transaction{
+		ID:             s.newID("TX"),
+		Type:           models.TransferTransaction,
+		Amount:         amount,
+		Timestamp:      s.now(),
+		Message:        fmt.Sprintf("Перевод от счета %s на %.2f", s.account.ID, amount),
+		Direction:      models.CreditEntry,
+		TransferID:     transferID,
+		CounterpartyID: s.account.ID,
+	}
+
+	s.queueEvent(to.ID, models.TransferInEvent, amount, toTransaction.ID, toTransaction.Timestamp)
+	to.Balance += amount
+	to.Transactions = append(to.Transactions, toTransaction)
+
+	return transaction
+}
+
+// operationResult формирует квитанцию по проведенной операции
+func (s *AccountServiceImpl) operationResult(transaction models.Transaction, fee float64) models.OperationResult {
+	result := models.OperationResult{
+		TransactionID: transaction.ID,
+		TransferID:    transaction.TransferID,
+		Fee:           fee,
+		Balance:       s.account.Balance,
+		ValueDate:     transaction.Timestamp,
+		UnderReview:   transaction.UnderReview,
+	}
+	if receipt, err := s.buildReceipt(transaction.ID); err == nil {
+		result.ReceiptCode = receipt.Code
+	}
+
+	return result
+}
+
+// checkFunds проверяет, что списание не выводит баланс за пределы лимита овердрафта
+func (s *AccountServiceImpl) checkFunds(amount float64) error {
+	if s.account.AvailableFundsAt(s.now()) >= amount {
+		return nil
+	}
+
+	if s.account.OverdraftLimit > 0 {
+		return errors.ErrOverdraftExceeded
+	}
+
+	return errors.ErrInsufficientFunds
+}
+
+// AccountID возвращает ID счета сервиса
+func (s *AccountServiceImpl) AccountID() string {
+	return s.account.ID
+}
+
+// GetBalance получение баланса
+func (s *AccountServiceImpl) GetBalance(ctx context.Context) float64 {
+	return s.account.Balance
+}
+
+// GetBalanceAt возвращает баланс счета на момент at по журналу событий
+func (s *AccountServiceImpl) GetBalanceAt(ctx context.Context, at time.Time) (float64, error) {
+	return GetBalanceAt(ctx, s.ledger, s.account.ID, at)
+}
+
+// GetBalanceHistory возвращает балансы счета на конец каждого дня периода [from, to)
+func (s *AccountServiceImpl) GetBalanceHistory(ctx context.Context, from, to time.Time) ([]models.BalancePoint, error) {
+	return GetBalanceHistory(ctx, s.ledger, s.account.ID, from, to)
+}
+
+// ListTransactions получение страницы истории транзакций и их общего количества
+func (s *AccountServiceImpl) ListTransactions(ctx context.Context, offset, limit int) ([]models.Transaction, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	start, end := models.PageBounds(len(s.account.Transactions), offset, limit)
+	page := make([]models.Transaction, end-start)
+	copy(page, s.account.Transactions[start:end])
+
+	return page, len(s.account.Transactions), nil
+}
+
+// attachmentLabel описание вложения для выписки
+func attachmentLabel(tr *i18n.Translator, attachment models.Attachment) string {
+	if attachment.Reference != "" {
+		return attachment.Reference
+	}
+
+	return tr.Sprintf("%s (%d байт)", attachment.Name, attachment.Size)
+}
+
+// GetStatement получение выписки. С подключенным оформлением выписка за
+// всю историю счета выводится по его шаблону.
+func (s *AccountServiceImpl) GetStatement(ctx context.Context) string {
+	if len(s.account.Transactions) == 0 {
+		return s.tr.T("История транзакций пуста")
+	}
+
+	if s.renderer != nil {
+		return s.renderStatement(ctx)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(s.tr.T("Выписка по счету:\n"))
+	sb.WriteString("========================================\n")
+	sb.WriteString(s.tr.Sprintf("Владелец: %s\n", s.account.OwnerName))
+	sb.WriteString(s.tr.Sprintf("ID счета: %s\n", s.account.ID))
+	sb.WriteString("========================================\n")
+
+	for _, tx := range s.account.Transactions {
+		sb.WriteString(fmt.Sprintf("%s | %s | %.2f | %s",
+			tx.Timestamp.Format(s.dateFormat.Layout()),
+			s.types.Name(tx.Type),
+			tx.Amount,
+			tx.Message))
+		if note := transactionAnnotation(s.tr, tx); note != "" {
+			sb.WriteString(" [" + note + "]")
+		}
+		sb.WriteString("\n")
+
+		if tx.Category != "" || len(tx.Tags) > 0 {
+			sb.WriteString(s.tr.Sprintf("    категория: %s, теги: %s\n", tx.Category, strings.Join(tx.Tags, " ")))
+		}
+		if tx.Note != "" {
+			sb.WriteString(s.tr.Sprintf("    заметка: %s\n", tx.Note))
+		}
+
+		for _, attachment := range tx.Attachments {
+			sb.WriteString(s.tr.Sprintf("    вложение %s: %s\n", attachment.ID, attachmentLabel(s.tr, attachment)))
+		}
+	}
+
+	if groups := s.statementGroups(); len(groups) > 0 {
+		sb.WriteString(s.tr.T("Итоги по группам:\n"))
+		for _, group := range groups {
+			sb.WriteString(fmt.Sprintf("    %s: %+.2f\n", group.name, group.total))
+		}
+	}
+
+	sb.WriteString("========================================\n")
+	sb.WriteString(s.tr.Sprintf("Текущий баланс: %.2f\n", s.account.Balance))
+
+	return sb.String()
+}
+
+// GetMiniStatement краткая выписка в стиле банкомата: последние count
+// операций по одной строке и текущий баланс
+func (s *AccountServiceImpl) GetMiniStatement(ctx context.Context, count int) string {
+	transactions := s.account.Transactions
+	if count > 0 && len(transactions) > count {
+		transactions = transactions[len(transactions)-count:]
+	}
+
+	var sb strings.Builder
+	sb.WriteString(s.tr.Sprintf("Мини-выписка %s\n", s.account.ID))
+	for _, tx := range transactions {
+		sb.WriteString(fmt.Sprintf("%s %+10.2f %s\n",
+			tx.Timestamp.Format("02.01 15:04"),
+			tx.SignedAmount(),
+			s.types.Name(tx.Type)))
+	}
+	sb.WriteString(s.tr.Sprintf("Баланс: %.2f\n", s.account.Balance))
+
+	return sb.String()
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// checkOperable проверяет, что по счету разрешены операции
+func checkOperable(account *models.Account) error {
+	if account.Archived() {
+		return errors.ErrAccountArchived
+	}
+
+	switch account.Status {
+	case models.ClosedStatus:
+		return errors.ErrAccountClosed
+	case models.FrozenStatus:
+		return errors.ErrAccountFrozen
+	default:
+		return nil
+	}
+}
+
+// changeStatus меняет статус счета и фиксирует переход служебной транзакцией на момент at
+func changeStatus(ids models.IDGenerator, account *models.Account, status models.AccountStatus, reason string, at time.Time) {
+	if account.Status == status {
+		return
+	}
+
+	account.Transactions = append(account.Transactions, models.Transaction{
+		ID:        ids.NewID("TX"),
+		Type:      models.StatusTransaction,
+		Timestamp: at,
+		Message:   fmt.Sprintf("Статус счета изменен: %s -> %s (%s)", account.Status, status, reason),
+	})
+	account.Status = status
+}
+
+// CloseAccount закрывает счет. Положительный остаток переводится на счет
+// transferTo; без него закрыть можно только счет с нулевым балансом.
+// Счет с задолженностью закрыть нельзя.
+func (s *AdminServiceImpl) CloseAccount(ctx context.Context, accountID, transferTo string) (err error) {
+	defer func() {
+		s.auditAdmin(ctx, "close", accountID, 0, transferTo, err)
+	}()
+
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	if account.Status == models.ClosedStatus {
+		return errors.ErrAccountClosed
+	}
+
+	if account.Balance < 0 || (account.Balance > 0 && transferTo == "") {
+		return errors.ErrNonZeroBalance
+	}
+
+	service := &AccountServiceImpl{
+		account: account,
+		storage: s.storage,
+		ledger:  s.ledger,
+		ids:     s.ids,
+		clock:   s.clock,
+	}
+
+	var others []*models.Account
+	if account.Balance > 0 {
+		to, err := s.storage.LoadAccount(ctx, transferTo)
+		if err != nil {
+			return err
+		}
+
+		if to.ID == account.ID {
+			return errors.ErrSameAccountTransfer
+		}
+
+		if err := checkOperable(to); err != nil {
+			return err
+		}
+
+		service.postTransfer(to, account.Balance, 0, false)
+		others = append(others, to)
+	}
+
+	changeStatus(s.ids, account, models.ClosedStatus, "счет закрыт", s.clock.Now())
+
+	// Остаток и закрытие сохраняются вместе с зачислением на счет transferTo
+	return service.saveAccount(ctx, others...)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"slices"
+	"sort"
+)
+
+// timelineActions виды событий хронологии для действий журнала аудита;
+// остальные действия попадают в хронологию как AuditTimeline
+var timelineActions = map[string]models.TimelineKind{
+	"freeze":              models.StatusTimeline,
+	"unfreeze":            models.StatusTimeline,
+	"close":               models.StatusTimeline,
+	"set_overdraft_limit": models.LimitTimeline,
+	"set_daily_limits":    models.LimitTimeline,
+	"set_child_controls":  models.LimitTimeline,
+	"reverse":             models.DisputeTimeline,
+	"cash_hold":           models.DisputeTimeline,
+	"resolve_cash_hold":   models.DisputeTimeline,
+}
+
+// timelineTransactions виды событий хронологии для служебных транзакций;
+// остальные транзакции попадают в хронологию как TransactionTimeline
+var timelineTransactions = map[models.TransactionType]models.TimelineKind{
+	models.StatusTransaction:         models.StatusTimeline,
+	models.OverdraftLimitTransaction: models.LimitTimeline,
+	models.ReversalTransaction:       models.DisputeTimeline,
+}
+
+// postedActions действия, успешные записи которых уже видны
+// в хронологии как транзакции
+var postedActions = map[string]bool{
+	"deposit":             true,
+	"withdraw":            true,
+	"transfer":            true,
+	"freeze":              true,
+	"unfreeze":            true,
+	"close":               true,
+	"set_overdraft_limit": true,
+	"reverse":             true,
+}
+
+// GetAccountTimeline собирает транзакции счета и записи журнала аудита по нему
+// в одну хронологию: смены статуса, изменения лимитов, спорные операции
+// (сторно и задержанные купюры) и прочие действия. Если kinds не заданы,
+// возвращаются события всех видов.
+func (s *AdminServiceImpl) GetAccountTimeline(ctx context.Context, accountID string, kinds ...models.TimelineKind) ([]models.TimelineEntry, error) {
+	for _, kind := range kinds {
+		if !kind.Valid() {
+			return nil, errors.ErrInvalidTimelineKind
+		}
+	}
+
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	include := func(kind models.TimelineKind) bool {
+		return len(kinds) == 0 || slices.Contains(kinds, kind)
+	}
+
+	var timeline []models.TimelineEntry
+	for _, tx := range account.Transactions {
+		kind, ok := timelineTransactions[tx.Type]
+		if !ok {
+			kind = models.TransactionTimeline
+		}
+
+		if include(kind) {
+			timeline = append(timeline, models.TimelineEntry{
+				Timestamp: tx.Timestamp,
+				Kind:      kind,
+				Action:    string(tx.Type),
+				Amount:    tx.Amount,
+				Details:   tx.Message,
+				Reference: tx.ID,
+			})
+		}
+	}
+
+	if s.audit != nil {
+		entries, _, err := s.audit.Query(ctx, models.AuditFilter{AccountID: accountID})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.Success && postedActions[entry.Action] {
+				continue
+			}
+
+			kind, ok := timelineActions[entry.Action]
+			if !ok {
+				kind = models.AuditTimeline
+			}
+
+			if include(kind) {
+				timeline = append(timeline, models.TimelineEntry{
+					Timestamp: entry.Timestamp,
+					Kind:      kind,
+					Actor:     entry.Actor,
+					Action:    entry.Action,
+					Amount:    entry.Amount,
+					Details:   entry.Details,
+					Error:     entry.Error,
+					Reference: entry.CorrelationID,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Timestamp.Before(timeline[j].Timestamp)
+	})
+
+	return timeline, nil
+}
@@ -0,0 +1,1114 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"bankapp/errors"
+	"bankapp/models"
+	"bankapp/services"
+	"bankapp/storage"
+)
+
+// showLoginMenu показывает меню входа и регистрации
+func (app *BankApp) showLoginMenu(ctx context.Context) {
+	app.printHeader("Вход")
+	app.println("1. Войти")
+	app.println("2. Зарегистрироваться")
+	app.println("3. Выйти")
+	app.print("Выберите опцию: ")
+
+	app.scanner.Scan()
+	choice := app.scanner.Text()
+
+	switch choice {
+	case "1":
+		app.login(ctx)
+	case "2":
+		app.register(ctx)
+	case "3":
+		app.stop()
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+	}
+}
+
+// showAdminMenu показывает меню администратора
+func (app *BankApp) showAdminMenu(ctx context.Context) {
+	app.printHeader("Меню администратора")
+	app.println("1. Показать все счета")
+	app.println("2. Поиск транзакций")
+	app.println("3. Заморозить счет")
+	app.println("4. Разморозить счет")
+	app.println("5. Пересобрать балансы из журнала событий")
+	app.println("6. Сверка проводок")
+	app.println("7. Установить лимит овердрафта")
+	app.println("8. Баланс счета на дату")
+	app.println("9. Установить дневные лимиты")
+	app.println("10. Заметки и менеджер счета")
+	app.println("11. Списать плату за обслуживание")
+	app.println("12. Сторнировать транзакцию")
+	app.println("13. Закрыть счет")
+	app.println("14. Кассовый отчет за день")
+	app.println("15. Зарегистрировать входящий внешний платеж")
+	app.println("16. Журнал аудита")
+	app.println("17. Пересчет комиссий за период")
+	app.println("18. Импорт счетов из CSV")
+	app.println("19. Повторная доставка вебхуков")
+	app.println("20. Проверка подозрительных купюр")
+	app.println("21. Перевод остатков неактивных счетов")
+	app.println("22. Хронология счета")
+	app.println("23. Операция пользовательского типа")
+	app.println("24. Операции на проверке")
+	app.println("25. Приостановка операций")
+	app.println("26. Кредиты")
+	app.println("27. Резервная копия")
+	app.println("28. Проверка целостности истории")
+	app.println("29. Архив счетов")
+	app.println("30. Настройки")
+	app.println("31. Проводка по счетам")
+	app.println("32. Выгрузка журнала аудита")
+	app.println("33. Выйти из профиля")
+	app.println("34. Выйти")
+	app.print("Выберите опцию: ")
+
+	app.scanner.Scan()
+	choice := app.scanner.Text()
+
+	switch choice {
+	case "1":
+		app.showAllAccounts(ctx)
+	case "2":
+		app.searchTransactions(ctx)
+	case "3":
+		app.setAccountFrozen(ctx, true)
+	case "4":
+		app.setAccountFrozen(ctx, false)
+	case "5":
+		app.rebuildBalances(ctx)
+	case "6":
+		app.reconcile(ctx)
+	case "7":
+		app.setOverdraftLimit(ctx)
+	case "8":
+		app.showBalanceAt(ctx)
+	case "9":
+		app.setDailyLimits(ctx)
+	case "10":
+		app.editAccountNotes(ctx)
+	case "11":
+		app.assessMaintenanceFees(ctx)
+	case "12":
+		app.reverseTransaction(ctx)
+	case "13":
+		app.closeAccount(ctx)
+	case "14":
+		app.showCashReport(ctx)
+	case "15":
+		app.receiveExternalCredit(ctx)
+	case "16":
+		app.showAuditLog(ctx)
+	case "17":
+		app.recalculateFees(ctx)
+	case "18":
+		app.importAccounts(ctx)
+	case "19":
+		app.replayWebhooks(ctx)
+	case "20":
+		app.verifyCashHolds(ctx)
+	case "21":
+		app.sweepDormantBalances(ctx)
+	case "22":
+		app.showAccountTimeline(ctx)
+	case "23":
+		app.postCustomTransaction(ctx)
+	case "24":
+		app.reviewFlaggedTransactions(ctx)
+	case "25":
+		app.manageOperationSwitches(ctx)
+	case "26":
+		app.manageLoans(ctx)
+	case "27":
+		app.manageBackup(ctx)
+	case "28":
+		app.verifyHistory(ctx)
+	case "29":
+		app.manageArchive(ctx)
+	case "30":
+		app.editPreferences(ctx)
+	case "31":
+		app.postLedgerEntries(ctx)
+	case "32":
+		app.exportAuditLog(ctx)
+	case "33":
+		app.logout()
+	case "34":
+		app.stop()
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+	}
+}
+
+// login выполняет вход пользователя
+func (app *BankApp) login(ctx context.Context) {
+	username, password := app.readCredentials()
+
+	user, err := app.auth.Login(ctx, username, password)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.currentUser = user
+	app.printf("Добро пожаловать, %s!\n", user.Username)
+	app.applyPreferences(ctx, user)
+}
+
+// register регистрирует нового пользователя и выполняет вход
+func (app *BankApp) register(ctx context.Context) {
+	username, password := app.readCredentials()
+
+	user, err := app.auth.Register(ctx, username, password)
+	if err != nil {
+		app.printf("Ошибка при регистрации: %v\n", err)
+		return
+	}
+
+	app.currentUser = user
+	app.printf("Пользователь %s зарегистрирован\n", user.Username)
+	if user.IsAdmin() {
+		app.println("Вы первый пользователь и получили роль администратора")
+	}
+}
+
+// logout завершает сеанс пользователя
+func (app *BankApp) logout() {
+	app.currentUser = nil
+	app.currentAccount = nil
+	app.prefs = models.DefaultPreferences()
+	app.setLanguage(app.prefs.Language)
+	app.println("Вы вышли из профиля")
+}
+
+// readCredentials читает имя пользователя и пароль
+func (app *BankApp) readCredentials() (string, string) {
+	app.print("Имя пользователя: ")
+	app.scanner.Scan()
+	username := strings.TrimSpace(app.scanner.Text())
+
+	app.print("Пароль: ")
+	app.scanner.Scan()
+	password := app.scanner.Text()
+
+	return username, password
+}
+
+// setAccountFrozen замораживает или размораживает счет по ID
+func (app *BankApp) setAccountFrozen(ctx context.Context, frozen bool) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	var err error
+	if frozen {
+		err = app.admin.FreezeAccount(ctx, accountID)
+	} else {
+		err = app.admin.UnfreezeAccount(ctx, accountID)
+	}
+
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	if frozen {
+		app.printf("Счет %s заморожен\n", accountID)
+	} else {
+		app.printf("Счет %s разморожен\n", accountID)
+	}
+}
+
+// rebuildBalances показывает расхождения балансов счетов с журналом событий
+// и после подтверждения приводит балансы к журналу
+func (app *BankApp) rebuildBalances(ctx context.Context) {
+	results, err := services.RebuildBalances(ctx, app.storage, app.ledger, app.ids, app.clock.Now(), true)
+	if err != nil {
+		app.printf("Ошибка при пересборке балансов: %v\n", err)
+		return
+	}
+
+	if app.printRebuildResults(results) == 0 {
+		return
+	}
+
+	app.print("Исправить балансы по журналу? (y/n): ")
+	app.scanner.Scan()
+	if strings.ToLower(strings.TrimSpace(app.scanner.Text())) != "y" {
+		app.println("Пересборка отменена")
+		return
+	}
+
+	results, err = services.RebuildBalances(ctx, app.storage, app.ledger, app.ids, app.clock.Now(), false)
+	app.auditAction(ctx, "rebuild_balances", "", err)
+	if err != nil {
+		app.printf("Ошибка при пересборке балансов: %v\n", err)
+	}
+
+	app.printf("Исправлено счетов: %d\n", app.printRebuildResults(results))
+}
+
+// printRebuildResults выводит счета, расходящиеся с журналом событий,
+// и возвращает их количество
+func (app *BankApp) printRebuildResults(results []models.RebuildResult) int {
+	differ := 0
+	for _, result := range results {
+		if !result.Mismatch {
+			continue
+		}
+
+		differ++
+		app.printf("Счет %s: баланс %.2f, по журналу %.2f, история %+.2f (событий: %d)\n",
+			result.AccountID, result.StoredBalance, result.RebuiltBalance, result.Correction, result.Events)
+	}
+
+	app.printf("Проверено счетов: %d, расхождений: %d\n", len(results), differ)
+	return differ
+}
+
+// reconcile выполняет сверку проводок по всем счетам
+func (app *BankApp) reconcile(ctx context.Context) {
+	report, err := services.Reconcile(ctx, app.storage)
+	if err != nil {
+		app.printf("Ошибка при сверке: %v\n", err)
+		return
+	}
+
+	app.printf("Проверено проводок: %d\n", report.Entries)
+	app.printf("Сумма внутренних проводок: %.2f\n", report.InternalTotal)
+
+	for _, problem := range report.UnbalancedTransfers {
+		app.printf("Несбалансированный перевод %s\n", problem)
+	}
+	for _, problem := range report.BalanceMismatches {
+		app.printf("Расхождение по счету %s\n", problem)
+	}
+
+	if report.Balanced() {
+		app.println("Расхождений не обнаружено")
+	}
+}
+
+// setOverdraftLimit устанавливает лимит овердрафта счета
+func (app *BankApp) setOverdraftLimit(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	app.print("Введите лимит овердрафта (0 - отключить): ")
+	app.scanner.Scan()
+	limit, err := strconv.ParseFloat(strings.TrimSpace(app.scanner.Text()), 64)
+	if err != nil {
+		app.printf("Ошибка: %v\n", errors.ErrInvalidAmount)
+		return
+	}
+
+	if err := app.admin.SetOverdraftLimit(ctx, accountID, limit); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Лимит овердрафта счета %s установлен: %.2f\n", accountID, limit)
+}
+
+// showBalanceAt показывает баланс счета на указанный момент времени
+func (app *BankApp) showBalanceAt(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	if _, err := app.storage.LoadAccount(ctx, accountID); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	at, err := app.readMoment("Дата (ГГГГ-ММ-ДД или ГГГГ-ММ-ДД ЧЧ:ММ): ")
+	if err != nil {
+		return
+	}
+
+	balance, err := services.GetBalanceAt(ctx, app.ledger, accountID, at)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Баланс счета %s на %s: %.2f\n", accountID, at.Format("2006-01-02 15:04"), balance)
+}
+
+// setDailyLimits устанавливает дневные лимиты счета
+func (app *BankApp) setDailyLimits(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	app.print("Дневной лимит суммы (0 - без ограничения): ")
+	app.scanner.Scan()
+	amountLimit, err := strconv.ParseFloat(strings.TrimSpace(app.scanner.Text()), 64)
+	if err != nil {
+		app.printf("Ошибка: %v\n", errors.ErrInvalidAmount)
+		return
+	}
+
+	app.print("Дневной лимит количества операций (0 - без ограничения): ")
+	app.scanner.Scan()
+	countLimit, err := strconv.Atoi(strings.TrimSpace(app.scanner.Text()))
+	if err != nil {
+		app.printf("Ошибка: %v\n", errors.ErrInvalidAmount)
+		return
+	}
+
+	if err := app.admin.SetDailyLimits(ctx, accountID, amountLimit, countLimit); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Дневные лимиты счета %s обновлены\n", accountID)
+}
+
+// editAccountNotes меняет заметки и персонального менеджера счета.
+// Пустой ввод оставляет текущее значение, "-" очищает его.
+func (app *BankApp) editAccountNotes(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	account, err := app.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Менеджер: %s\n", account.RelationshipManager)
+	app.printf("Заметки: %s\n", account.Notes)
+
+	app.print("Новый менеджер (Enter - без изменений, - - снять): ")
+	app.scanner.Scan()
+	if manager := strings.TrimSpace(app.scanner.Text()); manager != "" {
+		if manager == "-" {
+			manager = ""
+		}
+		if err := app.admin.SetRelationshipManager(ctx, accountID, manager); err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+	}
+
+	app.print("Новые заметки (Enter - без изменений, - - очистить): ")
+	app.scanner.Scan()
+	if notes := strings.TrimSpace(app.scanner.Text()); notes != "" {
+		if notes == "-" {
+			notes = ""
+		}
+		if err := app.admin.SetAccountNotes(ctx, accountID, notes); err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+	}
+
+	app.printf("Данные счета %s обновлены\n", accountID)
+}
+
+// assessMaintenanceFees показывает предварительный расчет платы за обслуживание
+// за текущий месяц и после подтверждения списывает ее
+func (app *BankApp) assessMaintenanceFees(ctx context.Context) {
+	period := app.clock.Now()
+
+	report, err := services.AssessMaintenanceFees(ctx, app.storage, app.ledger, app.fees, period, true)
+	if err != nil {
+		app.printf("Ошибка при расчете платы: %v\n", err)
+		return
+	}
+
+	app.printMaintenanceFeeReport(report)
+	if report.Charged == 0 {
+		return
+	}
+
+	app.print("Списать плату? (y/n): ")
+	app.scanner.Scan()
+	if strings.ToLower(strings.TrimSpace(app.scanner.Text())) != "y" {
+		app.println("Списание отменено")
+		return
+	}
+
+	report, err = services.AssessMaintenanceFees(ctx, app.storage, app.ledger, app.fees, period, false)
+	if err != nil {
+		app.printf("Ошибка при списании платы: %v\n", err)
+	}
+
+	app.printMaintenanceFeeReport(report)
+}
+
+// sweepDormantBalances показывает предварительный расчет перевода малых
+// остатков неактивных и закрытых счетов на пул-счет и проводит его после подтверждения
+func (app *BankApp) sweepDormantBalances(ctx context.Context) {
+	report, err := app.admin.SweepDormantBalances(ctx, app.sweep, true)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printSweepReport(report)
+	if report.Swept == 0 {
+		return
+	}
+
+	app.print("Перевести остатки? (y/n): ")
+	app.scanner.Scan()
+	if strings.ToLower(strings.TrimSpace(app.scanner.Text())) != "y" {
+		app.println("Перевод отменен")
+		return
+	}
+
+	report, err = app.admin.SweepDormantBalances(ctx, app.sweep, false)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+	}
+
+	app.printSweepReport(report)
+}
+
+// printSweepReport выводит отчет о переводе остатков на пул-счет
+func (app *BankApp) printSweepReport(report models.SweepReport) {
+	if report.DryRun {
+		app.printf("\n--- Предварительный расчет перевода на пул-счет %s ---\n", report.PoolAccountID)
+	} else {
+		app.printf("\n--- Перевод остатков на пул-счет %s ---\n", report.PoolAccountID)
+	}
+
+	for _, result := range report.Results {
+		if result.SkipReason != "" {
+			app.printf("%s | %.2f | пропущен: %s\n", result.AccountID, result.Amount, app.tr.T(result.SkipReason))
+			continue
+		}
+
+		app.printf("%s | %.2f\n", result.AccountID, result.Amount)
+	}
+
+	app.printf("Счетов к переводу: %d, пропущено: %d, сумма: %.2f\n",
+		report.Swept, report.Skipped, report.Total)
+}
+
+// printMaintenanceFeeReport выводит отчет о начислении платы за обслуживание
+func (app *BankApp) printMaintenanceFeeReport(report models.MaintenanceFeeReport) {
+	if report.DryRun {
+		app.printf("\n--- Предварительный расчет платы за %s ---\n", report.Period)
+	} else {
+		app.printf("\n--- Списание платы за %s ---\n", report.Period)
+	}
+
+	for _, result := range report.Results {
+		if result.SkipReason != "" {
+			app.printf("%s | пропущен: %s\n", result.AccountID, app.tr.T(result.SkipReason))
+			continue
+		}
+
+		app.printf("%s | %.2f\n", result.AccountID, result.Fee)
+	}
+
+	app.printf("Счетов к списанию: %d, пропущено: %d, сумма: %.2f\n",
+		report.Charged, report.Skipped, report.Total)
+}
+
+// recalculateFees пересчитывает комиссии за период по текущим правилам:
+// сначала показывает отчет о влиянии на клиентов, затем проводит корректировки
+func (app *BankApp) recalculateFees(ctx context.Context) {
+	from, err := app.readOptionalDate("Начало периода (ГГГГ-ММ-ДД): ")
+	if err != nil || from.IsZero() {
+		return
+	}
+
+	to, err := app.readOptionalDate("Конец периода включительно (ГГГГ-ММ-ДД): ")
+	if err != nil || to.IsZero() {
+		return
+	}
+	to = to.AddDate(0, 0, 1)
+
+	report, err := services.RecalculateFees(ctx, app.storage, app.ledger, app.fees, app.ids, app.clock, from, to, true)
+	if err != nil {
+		app.printf("Ошибка при пересчете комиссий: %v\n", err)
+		return
+	}
+
+	app.printFeeRecalculationReport(report)
+	if report.Accounts == 0 {
+		return
+	}
+
+	app.print("Провести корректировки? (y/n): ")
+	app.scanner.Scan()
+	if strings.ToLower(strings.TrimSpace(app.scanner.Text())) != "y" {
+		app.println("Корректировки отменены")
+		return
+	}
+
+	report, err = services.RecalculateFees(ctx, app.storage, app.ledger, app.fees, app.ids, app.clock, from, to, false)
+	app.auditAction(ctx, "recalculate_fees", "", err)
+	if err != nil {
+		app.printf("Ошибка при проведении корректировок: %v\n", err)
+	}
+
+	app.printFeeRecalculationReport(report)
+}
+
+// printFeeRecalculationReport выводит отчет о пересчете комиссий по клиентам
+func (app *BankApp) printFeeRecalculationReport(report models.FeeRecalculationReport) {
+	period := fmt.Sprintf("%s - %s", report.From.Format("2006-01-02"), report.To.AddDate(0, 0, -1).Format("2006-01-02"))
+	if report.DryRun {
+		app.printHeader(app.tr.Sprintf("Предварительный пересчет комиссий за %s", period))
+	} else {
+		app.printHeader(app.tr.Sprintf("Пересчет комиссий за %s", period))
+	}
+
+	if len(report.Results) == 0 {
+		app.println("Расхождений не найдено")
+		return
+	}
+
+	for _, result := range report.Results {
+		line := app.tr.Sprintf("%s (%s) | комиссия %s | списано %.2f, по правилам %.2f, корректировка %+.2f",
+			result.AccountID, result.OwnerName, result.FeeID, result.Charged, result.Expected, result.Difference())
+		if result.SkipReason != "" {
+			line += app.tr.Sprintf(" | пропущено: %s", app.tr.T(result.SkipReason))
+		}
+		fmt.Println(line)
+	}
+
+	app.printf("Затронуто счетов: %d, к возврату: %.2f, к доначислению: %.2f\n",
+		report.Accounts, report.Refunded, report.Collected)
+}
+
+// reverseTransaction сторнирует ошибочную транзакцию счета
+func (app *BankApp) reverseTransaction(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	account, err := app.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.print("Введите ID транзакции: ")
+	app.scanner.Scan()
+	transactionID := strings.TrimSpace(app.scanner.Text())
+
+	if err := app.newAccountService(account).Reverse(ctx, transactionID); err != nil {
+		app.printf("Ошибка при сторнировании: %v\n", err)
+		return
+	}
+
+	app.printf("Транзакция %s сторнирована\n", transactionID)
+}
+
+// closeAccount закрывает счет, при необходимости переводя остаток на другой счет
+func (app *BankApp) closeAccount(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	account, err := app.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	var transferTo string
+	if account.Balance > 0 {
+		transferTo = app.readAccountID(ctx, app.tr.Sprintf("Остаток %.2f. Куда перевести (ID счета или псевдоним): ", account.Balance))
+	}
+
+	if err := app.admin.CloseAccount(ctx, accountID, transferTo); err != nil {
+		app.printf("Ошибка при закрытии счета: %v\n", err)
+		return
+	}
+
+	app.printf("Счет %s закрыт\n", accountID)
+}
+
+// showCashReport показывает кассовый отчет за день для сверки с наличными в кассе
+func (app *BankApp) showCashReport(ctx context.Context) {
+	day, err := app.readOptionalDate("Дата (ГГГГ-ММ-ДД, Enter - сегодня): ")
+	if err != nil {
+		return
+	}
+
+	if day.IsZero() {
+		day = app.clock.Now()
+	}
+
+	report, err := services.GetCashReport(ctx, app.storage, day)
+	if err != nil {
+		app.printf("Ошибка при формировании отчета: %v\n", err)
+		return
+	}
+
+	app.printf("\n--- Кассовый отчет за %s ---\n", report.Date.Format("2006-01-02"))
+	app.printf("Поступило наличными: %.2f (операций: %d)\n", report.CashIn, report.Deposits)
+	app.printf("Выдано наличными: %.2f (операций: %d)\n", report.CashOut, report.Withdrawals)
+	app.printf("Итого по кассе: %.2f\n", report.Net())
+}
+
+// receiveExternalCredit регистрирует входящий платеж из внешнего банка
+func (app *BankApp) receiveExternalCredit(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним получателя: ")
+
+	amount, err := app.readAmount("Введите сумму: ")
+	if err != nil {
+		return
+	}
+
+	app.print("Отправитель: ")
+	app.scanner.Scan()
+	sender := strings.TrimSpace(app.scanner.Text())
+
+	app.print("Назначение платежа: ")
+	app.scanner.Scan()
+	reference := strings.TrimSpace(app.scanner.Text())
+
+	credit, err := services.ReceiveExternalCredit(ctx, app.storage, app.ledger, app.clock, accountID, amount, sender, reference)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	if credit.Status == models.AcceptedCreditStatus {
+		app.printf("Платеж %s зачислен на счет %s\n", credit.ID, accountID)
+	} else {
+		app.printf("Платеж %s ожидает подтверждения владельца счета\n", credit.ID)
+	}
+}
+
+// postCustomTransaction проводит по счету транзакцию типа, заданного в конфигурации
+func (app *BankApp) postCustomTransaction(ctx context.Context) {
+	types := app.txTypes.Types()
+	if len(types) == 0 {
+		app.println("Пользовательские типы транзакций не настроены")
+		return
+	}
+
+	app.println("Доступные типы:")
+	for _, info := range types {
+		app.printf("  %s - %s (%s)\n", info.Type, info.Name, info.Direction)
+	}
+
+	txType := models.TransactionType(strings.ToUpper(app.readLine("Тип транзакции: ")))
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	amount, err := app.readAmount("Введите сумму: ")
+	if err != nil {
+		return
+	}
+
+	memo := app.readLine("Комментарий: ")
+
+	transaction, err := services.PostCustomTransaction(ctx, app.storage, app.ledger, app.clock, app.txTypes, accountID, txType, amount, memo)
+	app.auditAction(ctx, "post_custom_transaction", accountID, err)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Транзакция %s проведена по счету %s\n", transaction.ID, accountID)
+}
+
+// postLedgerEntries проводит сбалансированную проводку по нескольким счетам.
+// Проводка доступна только операторам из --ledger-operators.
+func (app *BankApp) postLedgerEntries(ctx context.Context) {
+	app.println("Суммы ног со знаком: плюс - зачисление, минус - списание.")
+	app.println("Пустой ID счета завершает ввод.")
+
+	var entries []models.LedgerEntry
+	for {
+		accountID := app.readAccountID(ctx, "ID счета или псевдоним: ")
+		if accountID == "" {
+			break
+		}
+
+		amount, err := strconv.ParseFloat(strings.Replace(app.readLine("Сумма: "), ",", ".", 1), 64)
+		if err != nil {
+			app.printf("Ошибка: %v\n", errors.ErrInvalidAmount)
+			continue
+		}
+
+		entries = append(entries, models.LedgerEntry{AccountID: accountID, Amount: amount})
+	}
+
+	reason := app.readLine("Код основания: ")
+	memo := app.readLine("Комментарий: ")
+	for i := range entries {
+		entries[i].ReasonCode = reason
+		entries[i].Memo = memo
+	}
+
+	if err := app.postings.PostEntries(services.WithOperator(ctx, app.currentUser.Username), entries); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Проводка по %d счетам выполнена\n", len(entries))
+}
+
+// showAuditLog показывает журнал аудита с фильтрами и постраничным выводом
+func (app *BankApp) showAuditLog(ctx context.Context) {
+	var filter models.AuditFilter
+
+	app.print("Пользователь (Enter - любой): ")
+	app.scanner.Scan()
+	filter.Actor = strings.TrimSpace(app.scanner.Text())
+
+	app.print("ID счета (Enter - любой): ")
+	app.scanner.Scan()
+	filter.AccountID = strings.TrimSpace(app.scanner.Text())
+
+	app.print("Только неуспешные попытки? (y/n): ")
+	app.scanner.Scan()
+	filter.FailuresOnly = strings.ToLower(strings.TrimSpace(app.scanner.Text())) == "y"
+
+	filter.Limit = pageSize
+	for {
+		entries, total, err := app.audit.Query(ctx, filter)
+		if err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+
+		if total == 0 {
+			app.println("Записи не найдены")
+			return
+		}
+
+		app.printf("\n--- Журнал аудита (%d-%d из %d) ---\n", filter.Offset+1, filter.Offset+len(entries), total)
+		for _, entry := range entries {
+			result := app.tr.T("успешно")
+			if !entry.Success {
+				result = app.tr.Sprintf("ошибка: %s", app.tr.T(entry.Error))
+			}
+
+			app.printf("%s | %s | %s | %s | %.2f | %s | %s\n",
+				app.formatTime(entry.Timestamp),
+				app.auditActor(entry),
+				entry.Action,
+				entry.AccountID,
+				entry.Amount,
+				entry.Details,
+				result)
+		}
+
+		if filter.Offset+len(entries) >= total {
+			return
+		}
+
+		app.print("Enter - следующая страница, q - выход: ")
+		app.scanner.Scan()
+		if strings.TrimSpace(app.scanner.Text()) == "q" {
+			return
+		}
+
+		filter.Offset += pageSize
+	}
+}
+
+// auditActor автор записи аудита; для действий по доверенности указывается и она
+func (app *BankApp) auditActor(entry models.AuditEntry) string {
+	if entry.GrantID == "" {
+		return entry.Actor
+	}
+	return app.tr.Sprintf("%s по доверенности %s", entry.Actor, entry.GrantID)
+}
+
+// exportAuditLog выгружает журнал аудита в файл JSON Lines или CEF для SIEM.
+// Номер последней выгруженной записи вводится при следующей выгрузке.
+func (app *BankApp) exportAuditLog(ctx context.Context) {
+	format := models.ExportFormat(strings.ToLower(app.readLine("Формат (jsonl/cef): ")))
+	if format != models.JSONLinesFormat && format != models.CEFFormat {
+		app.printf("Ошибка: %v\n", errors.ErrUnsupportedFormat)
+		return
+	}
+
+	var after uint64
+	if input := app.readLine("Выгрузить записи после номера (Enter - все): "); input != "" {
+		parsed, err := strconv.ParseUint(input, 10, 64)
+		if err != nil {
+			app.println("Некорректный номер записи")
+			return
+		}
+		after = parsed
+	}
+
+	path := app.readLine("Введите путь к файлу: ")
+	if path == "" {
+		app.println("Путь к файлу не может быть пустым")
+		return
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		app.printf("Ошибка при создании файла: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	last, err := services.ExportAuditLog(ctx, file, app.audit, format, after)
+	app.auditAction(ctx, "audit_export", "", err)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Журнал аудита выгружен в %s, последняя запись %d\n", path, last)
+}
+
+// showAccountTimeline показывает хронологию событий счета для службы поддержки
+func (app *BankApp) showAccountTimeline(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	app.println("Виды событий: TRANSACTION, STATUS, LIMIT, DISPUTE, AUDIT")
+	app.print("Виды событий через запятую (Enter - все): ")
+	app.scanner.Scan()
+
+	var kinds []models.TimelineKind
+	for _, field := range strings.Split(app.scanner.Text(), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			kinds = append(kinds, models.TimelineKind(strings.ToUpper(field)))
+		}
+	}
+
+	timeline, err := app.admin.GetAccountTimeline(ctx, accountID, kinds...)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	if len(timeline) == 0 {
+		app.println("События не найдены")
+		return
+	}
+
+	app.printf("\n--- Хронология счета %s ---\n", accountID)
+	for _, entry := range timeline {
+		result := ""
+		if entry.Error != "" {
+			result = " | " + app.tr.Sprintf("ошибка: %s", app.tr.T(entry.Error))
+		}
+
+		app.printf("%s | %s | %s | %s | %.2f | %s%s\n",
+			app.formatTime(entry.Timestamp),
+			entry.Kind,
+			entry.Actor,
+			entry.Action,
+			entry.Amount,
+			entry.Details,
+			result)
+	}
+}
+
+// verifyHistory проверяет цепочку хешей транзакций счета
+func (app *BankApp) verifyHistory(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	report, err := app.admin.VerifyHistory(ctx, accountID)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Транзакций: %d, в цепочке хешей: %d\n", report.Transactions, report.Sealed)
+	if report.Intact() {
+		app.println("Нарушений целостности не найдено")
+		return
+	}
+
+	for _, issue := range report.Issues {
+		app.printf("#%d %s: %s\n", issue.Index+1, issue.TransactionID, app.tr.T(issue.Problem))
+	}
+	app.printf("Найдено нарушений целостности: %d\n", len(report.Issues))
+}
+
+// importAccounts загружает счета и историю транзакций из CSV-файлов
+func (app *BankApp) importAccounts(ctx context.Context) {
+	accountsPath := app.readLine("Путь к файлу счетов (CSV): ")
+	transactionsPath := app.readLine("Путь к файлу транзакций (CSV): ")
+	if accountsPath == "" || transactionsPath == "" {
+		app.println("Путь к файлу не может быть пустым")
+		return
+	}
+
+	accounts, err := os.Open(accountsPath)
+	if err != nil {
+		app.printf("Ошибка при чтении файла: %v\n", err)
+		return
+	}
+	defer accounts.Close()
+
+	transactions, err := os.Open(transactionsPath)
+	if err != nil {
+		app.printf("Ошибка при чтении файла: %v\n", err)
+		return
+	}
+	defer transactions.Close()
+
+	report, err := services.ImportCSV(ctx, app.storage, app.ledger, app.ids, app.clock, accounts, transactions)
+	app.auditAction(ctx, "import_csv", "", err)
+	for _, rowErr := range report.Errors {
+		app.printf("%s:%d: %s\n", rowErr.File, rowErr.Line, app.tr.Error(rowErr.Err))
+	}
+	if err != nil {
+		app.printf("Ошибка при импорте: %v\n", err)
+		return
+	}
+
+	app.printf("Импортировано счетов: %d, транзакций: %d\n", report.Accounts, report.Transactions)
+}
+
+// replayWebhooks повторно доставляет уведомления на вебхук за период или
+// по списку транзакций, например после недоступности получателя
+func (app *BankApp) replayWebhooks(ctx context.Context) {
+	if app.webhook == nil {
+		app.println("Вебхук не настроен")
+		return
+	}
+
+	var filter models.OutboxFilter
+	var err error
+	if filter.From, err = app.readOptionalDate("Начало периода (ГГГГ-ММ-ДД, Enter - без ограничения): "); err != nil {
+		return
+	}
+	if filter.To, err = app.readOptionalDate("Конец периода включительно (ГГГГ-ММ-ДД, Enter - без ограничения): "); err != nil {
+		return
+	}
+	if !filter.To.IsZero() {
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	for _, id := range strings.Split(app.readLine("ID транзакций через запятую (Enter - все): "), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			filter.TransactionIDs = append(filter.TransactionIDs, id)
+		}
+	}
+
+	report, err := services.ReplayOutbox(ctx, app.outbox, app.webhook, app.clock, filter)
+	app.auditAction(ctx, "replay_webhooks", "", err)
+	if err != nil {
+		app.printf("Ошибка при повторной доставке: %v\n", err)
+		return
+	}
+
+	app.printf("Найдено уведомлений: %d, доставлено: %d\n", report.Matched, report.Delivered)
+	if len(report.Failed) > 0 {
+		app.printf("Не доставлены: %s\n", strings.Join(report.Failed, ", "))
+	}
+}
+
+// manageBackup создает резервную копию состояния банка или восстанавливает его
+func (app *BankApp) manageBackup(ctx context.Context) {
+	app.println("1. Создать резервную копию")
+	app.println("2. Восстановить из резервной копии")
+	app.print("Выберите опцию: ")
+	app.scanner.Scan()
+	choice := strings.TrimSpace(app.scanner.Text())
+	if choice != "1" && choice != "2" {
+		app.println("Неверный выбор. Попробуйте снова.")
+		return
+	}
+
+	app.print("Введите путь к файлу: ")
+	app.scanner.Scan()
+	path := strings.TrimSpace(app.scanner.Text())
+	if path == "" {
+		app.println("Путь к файлу не может быть пустым")
+		return
+	}
+
+	if choice == "1" {
+		app.createBackup(ctx, path)
+		return
+	}
+	app.restoreBackup(ctx, path)
+}
+
+// createBackup записывает резервную копию в файл
+func (app *BankApp) createBackup(ctx context.Context, path string) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		app.printf("Ошибка при создании файла: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	err = storage.ExportBackup(ctx, app.storage, app.ledger, app.clock, file)
+	app.auditAction(ctx, "backup_export", "", err)
+	if err != nil {
+		app.printf("Ошибка при создании резервной копии: %v\n", err)
+		return
+	}
+
+	app.printf("Резервная копия сохранена в %s\n", path)
+}
+
+// restoreBackup проверяет резервную копию, показывает, что будет
+// восстановлено, и после подтверждения восстанавливает состояние
+func (app *BankApp) restoreBackup(ctx context.Context, path string) {
+	report, err := app.importBackup(ctx, path, true)
+	if err != nil {
+		app.printf("Ошибка при проверке резервной копии: %v\n", err)
+		return
+	}
+
+	app.printRestoreReport(report)
+	if len(report.UserConflicts) > 0 {
+		app.printf("Ошибка: %v\n", errors.ErrBackupUserConflict)
+		return
+	}
+	if report.Users == 0 && report.Accounts == 0 {
+		return
+	}
+
+	app.print("Восстановить? (y/n): ")
+	app.scanner.Scan()
+	if strings.ToLower(strings.TrimSpace(app.scanner.Text())) != "y" {
+		app.println("Восстановление отменено")
+		return
+	}
+
+	report, err = app.importBackup(ctx, path, false)
+	app.auditAction(ctx, "backup_restore", "", err)
+	if err != nil {
+		app.printf("Ошибка при восстановлении: %v\n", err)
+		return
+	}
+
+	app.printRestoreReport(report)
+}
+
+// importBackup читает резервную копию из файла
+func (app *BankApp) importBackup(ctx context.Context, path string, dryRun bool) (models.RestoreReport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return models.RestoreReport{}, err
+	}
+	defer file.Close()
+
+	return storage.ImportBackup(ctx, app.storage, app.ledger, file, dryRun)
+}
+
+// printRestoreReport выводит отчет о восстановлении из резервной копии
+func (app *BankApp) printRestoreReport(report models.RestoreReport) {
+	if report.DryRun {
+		app.printHeader(app.tr.Sprintf("Проверка резервной копии от %s", app.formatTime(report.CreatedAt)))
+	} else {
+		app.printHeader(app.tr.Sprintf("Восстановление из резервной копии от %s", app.formatTime(report.CreatedAt)))
+	}
+
+	app.printf("Версия формата: %d\n", report.SchemaVersion)
+	app.printf("Пользователей: %d, счетов: %d, событий журнала: %d\n", report.Users, report.Accounts, report.Events)
+	if len(report.ExistingUsers) > 0 {
+		app.printf("Уже существуют: %s\n", strings.Join(report.ExistingUsers, ", "))
+	}
+	if len(report.UserConflicts) > 0 {
+		app.printf("Имя занято другим пользователем: %s\n", strings.Join(report.UserConflicts, ", "))
+	}
+	if len(report.SkippedAccounts) > 0 {
+		app.printf("Пропущены существующие счета: %s\n", strings.Join(report.SkippedAccounts, ", "))
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// AdminServiceImpl реализация AdminService
+type AdminServiceImpl struct {
+	storage interfaces.Storage
+	ledger  interfaces.LedgerStorage
+	ids     models.IDGenerator
+	audit   interfaces.AuditLogger
+	clock   models.Clock
+}
+
+// AdminOption настройка сервиса административных операций
+type AdminOption func(*AdminServiceImpl)
+
+// NewAdminService создает сервис административных операций
+func NewAdminService(storage interfaces.Storage, ledger interfaces.LedgerStorage, ids models.IDGenerator, audit interfaces.AuditLogger, opts ...AdminOption) interfaces.AdminService {
+	s := &AdminServiceImpl{
+		storage: storage,
+		ledger:  ledger,
+		ids:     ids,
+		audit:   audit,
+		clock:   models.DefaultClock,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithAdminClock задает часы, по которым проставляется время операций
+func WithAdminClock(clock models.Clock) AdminOption {
+	return func(s *AdminServiceImpl) {
+		s.clock = clock
+	}
+}
+
+// FreezeAccount замораживает счет: операции по нему запрещены
+func (s *AdminServiceImpl) FreezeAccount(ctx context.Context, accountID string) (err error) {
+	defer func() {
+		s.auditAdmin(ctx, "freeze", accountID, 0, "", err)
+	}()
+
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	if account.Status == models.ClosedStatus {
+		return errors.ErrAccountClosed
+	}
+
+	changeStatus(s.ids, account, models.FrozenStatus, "заморожен администратором", s.clock.Now())
+
+	return s.storage.SaveAccount(ctx, account)
+}
+
+// UnfreezeAccount снимает заморозку со счета
+func (s *AdminServiceImpl) UnfreezeAccount(ctx context.Context, accountID string) (err error) {
+	defer func() {
+		s.auditAdmin(ctx, "unfreeze", accountID, 0, "", err)
+	}()
+
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	if account.Status == models.ClosedStatus {
+		return errors.ErrAccountClosed
+	}
+
+	changeStatus(s.ids, account, models.ActiveStatus, "разморожен администратором", s.clock.Now())
+
+	// Разморозка администратором означает, что причина карантина устранена
+	account.Quarantined = false
+	account.QuarantineReason = ""
+
+	return s.storage.SaveAccount(ctx, account)
+}
+
+// SetOverdraftLimit устанавливает лимит овердрафта и фиксирует изменение
+// служебной транзакцией в истории счета
+func (s *AdminServiceImpl) SetOverdraftLimit(ctx context.Context, accountID string, limit float64) (err error) {
+	defer func() {
+		s.auditAdmin(ctx, "set_overdraft_limit", accountID, limit, "", err)
+	}()
+
+	if limit < 0 || math.IsNaN(limit) || math.IsInf(limit, 0) {
+		return errors.ErrInvalidAmount
+	}
+
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	transaction := models.Transaction{
+		ID:        s.ids.NewID("TX"),
+		Type:      models.OverdraftLimitTransaction,
+		Amount:    limit,
+		Timestamp: s.clock.Now(),
+		Message:   fmt.Sprintf("Лимит овердрафта изменен с %.2f на %.2f", account.OverdraftLimit, limit),
+	}
+
+	account.OverdraftLimit = limit
+	account.Transactions = append(account.Transactions, transaction)
+
+	return s.storage.SaveAccount(ctx, account)
+}
+
+// SetDailyLimits устанавливает дневные лимиты счета на сумму и количество
+// списаний; нулевое значение снимает ограничение
+func (s *AdminServiceImpl) SetDailyLimits(ctx context.Context, accountID string, amountLimit float64, countLimit int) (err error) {
+	defer func() {
+		s.auditAdmin(ctx, "set_daily_limits", accountID, amountLimit, fmt.Sprintf("операций: %d", countLimit), err)
+	}()
+
+	if amountLimit < 0 || countLimit < 0 || math.IsNaN(amountLimit) || math.IsInf(amountLimit, 0) {
+		return errors.ErrInvalidAmount
+	}
+
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	account.DailyAmountLimit = amountLimit
+	account.DailyCountLimit = countLimit
+
+	return s.storage.SaveAccount(ctx, account)
+}
+
+// SetAccountNotes сохраняет служебные заметки администратора по счету
+func (s *AdminServiceImpl) SetAccountNotes(ctx context.Context, accountID, notes string) (err error) {
+	defer func() {
+		s.auditAdmin(ctx, "set_notes", accountID, 0, "", err)
+	}()
+
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	account.Notes = strings.TrimSpace(notes)
+
+	return s.storage.SaveAccount(ctx, account)
+}
+
+// SetRelationshipManager назначает счету персонального менеджера;
+// пустое значение снимает назначение
+func (s *AdminServiceImpl) SetRelationshipManager(ctx context.Context, accountID, manager string) (err error) {
+	defer func() {
+		s.auditAdmin(ctx, "set_manager", accountID, 0, manager, err)
+	}()
+
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	account.RelationshipManager = strings.TrimSpace(manager)
+
+	return s.storage.SaveAccount(ctx, account)
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"bankapp/errors"
+	"bankapp/services"
+)
+
+// manageAliases показывает псевдонимы текущего счета и позволяет
+// привязать или отвязать псевдоним либо номер телефона. Псевдонимы
+// определяют, куда приходят входящие переводы, поэтому по доверенности
+// их можно только просматривать.
+func (app *BankApp) manageAliases(ctx context.Context) {
+	accountID := app.currentAccount.AccountID()
+	_, delegated := app.currentAccount.(*services.DelegatedAccountService)
+
+	aliases, err := app.aliases.ListAliases(ctx, accountID)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printHeader("Псевдонимы счета")
+	if len(aliases) == 0 {
+		app.println("Псевдонимов нет")
+	}
+	for _, alias := range aliases {
+		app.printf("%s (%s)\n", alias.Alias, alias.Kind)
+	}
+
+	app.println("1. Привязать псевдоним или телефон")
+	app.println("2. Отвязать псевдоним")
+	app.println("3. Переименовать псевдоним")
+	app.println("4. История изменений")
+	app.println("5. Назад")
+	app.print("Выберите опцию: ")
+	app.scanner.Scan()
+
+	choice := strings.TrimSpace(app.scanner.Text())
+	if delegated && (choice == "1" || choice == "2" || choice == "3") {
+		app.auditAction(ctx, "manage_aliases", accountID, errors.ErrAccessDenied)
+		app.printf("Ошибка: %v\n", errors.ErrAccessDenied)
+		return
+	}
+
+	switch choice {
+	case "1":
+		alias, err := app.aliases.LinkAlias(ctx, accountID, app.readLine("Псевдоним (латиница, 3-32 символа) или телефон: "))
+		if err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+		app.printf("Псевдоним %s привязан к счету\n", alias.Alias)
+	case "2":
+		if err := app.aliases.UnlinkAlias(ctx, accountID, app.readLine("Псевдоним или телефон: ")); err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+		app.println("Псевдоним отвязан")
+	case "3":
+		alias, err := app.aliases.RenameAlias(ctx, accountID, app.readLine("Текущий псевдоним: "), app.readLine("Новый псевдоним: "))
+		if err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+		app.printf("Псевдоним переименован в %s\n", alias.Alias)
+	case "4":
+		history, err := app.aliases.History(ctx, accountID)
+		if err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+		for _, change := range history {
+			alias := change.Alias
+			if change.PreviousAlias != "" {
+				alias = change.PreviousAlias + " -> " + change.Alias
+			}
+			app.printf("%s | %s | %s | %s\n", app.formatTime(change.Timestamp), change.Action, alias, change.Actor)
+		}
+	}
+}
+
+// readAccountID читает ID счета, псевдоним или номер телефона и возвращает
+// ID счета. Неизвестная ссылка возвращается как есть, чтобы вызывающий код
+// сообщил, что счет не найден.
+func (app *BankApp) readAccountID(ctx context.Context, prompt string) string {
+	reference := app.readLine(prompt)
+	if account, err := app.aliases.Resolve(ctx, reference); err == nil {
+		return account.ID
+	}
+	return reference
+}
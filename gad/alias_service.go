@@ -0,0 +1,279 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	stderrors "errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	// phonePattern номер телефона в международном формате
+	phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{9,14}$`)
+	// namePattern короткий псевдоним: начинается с буквы, 3-32 символа
+	namePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{2,31}$`)
+)
+
+// AliasServiceImpl реализация AliasService
+type AliasServiceImpl struct {
+	storage interfaces.Storage
+	aliases interfaces.AliasStorage
+	clock   models.Clock
+	audit   interfaces.AuditLogger
+}
+
+// NewAliasService создает сервис псевдонимов счетов
+func NewAliasService(storage interfaces.Storage, aliases interfaces.AliasStorage, clock models.Clock, audit interfaces.AuditLogger) interfaces.AliasService {
+	return &AliasServiceImpl{
+		storage: storage,
+		aliases: aliases,
+		clock:   clock,
+		audit:   audit,
+	}
+}
+
+// NormalizeAlias приводит псевдоним к каноническому виду и определяет его вид.
+// Номер телефона очищается от пробелов, скобок и дефисов, российский
+// номер с 8 в начале переводится в формат +7.
+func NormalizeAlias(raw string) (string, models.AliasKind, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", "", errors.ErrInvalidAlias
+	}
+
+	if raw[0] == '+' || (raw[0] >= '0' && raw[0] <= '9') {
+		phone := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(raw)
+		if len(phone) == 11 && phone[0] == '8' {
+			phone = "7" + phone[1:]
+		}
+		if !strings.HasPrefix(phone, "+") {
+			phone = "+" + phone
+		}
+
+		if !phonePattern.MatchString(phone) {
+			return "", "", errors.ErrInvalidAlias
+		}
+		return phone, models.PhoneAlias, nil
+	}
+
+	name := strings.ToLower(raw)
+	if !namePattern.MatchString(name) {
+		return "", "", errors.ErrInvalidAlias
+	}
+
+	return name, models.NameAlias, nil
+}
+
+// LinkAlias привязывает псевдоним к счету. Псевдоним, занятый другим счетом
+// или совпадающий с ID счета, не привязывается.
+func (s *AliasServiceImpl) LinkAlias(ctx context.Context, accountID, raw string) (alias models.AccountAlias, err error) {
+	defer func() {
+		recordAudit(ctx, s.audit, models.AuditEntry{
+			Action:    "link_alias",
+			AccountID: accountID,
+			Details:   raw,
+		}, err)
+	}()
+
+	name, kind, err := NormalizeAlias(raw)
+	if err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	existing, err := s.checkAvailable(ctx, accountID, name)
+	if err != nil || existing.Alias != "" {
+		return existing, err
+	}
+
+	alias = models.AccountAlias{
+		Alias:     name,
+		Kind:      kind,
+		AccountID: accountID,
+		CreatedAt: s.clock.Now(),
+	}
+
+	if err := s.aliases.SaveAlias(ctx, alias); err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	return alias, s.recordChange(ctx, alias, models.LinkAliasAction)
+}
+
+// UnlinkAlias отвязывает псевдоним от счета
+func (s *AliasServiceImpl) UnlinkAlias(ctx context.Context, accountID, raw string) (err error) {
+	defer func() {
+		recordAudit(ctx, s.audit, models.AuditEntry{
+			Action:    "unlink_alias",
+			AccountID: accountID,
+			Details:   raw,
+		}, err)
+	}()
+
+	name, _, err := NormalizeAlias(raw)
+	if err != nil {
+		return err
+	}
+
+	alias, err := s.aliases.LoadAlias(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if alias.AccountID != accountID {
+		return errors.ErrAliasNotFound
+	}
+
+	if err := s.aliases.DeleteAlias(ctx, name); err != nil {
+		return err
+	}
+
+	return s.recordChange(ctx, alias, models.UnlinkAliasAction)
+}
+
+// RenameAlias заменяет псевдоним счета новым. Новый псевдоним проверяется
+// так же, как при привязке, история сохраняет прежнее имя.
+func (s *AliasServiceImpl) RenameAlias(ctx context.Context, accountID, oldRaw, newRaw string) (alias models.AccountAlias, err error) {
+	defer func() {
+		recordAudit(ctx, s.audit, models.AuditEntry{
+			Action:    "rename_alias",
+			AccountID: accountID,
+			Details:   oldRaw + " -> " + newRaw,
+		}, err)
+	}()
+
+	oldName, _, err := NormalizeAlias(oldRaw)
+	if err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	previous, err := s.aliases.LoadAlias(ctx, oldName)
+	if err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	if previous.AccountID != accountID {
+		return models.AccountAlias{}, errors.ErrAliasNotFound
+	}
+
+	name, kind, err := NormalizeAlias(newRaw)
+	if err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	if name == oldName {
+		return previous, nil
+	}
+
+	existing, err := s.checkAvailable(ctx, accountID, name)
+	if err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	alias = existing
+	if alias.Alias == "" {
+		alias = models.AccountAlias{
+			Alias:     name,
+			Kind:      kind,
+			AccountID: accountID,
+			CreatedAt: s.clock.Now(),
+		}
+
+		if err := s.aliases.SaveAlias(ctx, alias); err != nil {
+			return models.AccountAlias{}, err
+		}
+	}
+
+	if err := s.aliases.DeleteAlias(ctx, oldName); err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	actor, _ := ActorFromContext(ctx)
+	return alias, s.aliases.AppendAliasChange(ctx, models.AliasChange{
+		Alias:         alias.Alias,
+		PreviousAlias: previous.Alias,
+		Kind:          alias.Kind,
+		AccountID:     accountID,
+		Action:        models.RenameAliasAction,
+		Actor:         actor,
+		Timestamp:     s.clock.Now(),
+	})
+}
+
+// checkAvailable проверяет, что к счету можно привязать псевдоним name.
+// Если псевдоним уже привязан к этому счету, он возвращается.
+func (s *AliasServiceImpl) checkAvailable(ctx context.Context, accountID, name string) (models.AccountAlias, error) {
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	if err := checkOperable(account); err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	if _, err := s.storage.LoadAccount(ctx, name); err == nil {
+		return models.AccountAlias{}, errors.ErrAliasTaken
+	}
+
+	existing, err := s.aliases.LoadAlias(ctx, name)
+	if stderrors.Is(err, errors.ErrAliasNotFound) {
+		return models.AccountAlias{}, nil
+	}
+	if err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	if existing.AccountID != accountID {
+		return models.AccountAlias{}, errors.ErrAliasTaken
+	}
+	return existing, nil
+}
+
+// Resolve находит счет по ID, псевдониму или номеру телефона
+func (s *AliasServiceImpl) Resolve(ctx context.Context, reference string) (*models.Account, error) {
+	reference = strings.TrimSpace(reference)
+
+	account, err := s.storage.LoadAccount(ctx, reference)
+	if err == nil {
+		return account, nil
+	}
+
+	name, _, aliasErr := NormalizeAlias(reference)
+	if aliasErr != nil {
+		return nil, err
+	}
+
+	alias, aliasErr := s.aliases.LoadAlias(ctx, name)
+	if aliasErr != nil {
+		return nil, err
+	}
+
+	return s.storage.LoadAccount(ctx, alias.AccountID)
+}
+
+// ListAliases возвращает псевдонимы счета
+func (s *AliasServiceImpl) ListAliases(ctx context.Context, accountID string) ([]models.AccountAlias, error) {
+	return s.aliases.ListAliases(ctx, accountID)
+}
+
+// History возвращает историю привязки псевдонимов счета
+func (s *AliasServiceImpl) History(ctx context.Context, accountID string) ([]models.AliasChange, error) {
+	return s.aliases.ListAliasChanges(ctx, accountID)
+}
+
+// recordChange записывает изменение псевдонима в историю
+func (s *AliasServiceImpl) recordChange(ctx context.Context, alias models.AccountAlias, action models.AliasAction) error {
+	actor, _ := ActorFromContext(ctx)
+
+	return s.aliases.AppendAliasChange(ctx, models.AliasChange{
+		Alias:     alias.Alias,
+		Kind:      alias.Kind,
+		AccountID: alias.AccountID,
+		Action:    action,
+		Actor:     actor,
+		Timestamp: s.clock.Now(),
+	})
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"sort"
+	"time"
+)
+
+// largestCount количество крупнейших операций в сводке
+const largestCount = 5
+
+// AnalyticsServiceImpl реализация AnalyticsService
+type AnalyticsServiceImpl struct {
+	storage interfaces.Storage
+	clock   models.Clock
+}
+
+// NewAnalyticsService создает сервис аналитических сводок
+func NewAnalyticsService(storage interfaces.Storage, clock models.Clock) interfaces.AnalyticsService {
+	return &AnalyticsServiceImpl{
+		storage: storage,
+		clock:   clock,
+	}
+}
+
+// GetSummary считает сводку по счету за период: обороты по месяцам с
+// разбивкой по типам и категориям, средний баланс, чистый поток и
+// крупнейшие операции. Сторнированные операции и их сторно не учитываются.
+func (s *AnalyticsServiceImpl) GetSummary(ctx context.Context, accountID string, period models.Period) (models.AccountSummary, error) {
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return models.AccountSummary{}, err
+	}
+
+	summary := models.AccountSummary{AccountID: account.ID, Period: period}
+
+	// Баланс на начало периода: текущий баланс без операций, проведенных позже
+	opening := account.Balance
+	var inPeriod []models.Transaction
+	for _, tx := range account.Transactions {
+		if tx.Timestamp.Before(period.From) {
+			continue
+		}
+		opening -= tx.SignedAmount()
+		if tx.Timestamp.Before(period.To) {
+			inPeriod = append(inPeriod, tx)
+		}
+	}
+
+	summary.AverageBalance = averageBalance(opening, inPeriod, period, s.clock.Now())
+
+	months := make(map[string]*models.MonthlySummary)
+	var keys []string
+	for _, tx := range inPeriod {
+		summary.NetFlow += tx.SignedAmount()
+		if tx.ReversedBy != "" || tx.ReversalOf != "" {
+			continue
+		}
+
+		key := tx.Timestamp.Format("2006-01")
+		month, ok := months[key]
+		if !ok {
+			month = &models.MonthlySummary{
+				Month:      key,
+				ByType:     make(map[models.TransactionType]float64),
+				ByCategory: make(map[string]float64),
+			}
+			months[key] = month
+			keys = append(keys, key)
+		}
+
+		month.ByType[tx.Type] += tx.Amount
+		if amount := tx.SignedAmount(); amount >= 0 {
+			month.In += amount
+		} else {
+			month.Out -= amount
+			month.ByCategory[tx.Category] -= amount
+		}
+
+		summary.Largest = append(summary.Largest, tx)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		summary.Months = append(summary.Months, *months[key])
+	}
+
+	sort.SliceStable(summary.Largest, func(i, j int) bool {
+		return summary.Largest[i].Amount > summary.Largest[j].Amount
+	})
+	if len(summary.Largest) > largestCount {
+		summary.Largest = summary.Largest[:largestCount]
+	}
+
+	return summary, nil
+}
+
+// averageBalance считает средневзвешенный по времени баланс за период;
+// часть периода после now не учитывается
+func averageBalance(opening float64, transactions []models.Transaction, period models.Period, now time.Time) float64 {
+	end := period.To
+	if end.After(now) {
+		end = now
+	}
+	if !end.After(period.From) {
+		return opening
+	}
+
+	var weighted float64
+	balance, at := opening, period.From
+	for _, tx := range transactions {
+		weighted += balance * tx.Timestamp.Sub(at).Seconds()
+		balance += tx.SignedAmount()
+		at = tx.Timestamp
+	}
+	weighted += balance * end.Sub(at).Seconds()
+
+	return weighted / end.Sub(period.From).Seconds()
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"bankapp/errors"
+	"bankapp/models"
+)
+
+// barWidth ширина самого длинного столбца диаграммы в символах
+const barWidth = 30
+
+// showStatistics показывает сводку по текущему счету за последние месяцы
+// с текстовыми диаграммами оборотов по типам операций и категориям
+func (app *BankApp) showStatistics(ctx context.Context) {
+	months := 3
+	if input := app.readLine("Количество месяцев (Enter - 3): "); input != "" {
+		n, err := strconv.Atoi(input)
+		if err != nil || n <= 0 {
+			app.printf("Ошибка: %v\n", errors.ErrInvalidAmount)
+			return
+		}
+		months = n
+	}
+
+	now := app.clock.Now()
+	period := models.Period{
+		From: time.Date(now.Year(), now.Month()-time.Month(months-1), 1, 0, 0, 0, 0, time.Local),
+		To:   time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.Local),
+	}
+
+	summary, err := app.analytics.GetSummary(ctx, app.currentAccount.AccountID(), period)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printHeader("Статистика")
+	for _, month := range summary.Months {
+		app.printf("\n%s: поступления %.2f, списания %.2f\n", month.Month, month.In, month.Out)
+
+		byType := make(map[string]float64, len(month.ByType))
+		for txType, amount := range month.ByType {
+			byType[string(txType)] = amount
+		}
+		app.printBars(byType)
+
+		if len(month.ByCategory) > 0 {
+			app.println("Расходы по категориям:")
+			byCategory := make(map[string]float64, len(month.ByCategory))
+			for category, amount := range month.ByCategory {
+				if category == "" {
+					category = app.tr.T("без категории")
+				}
+				byCategory[category] = amount
+			}
+			app.printBars(byCategory)
+		}
+	}
+
+	app.printf("\nСредний баланс: %.2f %s\n", summary.AverageBalance, app.currency)
+	app.printf("Чистый поток: %+.2f %s\n", summary.NetFlow, app.currency)
+
+	if len(summary.Largest) > 0 {
+		app.println("Крупнейшие операции:")
+		for _, tx := range summary.Largest {
+			app.printf("%s | %s | %.2f | %s\n", app.formatTime(tx.Timestamp), tx.Type, tx.Amount, tx.Message)
+		}
+	}
+}
+
+// printBars выводит значения столбчатой диаграммой от большего к меньшему
+func (app *BankApp) printBars(values map[string]float64) {
+	labels := make([]string, 0, len(values))
+	var maxValue float64
+	for label, value := range values {
+		labels = append(labels, label)
+		maxValue = math.Max(maxValue, value)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if values[labels[i]] != values[labels[j]] {
+			return values[labels[i]] > values[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+
+	for _, label := range labels {
+		width := 0
+		if maxValue > 0 {
+			width = int(math.Round(values[label] / maxValue * barWidth))
+		}
+		app.printf("  %-20s %-30s %10.2f\n", label, strings.Repeat("#", width), values[label])
+	}
+}
+
+// showBalanceHistory показывает баланс текущего счета на дату или
+// по дням за период в виде текстового графика
+func (app *BankApp) showBalanceHistory(ctx context.Context) {
+	app.println("1. Баланс на дату")
+	app.println("2. История баланса по дням")
+	app.println("3. Назад")
+	app.print("Выберите опцию: ")
+	app.scanner.Scan()
+
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "1":
+		at, err := app.readMoment("Дата (ГГГГ-ММ-ДД или ГГГГ-ММ-ДД ЧЧ:ММ): ")
+		if err != nil {
+			return
+		}
+
+		balance, err := app.currentAccount.GetBalanceAt(ctx, at)
+		if err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+		app.printf("Баланс на %s: %.2f %s\n", at.Format("2006-01-02 15:04"), balance, app.currency)
+	case "2":
+		from, err := app.readOptionalDate("Дата с (ГГГГ-ММ-ДД, Enter - 30 дней назад): ")
+		if err != nil {
+			return
+		}
+		to, err := app.readOptionalDate("Дата по (ГГГГ-ММ-ДД, Enter - сегодня): ")
+		if err != nil {
+			return
+		}
+
+		today := app.clock.Now()
+		today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+		if to.IsZero() {
+			to = today
+		}
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -29)
+		}
+
+		history, err := app.currentAccount.GetBalanceHistory(ctx, from, to.AddDate(0, 0, 1))
+		if err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+
+		var maxBalance float64
+		for _, point := range history {
+			maxBalance = math.Max(maxBalance, math.Abs(point.Balance))
+		}
+		for _, point := range history {
+			width := 0
+			if maxBalance > 0 {
+				width = int(math.Round(math.Abs(point.Balance) / maxBalance * barWidth))
+			}
+			app.printf("%s %-30s %10.2f\n", point.Date.Format("2006-01-02"), strings.Repeat("#", width), point.Balance)
+		}
+	}
+}
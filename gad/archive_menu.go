@@ -0,0 +1,106 @@
+package app
+
+import (
+	"bankapp/models"
+	"context"
+	"strings"
+)
+
+// manageArchive архивирует неактивные счета, показывает архив и возвращает счета из него
+func (app *BankApp) manageArchive(ctx context.Context) {
+	app.println("1. Архивировать неактивные счета")
+	app.println("2. Показать архив")
+	app.println("3. Вернуть счет из архива")
+	app.print("Выберите опцию: ")
+	app.scanner.Scan()
+
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "1":
+		app.archiveDormantAccounts(ctx)
+	case "2":
+		app.showArchivedAccounts(ctx)
+	case "3":
+		app.unarchiveAccount(ctx)
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+	}
+}
+
+// archiveDormantAccounts показывает предварительный отчет и после
+// подтверждения переносит неактивные счета в архив
+func (app *BankApp) archiveDormantAccounts(ctx context.Context) {
+	report, err := app.admin.ArchiveDormantAccounts(ctx, app.archive, true)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printArchiveReport(report)
+	if report.Archived == 0 {
+		return
+	}
+
+	app.print("Перенести счета в архив? (y/n): ")
+	app.scanner.Scan()
+	if strings.ToLower(strings.TrimSpace(app.scanner.Text())) != "y" {
+		app.println("Архивация отменена")
+		return
+	}
+
+	report, err = app.admin.ArchiveDormantAccounts(ctx, app.archive, false)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+	}
+
+	app.printArchiveReport(report)
+}
+
+// printArchiveReport выводит отчет об архивации неактивных счетов
+func (app *BankApp) printArchiveReport(report models.ArchiveReport) {
+	if report.DryRun {
+		app.printf("\n--- Предварительный расчет архивации (без операций %d дн.) ---\n", report.InactiveDays)
+	} else {
+		app.printf("\n--- Архивация счетов (без операций %d дн.) ---\n", report.InactiveDays)
+	}
+
+	for _, result := range report.Results {
+		line := app.tr.Sprintf("%s | %s | последняя операция: %s", result.AccountID, result.OwnerName, app.formatTime(result.LastActivity))
+		if result.SkipReason != "" {
+			line += app.tr.Sprintf(" | пропущено: %s", app.tr.T(result.SkipReason))
+		}
+		app.println(line)
+	}
+
+	app.printf("Счетов к архивации: %d, пропущено: %d\n", report.Archived, report.Skipped)
+}
+
+// showArchivedAccounts показывает счета в архиве
+func (app *BankApp) showArchivedAccounts(ctx context.Context) {
+	accounts, err := app.admin.ListArchivedAccounts(ctx)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	if len(accounts) == 0 {
+		app.println("Архив пуст")
+		return
+	}
+
+	app.printHeader("Архив счетов")
+	for _, account := range accounts {
+		app.printf("ID: %s | Владелец: %s | в архиве с %s\n", account.ID, account.OwnerName, app.formatTime(account.ArchivedAt))
+	}
+}
+
+// unarchiveAccount возвращает счет из архива
+func (app *BankApp) unarchiveAccount(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	if err := app.admin.UnarchiveAccount(ctx, accountID); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Счет %s возвращен из архива\n", accountID)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"path/filepath"
+	"strings"
+)
+
+// MaxAttachmentSize максимальный размер файла вложения в байтах
+const MaxAttachmentSize = 1 << 20
+
+// WithBlobStore подключает хранилище содержимого вложений
+func WithBlobStore(blobs interfaces.BlobStore) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.blobs = blobs
+	}
+}
+
+// AttachFile сохраняет небольшой файл в хранилище вложений и прикрепляет его к транзакции
+func (s *AccountServiceImpl) AttachFile(ctx context.Context, transactionID, name string, data []byte) (models.Attachment, error) {
+	if s.blobs == nil {
+		return models.Attachment{}, errors.ErrBlobStoreMissing
+	}
+
+	if len(data) > MaxAttachmentSize {
+		return models.Attachment{}, errors.ErrAttachmentTooLarge
+	}
+
+	tx, err := s.findTransaction(transactionID)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	name = filepath.Base(name)
+	key, err := s.blobs.Put(ctx, name, data)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	attachment := models.Attachment{
+		ID:        s.newID("ATT"),
+		Name:      name,
+		BlobKey:   key,
+		Size:      len(data),
+		CreatedAt: s.now(),
+	}
+
+	return s.addAttachment(ctx, tx, attachment)
+}
+
+// AttachReference прикрепляет к транзакции ссылку на внешний документ (путь или URL)
+func (s *AccountServiceImpl) AttachReference(ctx context.Context, transactionID, reference string) (models.Attachment, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return models.Attachment{}, errors.ErrAttachmentNotFound
+	}
+
+	tx, err := s.findTransaction(transactionID)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	attachment := models.Attachment{
+		ID:        s.newID("ATT"),
+		Name:      filepath.Base(reference),
+		Reference: reference,
+		CreatedAt: s.now(),
+	}
+
+	return s.addAttachment(ctx, tx, attachment)
+}
+
+// GetAttachment возвращает вложение транзакции и, для файлов, его содержимое
+func (s *AccountServiceImpl) GetAttachment(ctx context.Context, transactionID, attachmentID string) (models.Attachment, []byte, error) {
+	tx, err := s.findTransaction(transactionID)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+
+	for _, attachment := range tx.Attachments {
+		if attachment.ID != attachmentID {
+			continue
+		}
+
+		if attachment.BlobKey == "" {
+			return attachment, nil, nil
+		}
+
+		if s.blobs == nil {
+			return attachment, nil, errors.ErrBlobStoreMissing
+		}
+
+		data, err := s.blobs.Get(ctx, attachment.BlobKey)
+		return attachment, data, err
+	}
+
+	return models.Attachment{}, nil, errors.ErrAttachmentNotFound
+}
+
+// findTransaction ищет транзакцию счета по ID
+func (s *AccountServiceImpl) findTransaction(transactionID string) (*models.Transaction, error) {
+	for i := range s.account.Transactions {
+		if s.account.Transactions[i].ID == transactionID {
+			return &s.account.Transactions[i], nil
+		}
+	}
+
+	return nil, errors.ErrTransactionNotFound
+}
+
+// addAttachment добавляет вложение к транзакции и сохраняет счет
+func (s *AccountServiceImpl) addAttachment(ctx context.Context, tx *models.Transaction, attachment models.Attachment) (models.Attachment, error) {
+	tx.Attachments = append(tx.Attachments, attachment)
+	if err := s.saveAccount(ctx); err != nil {
+		return models.Attachment{}, err
+	}
+
+	return attachment, nil
+}
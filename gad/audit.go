@@ -0,0 +1,99 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+)
+
+type actorKey struct{}
+
+// WithActor возвращает контекст с именем пользователя, выполняющего действие
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает имя пользователя из контекста
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	return actor, ok && actor != ""
+}
+
+// AuditLoggerImpl реализация AuditLogger поверх хранилища журнала аудита
+type AuditLoggerImpl struct {
+	storage interfaces.AuditStorage
+	clock   models.Clock
+}
+
+// NewAuditLogger создает журнал аудита; время записей берется из clock
+func NewAuditLogger(storage interfaces.AuditStorage, clock models.Clock) interfaces.AuditLogger {
+	return &AuditLoggerImpl{
+		storage: storage,
+		clock:   clock,
+	}
+}
+
+// Record дополняет запись временем, пользователем, доверенностью и correlation_id
+// из контекста и сохраняет ее
+func (l *AuditLoggerImpl) Record(ctx context.Context, entry models.AuditEntry) error {
+	entry.Timestamp = l.clock.Now()
+	if actor, ok := ActorFromContext(ctx); ok && entry.Actor == "" {
+		entry.Actor = actor
+	}
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		entry.CorrelationID = id
+	}
+	if grantID, ok := GrantFromContext(ctx); ok {
+		entry.GrantID = grantID
+	}
+
+	// Запись аудита не должна теряться из-за отмены контекста операции
+	return l.storage.AppendAuditEntry(context.WithoutCancel(ctx), &entry)
+}
+
+// Query возвращает страницу записей журнала аудита
+func (l *AuditLoggerImpl) Query(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, int, error) {
+	return l.storage.QueryAuditEntries(ctx, filter)
+}
+
+// WithAuditLogger подключает журнал аудита к операциям счета
+func WithAuditLogger(audit interfaces.AuditLogger) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.audit = audit
+	}
+}
+
+// auditOperation записывает в журнал аудита действие над счетом сервиса
+func (s *AccountServiceImpl) auditOperation(ctx context.Context, action string, amount float64, details string, err error) {
+	recordAudit(ctx, s.audit, models.AuditEntry{
+		Action:    action,
+		AccountID: s.account.ID,
+		Amount:    amount,
+		Details:   details,
+	}, err)
+}
+
+// auditAdmin записывает в журнал аудита административное действие над счетом
+func (s *AdminServiceImpl) auditAdmin(ctx context.Context, action, accountID string, amount float64, details string, err error) {
+	recordAudit(ctx, s.audit, models.AuditEntry{
+		Action:    action,
+		AccountID: accountID,
+		Amount:    amount,
+		Details:   details,
+	}, err)
+}
+
+// recordAudit записывает результат действия в журнал аудита, если он подключен.
+// Ошибка записи не влияет на результат уже выполненного действия.
+func recordAudit(ctx context.Context, audit interfaces.AuditLogger, entry models.AuditEntry, err error) {
+	if audit == nil {
+		return
+	}
+
+	entry.Success = err == nil
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	_ = audit.Record(ctx, entry)
+}
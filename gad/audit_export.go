@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// auditExportPage количество записей журнала, читаемых за один запрос при выгрузке
+const auditExportPage = 500
+
+// auditRecordJSON представление записи аудита для JSON Lines
+type auditRecordJSON struct {
+	Sequence      uint64    `json:"seq"`
+	Timestamp     time.Time `json:"timestamp"`
+	Actor         string    `json:"actor"`
+	Action        string    `json:"action"`
+	AccountID     string    `json:"account_id,omitempty"`
+	Amount        float64   `json:"amount,omitempty"`
+	Details       string    `json:"details,omitempty"`
+	Success       bool      `json:"success"`
+	Error         string    `json:"error,omitempty"`
+	CorrelationID string    `json:"correlation_id,omitempty"`
+	GrantID       string    `json:"grant_id,omitempty"`
+}
+
+// ExportAuditLog выгружает из журнала аудита записи с номером больше
+// afterSequence в формате JSON Lines или CEF. Возвращает номер последней
+// выгруженной записи для следующей инкрементальной выгрузки.
+func ExportAuditLog(ctx context.Context, w io.Writer, audit interfaces.AuditLogger, format models.ExportFormat, afterSequence uint64) (uint64, error) {
+	last := afterSequence
+	// Номера записей идут подряд с единицы, поэтому выгрузка начинается
+	// сразу с записи afterSequence+1
+	filter := models.AuditFilter{Offset: int(afterSequence), Limit: auditExportPage}
+	for {
+		entries, total, err := audit.Query(ctx, filter)
+		if err != nil {
+			return last, err
+		}
+
+		last, err = ExportAuditRecords(w, format, entries, last)
+		if err != nil {
+			return last, err
+		}
+
+		filter.Offset += len(entries)
+		if len(entries) == 0 || filter.Offset >= total {
+			return last, nil
+		}
+	}
+}
+
+// ExportAuditRecords выгружает записи аудита в формате JSON Lines или CEF
+// по одной записи на строку. Возвращает номер последней выгруженной записи,
+// который передается как afterSequence при следующей инкрементальной выгрузке.
+func ExportAuditRecords(w io.Writer, format models.ExportFormat, records []models.AuditEntry, afterSequence uint64) (uint64, error) {
+	last := afterSequence
+	for _, record := range records {
+		if record.Sequence <= afterSequence {
+			continue
+		}
+
+		var line string
+		switch format {
+		case models.JSONLinesFormat:
+			data, err := json.Marshal(toAuditRecordJSON(record))
+			if err != nil {
+				return last, err
+			}
+			line = string(data)
+		case models.CEFFormat:
+			line = formatCEF(record)
+		default:
+			return last, errors.ErrUnsupportedFormat
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return last, err
+		}
+
+		last = record.Sequence
+	}
+
+	return last, nil
+}
+
+// toAuditRecordJSON преобразует запись аудита в JSON-представление
+func toAuditRecordJSON(record models.AuditEntry) auditRecordJSON {
+	return auditRecordJSON{
+		Sequence:      record.Sequence,
+		Timestamp:     record.Timestamp,
+		Actor:         record.Actor,
+		Action:        record.Action,
+		AccountID:     record.AccountID,
+		Amount:        record.Amount,
+		Details:       record.Details,
+		Success:       record.Success,
+		Error:         record.Error,
+		CorrelationID: record.CorrelationID,
+		GrantID:       record.GrantID,
+	}
+}
+
+// formatCEF форматирует запись аудита в ArcSight Common Event Format.
+// Неуспешные действия получают повышенную важность.
+func formatCEF(record models.AuditEntry) string {
+	outcome, severity := "success", 3
+	if !record.Success {
+		outcome, severity = "failure", 7
+	}
+
+	extension := []string{
+		"rt=" + cefExtension(fmt.Sprint(record.Timestamp.UnixMilli())),
+		"suser=" + cefExtension(record.Actor),
+		"externalId=" + cefExtension(fmt.Sprint(record.Sequence)),
+		"act=" + cefExtension(record.Action),
+		"outcome=" + outcome,
+		"cs1Label=accountId",
+		"cs1=" + cefExtension(record.AccountID),
+		"cfp1Label=amount",
+		"cfp1=" + fmt.Sprintf("%.2f", record.Amount),
+	}
+	if record.Details != "" {
+		extension = append(extension, "msg="+cefExtension(record.Details))
+	}
+	if record.Error != "" {
+		extension = append(extension, "reason="+cefExtension(record.Error))
+	}
+	if record.CorrelationID != "" {
+		extension = append(extension, "cs2Label=correlationId", "cs2="+cefExtension(record.CorrelationID))
+	}
+	if record.GrantID != "" {
+		extension = append(extension, "cs3Label=grantId", "cs3="+cefExtension(record.GrantID))
+	}
+
+	return fmt.Sprintf("CEF:0|%s|%s|%s|%s|%s|%d|%s",
+		cefHeader("bankapp"),
+		cefHeader("audit"),
+		cefHeader("1.0"),
+		cefHeader(strings.ToUpper(record.Action)),
+		cefHeader(record.Action),
+		severity,
+		strings.Join(extension, " "))
+}
+
+// cefHeader экранирует значение поля заголовка CEF
+func cefHeader(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ").Replace(value)
+}
+
+// cefExtension экранирует значение поля расширения CEF
+func cefExtension(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`).Replace(value)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"crypto/subtle"
+	stderrors "errors"
+	"strings"
+)
+
+// minPasswordLength минимальная длина пароля пользователя
+const minPasswordLength = 6
+
+// AuthServiceImpl реализация AuthService
+type AuthServiceImpl struct {
+	storage interfaces.Storage
+	ids     models.IDGenerator
+	clock   models.Clock
+	audit   interfaces.AuditLogger
+}
+
+// NewAuthService создает сервис аутентификации пользователей
+func NewAuthService(storage interfaces.Storage, ids models.IDGenerator, clock models.Clock, audit interfaces.AuditLogger) interfaces.AuthService {
+	return &AuthServiceImpl{
+		storage: storage,
+		ids:     ids,
+		clock:   clock,
+		audit:   audit,
+	}
+}
+
+// Register регистрирует пользователя. Первый зарегистрированный
+// пользователь получает роль администратора, остальные - роль клиента.
+func (s *AuthServiceImpl) Register(ctx context.Context, username, password string) (user *models.User, err error) {
+	username = strings.TrimSpace(username)
+	defer func() {
+		recordAudit(ctx, s.audit, models.AuditEntry{Actor: username, Action: "register"}, err)
+	}()
+
+	if username == "" {
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	if len(password) < minPasswordLength {
+		return nil, errors.ErrWeakPassword
+	}
+
+	if _, err := s.storage.FindUserByUsername(ctx, username); err == nil {
+		return nil, errors.ErrUserExists
+	} else if !stderrors.Is(err, errors.ErrUserNotFound) {
+		return nil, err
+	}
+
+	users, err := s.storage.GetAllUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	role := models.CustomerRole
+	if len(users) == 0 {
+		role = models.AdminRole
+	}
+
+	salt, err := newSalt()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := hashSecret(password, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	user = models.NewUser(username, role, s.ids, s.clock)
+	user.PasswordSalt = salt
+	user.PasswordHash = hash
+
+	if err := s.storage.SaveUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Login проверяет имя пользователя и пароль
+func (s *AuthServiceImpl) Login(ctx context.Context, username, password string) (user *models.User, err error) {
+	username = strings.TrimSpace(username)
+	defer func() {
+		recordAudit(ctx, s.audit, models.AuditEntry{Actor: username, Action: "login"}, err)
+	}()
+
+	user, err = s.storage.FindUserByUsername(ctx, username)
+	if stderrors.Is(err, errors.ErrUserNotFound) {
+		return nil, errors.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := hashSecret(password, user.PasswordSalt)
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare(hash, user.PasswordHash) != 1 {
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	return user, nil
+}
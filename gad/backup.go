@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// BackupSchemaVersion версия формата резервной копии
+const BackupSchemaVersion = 1
+
+// backupFile резервная копия: заголовок и данные, контрольная сумма SHA-256
+// считается по байтам Payload в том виде, в каком они записаны в файл
+type backupFile struct {
+	SchemaVersion int             `json:"schema_version"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Checksum      string          `json:"checksum"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// backupPayload состояние банка: пользователи, счета с транзакциями и
+// журнал событий со снимками балансов
+type backupPayload struct {
+	Users     []*models.User           `json:"users"`
+	Accounts  []*models.Account        `json:"accounts"`
+	Events    []models.AccountEvent    `json:"events"`
+	Snapshots []models.BalanceSnapshot `json:"snapshots"`
+}
+
+// ExportBackup записывает полное состояние хранилищ в резервную копию.
+// Копия содержит хэши паролей и PIN-кодов и должна храниться как секрет.
+// Счета и события переносятся без потерь, кроме Version, которую заново
+// назначает хранилище при восстановлении; псевдонимы и журнал аудита в
+// копию не входят.
+func ExportBackup(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage, clock models.Clock, w io.Writer) error {
+	users, err := storage.GetAllUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	accounts, _, err := storage.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	payload := backupPayload{Users: users, Accounts: accounts}
+	for _, account := range accounts {
+		events, err := ledger.LoadEvents(ctx, account.ID, 0)
+		if err != nil {
+			return err
+		}
+		payload.Events = append(payload.Events, events...)
+
+		snapshot, err := ledger.LoadSnapshot(ctx, account.ID)
+		switch {
+		case err == nil:
+			payload.Snapshots = append(payload.Snapshots, snapshot)
+		case !stderrors.Is(err, errors.ErrSnapshotNotFound):
+			return err
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	checksum := sha256.Sum256(data)
+	return json.NewEncoder(w).Encode(backupFile{
+		SchemaVersion: BackupSchemaVersion,
+		CreatedAt:     clock.Now(),
+		Checksum:      hex.EncodeToString(checksum[:]),
+		Payload:       data,
+	})
+}
+
+// ImportBackup восстанавливает состояние из резервной копии после проверки
+// версии формата и контрольной суммы. В режиме dryRun хранилища не
+// изменяются, отчет показывает, что было бы восстановлено. Если имя
+// пользователя из копии занято пользователем с другим ID, восстановление
+// отклоняется с ErrBackupUserConflict до изменения хранилищ.
+func ImportBackup(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage, r io.Reader, dryRun bool) (models.RestoreReport, error) {
+	var file backupFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return models.RestoreReport{}, err
+	}
+
+	report := models.RestoreReport{
+		SchemaVersion: file.SchemaVersion,
+		CreatedAt:     file.CreatedAt,
+		DryRun:        dryRun,
+	}
+
+	if file.SchemaVersion != BackupSchemaVersion {
+		return report, fmt.Errorf("%w: %d", errors.ErrBackupVersion, file.SchemaVersion)
+	}
+
+	checksum := sha256.Sum256(file.Payload)
+	if hex.EncodeToString(checksum[:]) != file.Checksum {
+		return report, errors.ErrBackupChecksum
+	}
+
+	var payload backupPayload
+	if err := json.Unmarshal(file.Payload, &payload); err != nil {
+		return report, err
+	}
+
+	// Пользователи сопоставляются по ID. Имя, занятое пользователем с
+	// другим ID, - конфликт: счета копии нельзя привязать ни к одному из них.
+	var users []*models.User
+	for _, user := range payload.Users {
+		if _, err := storage.LoadUser(ctx, user.ID); err == nil {
+			report.ExistingUsers = append(report.ExistingUsers, user.Username)
+			continue
+		} else if !stderrors.Is(err, errors.ErrUserNotFound) {
+			return report, err
+		}
+
+		existing, err := storage.FindUserByUsername(ctx, user.Username)
+		switch {
+		case err == nil:
+			report.UserConflicts = append(report.UserConflicts, fmt.Sprintf("%s (%s, в копии %s)", user.Username, existing.ID, user.ID))
+			continue
+		case !stderrors.Is(err, errors.ErrUserNotFound):
+			return report, err
+		}
+
+		users = append(users, user)
+	}
+	report.Users = len(users)
+
+	var accounts []*models.Account
+	restored := make(map[string]bool, len(payload.Accounts))
+	for _, account := range payload.Accounts {
+		if _, err := storage.LoadAccount(ctx, account.ID); err == nil {
+			report.SkippedAccounts = append(report.SkippedAccounts, account.ID)
+			continue
+		}
+
+		restored[account.ID] = true
+		accounts = append(accounts, account)
+	}
+	report.Accounts = len(accounts)
+
+	for _, event := range payload.Events {
+		if restored[event.AccountID] {
+			report.Events++
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+	if len(report.UserConflicts) > 0 {
+		return report, fmt.Errorf("%w: %s", errors.ErrBackupUserConflict, strings.Join(report.UserConflicts, ", "))
+	}
+
+	for _, user := range users {
+		if err := storage.SaveUser(ctx, user); err != nil {
+			return report, err
+		}
+	}
+
+	if err := saveAccounts(ctx, storage, accounts); err != nil {
+		return report, err
+	}
+
+	for _, event := range payload.Events {
+		if !restored[event.AccountID] {
+			continue
+		}
+
+		if err := ledger.AppendEvent(ctx, &event); err != nil {
+			return report, err
+		}
+	}
+
+	for _, snapshot := range payload.Snapshots {
+		if !restored[snapshot.AccountID] {
+			continue
+		}
+
+		if err := ledger.SaveSnapshot(ctx, snapshot); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// saveAccounts сохраняет восстановленные счета одной пачкой, если хранилище
+// это умеет, иначе по одному
+func saveAccounts(ctx context.Context, storage interfaces.Storage, accounts []*models.Account) error {
+	if batch, ok := storage.(interfaces.BatchStorage); ok {
+		return batch.SaveAccounts(ctx, accounts...)
+	}
+
+	for _, account := range accounts {
+		if err := storage.SaveAccount(ctx, account); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,258 +1,1420 @@
-package app
-
-import (
-	"bufio"
-	"fmt"
-	"os"
-	"strconv"
-	"strings"
-
-	"bankapp/errors"
-	"bankapp/interfaces"
-	"bankapp/models"
-	"bankapp/services"
-	"bankapp/storage"
-)
-
-// BankApp структура банковского приложения
-type BankApp struct {
-	storage        interfaces.Storage
-	accounts       map[string]interfaces.AccountService
-	currentAccount interfaces.AccountService
-	scanner        *bufio.Scanner
-}
-
-// NewBankApp создает новое банковское приложение
-func NewBankApp() *BankApp {
-	storage := storage.NewMemoryStorage()
-	return &BankApp{
-		storage:  storage,
-		accounts: make(map[string]interfaces.AccountService),
-		scanner:  bufio.NewScanner(os.Stdin),
-	}
-}
-
-// Run запускает приложение
-func (app *BankApp) Run() {
-	fmt.Println("=== Банковское приложение ===")
-
-	for {
-		if app.currentAccount == nil {
-			app.showMainMenu()
-		} else {
-			app.showAccountMenu()
-		}
-	}
-}
-
-// showMainMenu показывает главное меню
-func (app *BankApp) showMainMenu() {
-	fmt.Println("\n--- Главное меню ---")
-	fmt.Println("1. Создать счет")
-	fmt.Println("2. Выбрать счет")
-	fmt.Println("3. Показать все счета")
-	fmt.Println("4. Выйти")
-	fmt.Print("Выберите опцию: ")
-
-	app.scanner.Scan()
-	choice := app.scanner.Text()
-
-	switch choice {
-	case "1":
-		app.createAccount()
-	case "2":
-		app.selectAccount()
-	case "3":
-		app.showAllAccounts()
-	case "4":
-		fmt.Println("До свидания!")
-		os.Exit(0)
-	default:
-		fmt.Println("Неверный выбор. Попробуйте снова.")
-	}
-}
-
-// showAccountMenu показывает меню счета
-func (app *BankApp) showAccountMenu() {
-	fmt.Println("\n--- Меню счета ---")
-	fmt.Println("1. Пополнить счет")
-	fmt.Println("2. Снять средства")
-	fmt.Println("3. Перевести другому счету")
-	fmt.Println("4. Просмотреть баланс")
-	fmt.Println("5. Получить выписку")
-	fmt.Println("6. Вернуться в главное меню")
-	fmt.Print("Выберите опцию: ")
-
-	app.scanner.Scan()
-	choice := app.scanner.Text()
-
-	switch choice {
-	case "1":
-		app.deposit()
-	case "2":
-		app.withdraw()
-	case "3":
-		app.transfer()
-	case "4":
-		app.showBalance()
-	case "5":
-		app.showStatement()
-	case "6":
-		app.currentAccount = nil
-		fmt.Println("Возврат в главное меню...")
-	default:
-		fmt.Println("Неверный выбор. Попробуйте снова.")
-	}
-}
-
-// createAccount создает новый счет
-func (app *BankApp) createAccount() {
-	fmt.Print("Введите имя владельца счета: ")
-	app.scanner.Scan()
-	ownerName := strings.TrimSpace(app.scanner.Text())
-
-	if ownerName == "" {
-		fmt.Println("Имя владельца не может быть пустым")
-		return
-	}
-
-	account := models.NewAccount(ownerName)
-	accountService := services.NewAccountService(account, app.storage)
-
-	// Сохраняем счет
-	if err := app.storage.SaveAccount(account); err != nil {
-		fmt.Printf("Ошибка при создании счета: %v\n", err)
-		return
-	}
-
-	app.accounts[account.ID] = accountService
-
-	fmt.Printf("Счет успешно создан!\n")
-	fmt.Printf("ID счета: %s\n", account.ID)
-	fmt.Printf("Владелец: %s\n", account.OwnerName)
-}
-
-// selectAccount выбирает счет для работы
-func (app *BankApp) selectAccount() {
-	fmt.Print("Введите ID счета: ")
-	app.scanner.Scan()
-	accountID := strings.TrimSpace(app.scanner.Text())
-
-	accountService, exists := app.accounts[accountID]
-	if !exists {
-		// Попробуем загрузить из хранилища
-		account, err := app.storage.LoadAccount(accountID)
-		if err != nil {
-			fmt.Printf("Ошибка: %v\n", errors.ErrAccountNotFound)
-			return
-		}
-		accountService = services.NewAccountService(account, app.storage)
-		app.accounts[accountID] = accountService
-	}
-
-	app.currentAccount = accountService
-	fmt.Printf("Счет %s выбран для работы\n", accountID)
-}
-
-// showAllAccounts показывает все счета
-func (app *BankApp) showAllAccounts() {
-	accounts, err := app.storage.GetAllAccounts()
-	if err != nil {
-		fmt.Printf("Ошибка при получении счетов: %v\n", err)
-		return
-	}
-
-	if len(accounts) == 0 {
-		fmt.Println("Счета не найдены")
-		return
-	}
-
-	fmt.Println("\n--- Все счета ---")
-	for _, account := range accounts {
-		fmt.Printf("ID: %s | Владелец: %s | Баланс: %.2f\n",
-			account.ID, account.OwnerName, account.Balance)
-	}
-}
-
-// deposit пополняет счет
-func (app *BankApp) deposit() {
-	amount, err := app.readAmount("Введите сумму для пополнения: ")
-	if err != nil {
-		return
-	}
-
-	if err := app.currentAccount.Deposit(amount); err != nil {
-		fmt.Printf("Ошибка при пополнении: %v\n", err)
-		return
-	}
-
-	fmt.Printf("Счет успешно пополнен на %.2f\n", amount)
-}
-
-// withdraw снимает средства
-func (app *BankApp) withdraw() {
-	amount, err := app.readAmount("Введите сумму для снятия: ")
-	if err != nil {
-		return
-	}
-
-	if err := app.currentAccount.Withdraw(amount); err != nil {
-		fmt.Printf("Ошибка при снятии: %v\n", err)
-		return
-	}
-
-	fmt.Printf("Со счета успешно снято %.2f\n", amount)
-}
-
-// transfer переводит средства другому счету
-func (app *BankApp) transfer() {
-	amount, err := app.readAmount("Введите сумму для перевода: ")
-	if err != nil {
-		return
-	}
-
-	fmt.Print("Введите ID целевого счета: ")
-	app.scanner.Scan()
-	toAccountID := strings.TrimSpace(app.scanner.Text())
-
-	// Загружаем целевой счет
-	toAccount, err := app.storage.LoadAccount(toAccountID)
-	if err != nil {
-		fmt.Printf("Ошибка: %v\n", err)
-		return
-	}
-
-	if err := app.currentAccount.Transfer(toAccount, amount); err != nil {
-		fmt.Printf("Ошибка при переводе: %v\n", err)
-		return
-	}
-
-	fmt.Printf("Успешно переведено %.2f на счет %s\n", amount, toAccountID)
-}
-
-// showBalance показывает баланс
-func (app *BankApp) showBalance() {
-	balance := app.currentAccount.GetBalance()
-	fmt.Printf("Текущий баланс: %.2f\n", balance)
-}
-
-// showStatement показывает выписку
-func (app *BankApp) showStatement() {
-	statement := app.currentAccount.GetStatement()
-	fmt.Println(statement)
-}
-
-// readAmount читает сумму из ввода
-func (app *BankApp) readAmount(prompt string) (float64, error) {
-	fmt.Print(prompt)
-	app.scanner.Scan()
-	input := strings.TrimSpace(app.scanner.Text())
-
-	amount, err := strconv.ParseFloat(input, 64)
-	if err != nil || amount <= 0 {
-		fmt.Printf("Ошибка: %v\n", errors.ErrInvalidAmount)
-		return 0, errors.ErrInvalidAmount
-	}
-
-	return amount, nil
-}
+package app
+
+import (
+	"bufio"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"bankapp/config"
+	"bankapp/errors"
+	"bankapp/expr"
+	"bankapp/i18n"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"bankapp/services"
+	"bankapp/storage"
+)
+
+// BankApp структура банковского приложения
+type BankApp struct {
+	storage        interfaces.Storage
+	ledger         interfaces.LedgerStorage
+	blobs          interfaces.BlobStore
+	fees           interfaces.FeePolicy
+	features       interfaces.FeatureFlags
+	ids            models.IDGenerator
+	clock          models.Clock
+	logger         *slog.Logger
+	audit          interfaces.AuditLogger
+	events         *services.EventBus
+	observers      []interfaces.Observer
+	webhook        interfaces.Observer
+	outbox         interfaces.OutboxStorage
+	statements     interfaces.StatementSender
+	emailSender    interfaces.EmailSender
+	currentAccount interfaces.AccountService
+	currentUser    *models.User
+	prefs          models.UserPreferences
+	auth           interfaces.AuthService
+	admin          interfaces.AdminService
+	search         interfaces.SearchService
+	analytics      interfaces.AnalyticsService
+	aliases        interfaces.AliasService
+	switches       interfaces.OperationSwitches
+	postings       interfaces.LedgerService
+	scanner        *lineInput
+
+	// Язык приложения и переводчик сообщений текущего пользователя
+	locale string
+	tr     *i18n.Translator
+
+	// Валюта счетов и лимиты, назначаемые новым счетам
+	currency string
+	limits   config.LimitsConfig
+
+	// Ограничения операций и правила оценки риска на языке выражений
+	limitRules []models.LimitRule
+	riskRules  []models.RiskRule
+
+	// statementPageLines порог в строках, после которого выписка выводится постранично
+	statementPageLines int
+
+	// statementFormats оформление выписок по форматам из конфигурации
+	statementFormats map[string]models.StatementLayout
+
+	// sweep правила перевода малых остатков неактивных счетов на пул-счет
+	sweep models.SweepPolicy
+
+	// archive правила архивации неактивных счетов
+	archive models.ArchivePolicy
+
+	// undoWindow время, в течение которого можно отменить последнюю операцию
+	undoWindow time.Duration
+
+	// txTypes типы транзакций, зарегистрированные оператором
+	txTypes *services.TransactionTypeRegistry
+
+	// receiptKey ключ подписи квитанций
+	receiptKey []byte
+
+	// Правила комиссий и правила нового движка комиссий; политика
+	// собирается в NewBankApp, когда часы приложения уже известны
+	feeRules     []models.FeeRule
+	feeRulesNext []models.FeeRule
+
+	// ledgerOperators администраторы, которым разрешены прямые проводки по счетам
+	ledgerOperators []string
+
+	// Проверка согласованности счетов при запуске и режим исправления
+	startupCheck  bool
+	startupRepair bool
+
+	// stopped пользователь выбрал выход; главный цикл завершается и
+	// shutdown освобождает ресурсы ровно один раз
+	stopped      bool
+	shutdownOnce sync.Once
+}
+
+const (
+	// pageSize количество записей на одной странице списков
+	pageSize = 10
+	// miniStatementSize количество операций в мини-выписке
+	miniStatementSize = 10
+	// defaultStatementPageLines порог постраничного вывода выписки по умолчанию
+	defaultStatementPageLines = 50
+	// exitInterrupted код выхода при завершении по сигналу, как у оболочки для SIGINT
+	exitInterrupted = 130
+	// exportChunkSize количество транзакций в одной части выгрузки в файл
+	exportChunkSize = 1000
+)
+
+// Option настройка банковского приложения
+type Option func(*BankApp)
+
+// WithStatementPageLines задает количество строк выписки, после которого
+// она выводится постранично. Значение 0 отключает постраничный вывод.
+func WithStatementPageLines(lines int) Option {
+	return func(app *BankApp) {
+		app.statementPageLines = lines
+	}
+}
+
+// WithLogger задает логгер операций и ошибок хранилища. К записям
+// добавляется correlation_id действия пользователя.
+func WithLogger(logger *slog.Logger) Option {
+	return func(app *BankApp) {
+		app.logger = slog.New(services.WithCorrelation(logger.Handler()))
+	}
+}
+
+// WithObserver подписывает наблюдателя на уведомления о событиях по счетам
+func WithObserver(observer interfaces.Observer) Option {
+	return func(app *BankApp) {
+		app.observers = append(app.observers, observer)
+	}
+}
+
+// WithWebhook подписывает вебхук на уведомления с записью в журнал исходящих
+// уведомлений, из которого администратор может доставить их повторно
+func WithWebhook(observer interfaces.Observer) Option {
+	return func(app *BankApp) {
+		app.webhook = observer
+	}
+}
+
+// WithStatementSender задает канал доставки выписок клиентам
+func WithStatementSender(sender interfaces.StatementSender) Option {
+	return func(app *BankApp) {
+		app.statements = sender
+	}
+}
+
+// WithInput задает источник ввода вместо stdin, например для управления
+// приложением из скрипта или другого интерфейса
+func WithInput(source interfaces.InputSource) Option {
+	return func(app *BankApp) {
+		app.scanner = &lineInput{source: source}
+	}
+}
+
+// WithEmailSender задает отправку писем: выписки по запросу клиента и
+// ежемесячная рассылка подписанным счетам при запуске
+func WithEmailSender(sender interfaces.EmailSender) Option {
+	return func(app *BankApp) {
+		app.emailSender = sender
+	}
+}
+
+// WithStorage задает хранилище счетов и пользователей вместо хранилища в памяти
+func WithStorage(store interfaces.Storage) Option {
+	return func(app *BankApp) {
+		app.storage = store
+	}
+}
+
+// WithIDGenerator задает генератор идентификаторов счетов, пользователей и транзакций
+func WithIDGenerator(ids models.IDGenerator) Option {
+	return func(app *BankApp) {
+		app.ids = ids
+	}
+}
+
+// WithClock задает часы приложения вместо системных
+func WithClock(clock models.Clock) Option {
+	return func(app *BankApp) {
+		app.clock = clock
+	}
+}
+
+// WithFeeRules задает набор правил комиссий вместо набора по умолчанию
+func WithFeeRules(rules []models.FeeRule) Option {
+	return func(app *BankApp) {
+		app.feeRules = rules
+	}
+}
+
+// WithConfig применяет загруженную конфигурацию: комиссии, лимиты новых
+// счетов, валюту и порог постраничного вывода выписки
+func WithConfig(cfg config.Config) Option {
+	return func(app *BankApp) {
+		app.features = services.NewFeatureFlags(cfg.Features)
+		app.feeRules = cfg.Fees
+		app.feeRulesNext = cfg.FeesNext
+		app.currency = cfg.Currency
+		app.locale = cfg.Locale
+		app.limits = cfg.Limits
+		app.limitRules = cfg.LimitRules
+		app.riskRules = cfg.RiskRules
+		app.statementPageLines = cfg.StatementPageLines
+		app.statementFormats = cfg.StatementFormats
+		app.sweep = cfg.Sweep
+		app.archive = cfg.Archive
+		app.undoWindow = time.Duration(cfg.UndoWindowSeconds) * time.Second
+		app.txTypes = services.NewTransactionTypeRegistry(cfg.TransactionTypes)
+		// Ключ уже проверен в cfg.Validate
+		app.receiptKey, _ = cfg.ReceiptSecret()
+	}
+}
+
+// WithStartupCheck включает проверку согласованности счетов при запуске.
+// При repair несогласованные счета помещаются в карантин.
+func WithStartupCheck(repair bool) Option {
+	return func(app *BankApp) {
+		app.startupCheck = true
+		app.startupRepair = repair
+	}
+}
+
+// WithLedgerOperators разрешает перечисленным администраторам прямые
+// проводки по счетам. Без операторов пункт меню отклоняет любую проводку.
+func WithLedgerOperators(operators ...string) Option {
+	return func(app *BankApp) {
+		app.ledgerOperators = operators
+	}
+}
+
+// NewBankApp создает новое банковское приложение
+func NewBankApp(opts ...Option) *BankApp {
+	defaults := config.Default()
+	app := &BankApp{
+		ledger:             storage.NewMemoryLedgerStorage(),
+		blobs:              storage.NewMemoryBlobStore(),
+		feeRules:           services.DefaultFeeRules,
+		features:           services.NewFeatureFlags(nil),
+		outbox:             storage.NewMemoryOutboxStorage(),
+		ids:                models.DefaultIDGenerator,
+		clock:              models.DefaultClock,
+		logger:             slog.New(slog.DiscardHandler),
+		prefs:              models.DefaultPreferences(),
+		scanner:            &lineInput{source: bufio.NewScanner(os.Stdin)},
+		statementPageLines: defaultStatementPageLines,
+		currency:           defaults.Currency,
+		locale:             defaults.Locale,
+		limits:             defaults.Limits,
+	}
+
+	for _, opt := range opts {
+		opt(app)
+	}
+	app.setLanguage(app.locale)
+
+	app.fees = services.NewRuleFeePolicy(app.feeRules, app.clock)
+	if len(app.feeRulesNext) > 0 {
+		app.fees = services.NewFlaggedFeePolicy(app.features, models.NewFeeEngineFlag, services.NewRuleFeePolicy(app.feeRulesNext, app.clock), app.fees)
+	}
+
+	if app.receiptKey == nil {
+		app.receiptKey = services.NewReceiptKey()
+		app.logger.Warn("ключ подписи квитанций не задан, квитанции проверяются только до перезапуска")
+	}
+
+	app.events = services.NewEventBus(app.logger, app.clock)
+	for _, observer := range app.observers {
+		app.events.Subscribe(observer)
+	}
+	if app.webhook != nil {
+		app.events.Subscribe(services.NewOutboxNotifier(app.outbox, app.webhook, app.clock))
+	}
+
+	if app.storage == nil {
+		app.storage = storage.NewMemoryStorage()
+	}
+	// Хранилище с собственным журналом (например, file://) держит журнал
+	// событий, псевдонимы и аудит рядом со счетами, чтобы после перезапуска
+	// балансы сходились с журналом
+	aliasStorage := storage.NewMemoryAliasStorage()
+	auditStorage := storage.NewMemoryAuditStorage()
+	if journal, ok := app.storage.(interfaces.JournalStorage); ok {
+		app.ledger = journal.Ledger()
+		aliasStorage = journal.Aliases()
+		auditStorage = journal.AuditLog()
+	}
+	app.audit = services.NewAuditLogger(auditStorage, app.clock)
+	app.storage = storage.NewLoggingStorage(app.storage, app.logger)
+	app.auth = services.NewAuthService(app.storage, app.ids, app.clock, app.audit)
+	app.admin = services.NewAdminService(app.storage, app.ledger, app.ids, app.audit, services.WithAdminClock(app.clock))
+	app.search = services.NewSearchService(app.storage)
+	app.analytics = services.NewAnalyticsService(app.storage, app.clock)
+	app.aliases = services.NewAliasService(app.storage, aliasStorage, app.clock, app.audit)
+	app.switches = services.NewOperationSwitches(app.audit, app.clock)
+	app.postings = services.NewLedgerService(app.storage, app.ledger, app.ids, app.clock, app.audit, app.ledgerOperators...)
+
+	return app
+}
+
+// newAccountService создает сервис счета с зависимостями приложения
+func (app *BankApp) newAccountService(account *models.Account) interfaces.AccountService {
+	opts := []services.AccountOption{
+		services.WithBlobStore(app.blobs),
+		services.WithFeePolicy(app.fees),
+		services.WithIDGenerator(app.ids),
+		services.WithClock(app.clock),
+		services.WithLogger(app.logger),
+		services.WithAuditLogger(app.audit),
+		services.WithEventBus(app.events),
+		services.WithDateFormat(app.prefs.DateFormat),
+		services.WithTranslator(app.tr),
+		services.WithLimitRules(app.limitRules),
+		services.WithTransactionTypes(app.txTypes),
+		services.WithOperationSwitches(app.switches),
+		services.WithReceiptKey(app.receiptKey),
+		services.WithUndoWindow(app.undoWindow),
+	}
+	if app.statements != nil {
+		opts = append(opts, services.WithStatementSender(app.statements))
+	}
+	if app.emailSender != nil {
+		opts = append(opts, services.WithStatementMailer(app.newStatementMailer()))
+	}
+	if _, ok := app.statementFormats[services.TextStatementFormat]; ok {
+		opts = append(opts, services.WithStatementRenderer(app.statementRenderer(services.TextStatementFormat)))
+	}
+	if len(app.riskRules) > 0 {
+		opts = append(opts, services.WithRiskScorer(services.NewRuleRiskScorer(app.storage, app.riskRules, app.clock), services.DefaultRiskPolicy))
+	}
+
+	return services.NewAccountService(account, app.storage, app.ledger, opts...)
+}
+
+// Run запускает приложение. Конец ввода завершает работу так же, как
+// пункт "Выйти"; при ошибке чтения ввода возвращается ErrInputFailed.
+func (app *BankApp) Run(ctx context.Context) error {
+	app.println("=== Банковское приложение ===")
+
+	if app.startupCheck {
+		app.checkConsistency(ctx)
+	}
+	if app.emailSender != nil {
+		app.sendMonthlyStatements(ctx)
+	}
+	app.expirePendingItems(ctx)
+
+	// Ввод читается блокирующе, поэтому по сигналу завершения ресурсы
+	// освобождаются и процесс завершается отсюда, не дожидаясь главного цикла
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			app.println("\nЗавершение работы...")
+			app.shutdown(context.WithoutCancel(ctx))
+			os.Exit(exitInterrupted)
+		case <-done:
+		}
+	}()
+
+	for !app.stopped && !app.scanner.closed {
+		// Каждое действие пользователя получает свой correlation_id для логов
+		actionCtx := services.WithCorrelationID(ctx, app.ids.NewID("REQ"))
+		if app.currentUser != nil {
+			actionCtx = services.WithActor(actionCtx, app.currentUser.Username)
+		}
+
+		switch {
+		case app.currentUser == nil:
+			app.showLoginMenu(actionCtx)
+		case app.currentUser.IsAdmin():
+			app.showAdminMenu(actionCtx)
+		case app.currentAccount == nil:
+			app.showMainMenu(actionCtx)
+		default:
+			app.showAccountMenu(actionCtx)
+		}
+	}
+
+	app.shutdown(ctx)
+
+	if err := app.scanner.err; err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrInputFailed, err)
+	}
+	if app.scanner.closed {
+		app.println("\nВвод завершен")
+	}
+
+	return nil
+}
+
+// stop завершает главный цикл приложения после текущего действия
+func (app *BankApp) stop() {
+	app.println("До свидания!")
+	app.stopped = true
+}
+
+// shutdown записывает отложенные данные и закрывает хранилище
+func (app *BankApp) shutdown(ctx context.Context) {
+	app.shutdownOnce.Do(func() {
+		closable, ok := app.storage.(interfaces.ClosableStorage)
+		if !ok {
+			return
+		}
+
+		if err := closable.Close(ctx); err != nil {
+			app.printf("Ошибка при закрытии хранилища: %v\n", err)
+		}
+	})
+}
+
+// auditAction записывает в журнал аудита действие пользователя уровня приложения
+func (app *BankApp) auditAction(ctx context.Context, action, accountID string, err error) {
+	entry := models.AuditEntry{
+		Action:    action,
+		AccountID: accountID,
+		Success:   err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if recordErr := app.audit.Record(ctx, entry); recordErr != nil {
+		app.logger.ErrorContext(ctx, "ошибка записи аудита", "error", recordErr)
+	}
+}
+
+// checkConsistency проверяет счета при запуске и выводит найденные расхождения
+func (app *BankApp) checkConsistency(ctx context.Context) {
+	issues, err := services.CheckConsistency(ctx, app.storage, app.ledger, app.clock.Now(), app.startupRepair)
+	if err != nil {
+		app.printf("Ошибка при проверке согласованности: %v\n", err)
+		return
+	}
+
+	for _, issue := range issues {
+		if issue.Quarantined {
+			app.printf("Счет %s помещен в карантин: %s\n", issue.AccountID, issue.Problem)
+		} else {
+			app.printf("Счет %s несогласован: %s\n", issue.AccountID, issue.Problem)
+		}
+	}
+
+	if len(issues) > 0 {
+		app.printf("Проверка согласованности: найдено проблем: %d\n", len(issues))
+	}
+}
+
+// showMainMenu показывает главное меню
+func (app *BankApp) showMainMenu(ctx context.Context) {
+	app.printHeader("Главное меню")
+	app.println("1. Создать счет")
+	app.println("2. Выбрать счет")
+	app.println("3. Показать мои счета")
+	app.println("4. Настройки")
+	app.println("5. Проверить квитанцию")
+	app.println("6. Выйти из профиля")
+	app.println("7. Выйти")
+	app.print("Выберите опцию: ")
+
+	app.scanner.Scan()
+	choice := app.scanner.Text()
+
+	switch choice {
+	case "1":
+		app.createAccount(ctx)
+	case "2":
+		app.selectAccount(ctx)
+	case "3":
+		app.showMyAccounts(ctx)
+	case "4":
+		app.editPreferences(ctx)
+	case "5":
+		app.verifyReceipt()
+	case "6":
+		app.logout()
+	case "7":
+		app.stop()
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+	}
+}
+
+// showAccountMenu показывает меню счета
+func (app *BankApp) showAccountMenu(ctx context.Context) {
+	app.printHeader("Меню счета")
+	app.println("1. Пополнить счет")
+	app.println("2. Снять средства")
+	app.println("3. Перевести другому счету")
+	app.println("4. Просмотреть баланс")
+	app.println("5. Получить выписку")
+	app.println("6. Экспортировать выписку в файл")
+	app.println("7. Сменить PIN-код")
+	app.println("8. Прикрепить вложение к транзакции")
+	app.println("9. Остаток дневного лимита")
+	app.println("10. Входящие платежи")
+	app.println("11. Мини-выписка (последние операции)")
+	app.println("12. Псевдонимы и номер телефона")
+	app.println("13. Отправить выписку")
+	app.println("14. Ключ шифрования выписок")
+	app.println("15. Выписка за период в HTML")
+	app.println("16. Категории, теги и заметки")
+	app.println("17. Детские счета")
+	app.println("18. Статистика")
+	app.println("19. История баланса")
+	app.println("20. Переводы с подтверждением")
+	app.println("21. Доверенности")
+	app.println("22. Кредит: остаток и график платежей")
+	app.println("23. Квитанция по операции")
+	app.println("24. Выписки по электронной почте")
+	app.println("25. Отменить последнюю операцию")
+	app.println("26. Вернуться в главное меню")
+	app.print("Выберите опцию: ")
+
+	app.scanner.Scan()
+	choice := app.scanner.Text()
+
+	switch choice {
+	case "1":
+		app.deposit(ctx)
+	case "2":
+		app.withdraw(ctx)
+	case "3":
+		app.transfer(ctx)
+	case "4":
+		app.showBalance(ctx)
+	case "5":
+		app.showStatement(ctx)
+	case "6":
+		app.exportStatement(ctx)
+	case "7":
+		app.changePIN(ctx)
+	case "8":
+		app.attachToTransaction(ctx)
+	case "9":
+		app.showDailyAllowance(ctx)
+	case "10":
+		app.showCreditInbox(ctx)
+	case "11":
+		fmt.Print(app.currentAccount.GetMiniStatement(ctx, miniStatementSize))
+	case "12":
+		app.manageAliases(ctx)
+	case "13":
+		app.deliverStatement(ctx)
+	case "14":
+		app.editStatementKey(ctx)
+	case "15":
+		app.renderStatement(ctx)
+	case "16":
+		app.manageTransactionDetails(ctx)
+	case "17":
+		app.manageChildren(ctx)
+	case "18":
+		app.showStatistics(ctx)
+	case "19":
+		app.showBalanceHistory(ctx)
+	case "20":
+		app.managePendingTransfers(ctx)
+	case "21":
+		app.manageAccessGrants(ctx)
+	case "22":
+		app.showLoan(ctx)
+	case "23":
+		app.showReceipt(ctx)
+	case "24":
+		app.manageStatementEmail(ctx)
+	case "25":
+		app.undoLastOperation(ctx)
+	case "26":
+		app.currentAccount = nil
+		app.println("Возврат в главное меню...")
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+	}
+}
+
+// createAccount создает новый счет
+func (app *BankApp) createAccount(ctx context.Context) {
+	app.print("Введите имя владельца счета: ")
+	app.scanner.Scan()
+	ownerName := strings.TrimSpace(app.scanner.Text())
+
+	if ownerName == "" {
+		app.println("Имя владельца не может быть пустым")
+		return
+	}
+
+	app.print("Придумайте PIN-код (4-6 цифр): ")
+	app.scanner.Scan()
+	pin := strings.TrimSpace(app.scanner.Text())
+
+	account := models.NewAccount(ownerName, app.ids, app.clock)
+	account.OwnerID = app.currentUser.ID
+	account.DailyAmountLimit = app.limits.DailyAmount
+	account.DailyCountLimit = app.limits.DailyCount
+	account.OverdraftLimit = app.limits.Overdraft
+	if err := services.SetPIN(account, pin); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	// Сохраняем счет
+	if err := app.storage.SaveAccount(ctx, account); err != nil {
+		app.printf("Ошибка при создании счета: %v\n", err)
+		return
+	}
+
+	app.events.Publish(ctx, models.Notification{
+		ID:        app.ids.NewID("EV"),
+		Type:      models.AccountCreatedNotification,
+		AccountID: account.ID,
+	})
+
+	app.printf("Счет успешно создан!\n")
+	app.printf("ID счета: %s\n", account.ID)
+	app.printf("Владелец: %s\n", account.OwnerName)
+}
+
+// selectAccount выбирает счет для работы
+func (app *BankApp) selectAccount(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+	account, err := app.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		app.auditAction(ctx, "select_account", accountID, errors.ErrAccountNotFound)
+		app.printf("Ошибка: %v\n", errors.ErrAccountNotFound)
+		return
+	}
+
+	// Счет в архиве открывается только после возврата из архива администратором
+	if account.Archived() {
+		app.auditAction(ctx, "select_account", accountID, errors.ErrAccountArchived)
+		app.printf("Ошибка: %v\n", errors.ErrAccountArchived)
+		return
+	}
+
+	if account.OwnerID != app.currentUser.ID {
+		app.selectDelegatedAccount(ctx, account)
+		return
+	}
+
+	app.print("Введите PIN-код: ")
+	app.scanner.Scan()
+	pin := strings.TrimSpace(app.scanner.Text())
+
+	err = services.Authenticate(ctx, app.storage, app.clock, account, pin)
+	app.auditAction(ctx, "select_account", accountID, err)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.currentAccount = app.newAccountService(account)
+	app.rememberAccount(ctx, accountID)
+	app.printf("Счет %s выбран для работы\n", accountID)
+}
+
+// rememberAccount запоминает выбранный счет, чтобы предложить его при следующем входе
+func (app *BankApp) rememberAccount(ctx context.Context, accountID string) {
+	if err := app.auth.SaveLastAccount(ctx, app.currentUser.ID, accountID); err != nil {
+		app.logger.ErrorContext(ctx, "ошибка сохранения сеанса", "error", err)
+		return
+	}
+	app.currentUser.LastAccountID = accountID
+}
+
+// selectDelegatedAccount открывает чужой счет по действующей доверенности
+// текущего пользователя; PIN-код владельца при этом не запрашивается
+func (app *BankApp) selectDelegatedAccount(ctx context.Context, account *models.Account) {
+	grant, ok := services.FindAccessGrant(account, app.currentUser.ID, app.clock.Now())
+	if !ok {
+		app.auditAction(ctx, "select_account", account.ID, errors.ErrAccessDenied)
+		app.printf("Ошибка: %v\n", errors.ErrAccessDenied)
+		return
+	}
+
+	app.auditAction(services.WithGrant(ctx, grant.ID), "select_account", account.ID, nil)
+	app.currentAccount = services.NewDelegatedAccountService(app.newAccountService(account), app.storage, app.clock, grant.ID)
+	app.printf("Счет %s выбран для работы по доверенности (%s до %s)\n", account.ID, grant.Scope, app.formatTime(grant.ExpiresAt))
+}
+
+// showMyAccounts показывает счета текущего пользователя
+func (app *BankApp) showMyAccounts(ctx context.Context) {
+	accounts, _, err := app.storage.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		app.printf("Ошибка при получении счетов: %v\n", err)
+		return
+	}
+
+	found := false
+	for _, account := range accounts {
+		grant, delegated := services.FindAccessGrant(account, app.currentUser.ID, app.clock.Now())
+		if (account.OwnerID != app.currentUser.ID && !delegated) || account.Archived() {
+			continue
+		}
+
+		if !found {
+			app.printHeader("Мои счета")
+			found = true
+		}
+
+		app.printf("ID: %s | Владелец: %s | Баланс: %.2f\n",
+			account.ID, account.OwnerName, account.Balance)
+		if delegated {
+			app.printf("    по доверенности %s до %s\n", grant.Scope, app.formatTime(grant.ExpiresAt))
+		}
+	}
+
+	if !found {
+		app.println("Счета не найдены")
+	}
+}
+
+// showAllAccounts показывает все счета, кроме перенесенных в архив
+func (app *BankApp) showAllAccounts(ctx context.Context) {
+	for offset := 0; ; offset += pageSize {
+		accounts, total, err := app.storage.ListAccountsByScope(ctx, models.ActiveAccounts, offset, pageSize)
+		if err != nil {
+			app.printf("Ошибка при получении счетов: %v\n", err)
+			return
+		}
+
+		if total == 0 {
+			app.println("Счета не найдены")
+			return
+		}
+		if len(accounts) == 0 {
+			return
+		}
+
+		app.printf("\n--- Все счета (%d-%d из %d) ---\n", offset+1, offset+len(accounts), total)
+		for _, account := range accounts {
+			status := app.tr.T("активен")
+			switch {
+			case account.Quarantined:
+				status = app.tr.Sprintf("карантин: %s", account.QuarantineReason)
+			case account.Status == models.FrozenStatus:
+				status = app.tr.T("заморожен")
+			case account.Status == models.ClosedStatus:
+				status = app.tr.T("закрыт")
+			}
+
+			app.printf("ID: %s | Владелец: %s | Баланс: %.2f | Статус: %s\n",
+				account.ID, account.OwnerName, account.Balance, status)
+			if account.RelationshipManager != "" {
+				app.printf("    Менеджер: %s\n", account.RelationshipManager)
+			}
+			if account.Notes != "" {
+				app.printf("    Заметки: %s\n", account.Notes)
+			}
+		}
+
+		if offset+len(accounts) >= total {
+			return
+		}
+
+		app.print("Enter - следующая страница, q - выход: ")
+		app.scanner.Scan()
+		if strings.TrimSpace(app.scanner.Text()) == "q" {
+			return
+		}
+	}
+}
+
+// searchTransactions ищет транзакции по всем счетам с постраничным выводом
+func (app *BankApp) searchTransactions(ctx context.Context) {
+	var filter models.TransactionFilter
+	var err error
+
+	if filter.MinAmount, err = app.readOptionalAmount("Минимальная сумма (Enter - без ограничения): "); err != nil {
+		return
+	}
+	if filter.MaxAmount, err = app.readOptionalAmount("Максимальная сумма (Enter - без ограничения): "); err != nil {
+		return
+	}
+
+	app.print("ID счета контрагента (Enter - любой): ")
+	app.scanner.Scan()
+	filter.Counterparty = strings.TrimSpace(app.scanner.Text())
+
+	if filter.From, err = app.readOptionalDate("Дата с (ГГГГ-ММ-ДД, Enter - без ограничения): "); err != nil {
+		return
+	}
+	if filter.To, err = app.readOptionalDate("Дата по (ГГГГ-ММ-ДД, Enter - без ограничения): "); err != nil {
+		return
+	}
+	if !filter.To.IsZero() {
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	app.print("Текст в описании (Enter - любой): ")
+	app.scanner.Scan()
+	filter.Text = strings.TrimSpace(app.scanner.Text())
+
+	filter.Limit = pageSize
+	for {
+		results, total, err := app.search.SearchTransactions(ctx, filter)
+		if err != nil {
+			app.printf("Ошибка при поиске: %v\n", err)
+			return
+		}
+
+		if total == 0 {
+			app.println("Транзакции не найдены")
+			return
+		}
+
+		app.printf("\n--- Найдено транзакций: %d (показаны %d-%d) ---\n",
+			total, filter.Offset+1, filter.Offset+len(results))
+		for _, result := range results {
+			tx := result.Transaction
+			app.printf("%s | %s | %s | %s | %.2f | %s\n",
+				app.formatTime(tx.Timestamp),
+				result.AccountID,
+				result.OwnerName,
+				tx.Type,
+				tx.Amount,
+				tx.Message)
+		}
+
+		if filter.Offset+len(results) >= total {
+			return
+		}
+
+		app.print("Enter - следующая страница, q - выход: ")
+		app.scanner.Scan()
+		if strings.TrimSpace(app.scanner.Text()) == "q" {
+			return
+		}
+
+		filter.Offset += pageSize
+	}
+}
+
+// deposit пополняет счет
+func (app *BankApp) deposit(ctx context.Context) {
+	amount, err := app.readAmount("Введите сумму для пополнения: ")
+	if err != nil {
+		return
+	}
+
+	app.print("Источник (1 - наличные, 2 - чек, 3 - внешний перевод, Enter - наличные): ")
+	app.scanner.Scan()
+
+	var source models.DepositSource
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "", "1":
+		source = models.CashSource
+	case "2":
+		source = models.ChequeSource
+	case "3":
+		source = models.TransferInSource
+	default:
+		app.printf("Ошибка: %v\n", errors.ErrInvalidDepositSource)
+		return
+	}
+
+	ctx = app.withTransactionDetails(ctx)
+
+	if !app.confirmPreview(app.currentAccount.PreviewDeposit(ctx, amount, source)) {
+		return
+	}
+
+	var result models.OperationResult
+	if source == models.CashSource {
+		result, err = app.depositCash(ctx, amount)
+	} else {
+		result, err = app.currentAccount.Deposit(ctx, amount, source)
+	}
+	if err != nil {
+		app.printf("Ошибка при пополнении: %v\n", err)
+		return
+	}
+
+	app.printf("Счет успешно пополнен на %.2f\n", amount-result.HeldAmount)
+	app.printReceipt(result)
+}
+
+// withdraw снимает средства
+func (app *BankApp) withdraw(ctx context.Context) {
+	amount, err := app.readAmount("Введите сумму для снятия: ")
+	if err != nil {
+		return
+	}
+
+	ctx = app.withTransactionDetails(ctx)
+
+	if !app.confirmPreview(app.currentAccount.PreviewWithdraw(ctx, amount)) {
+		return
+	}
+
+	result, err := app.currentAccount.Withdraw(ctx, amount)
+	if err != nil {
+		app.printf("Ошибка при снятии: %v\n", err)
+		return
+	}
+
+	app.printf("Со счета успешно снято %.2f\n", amount)
+	app.printReceipt(result)
+}
+
+// transfer переводит средства другому счету
+func (app *BankApp) transfer(ctx context.Context) {
+	amount, err := app.readAmount("Введите сумму для перевода: ")
+	if err != nil {
+		return
+	}
+
+	app.print("Введите ID, псевдоним или телефон получателя: ")
+	app.scanner.Scan()
+	toAccountID := strings.TrimSpace(app.scanner.Text())
+
+	// Загружаем целевой счет
+	toAccount, err := app.aliases.Resolve(ctx, toAccountID)
+	if err != nil {
+		app.auditAction(ctx, "lookup_account", toAccountID, err)
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	ctx = app.withTransactionDetails(ctx)
+
+	if !app.confirmPreview(app.currentAccount.PreviewTransfer(ctx, toAccount, amount)) {
+		return
+	}
+
+	result, err := app.currentAccount.Transfer(ctx, toAccount, amount)
+	if err != nil {
+		app.printf("Ошибка при переводе: %v\n", err)
+		return
+	}
+
+	app.printf("Успешно переведено %.2f на счет %s\n", amount, toAccount.ID)
+	app.printReceipt(result)
+}
+
+// confirmPreview показывает расчет операции и спрашивает подтверждение.
+// Если операция заведомо не пройдет, выводит причину и возвращает false.
+func (app *BankApp) confirmPreview(preview models.OperationPreview, err error) bool {
+	if err != nil {
+		app.printf("Операция невозможна: %v\n", err)
+		return false
+	}
+
+	if preview.CounterpartyID != "" {
+		app.printf("Получатель: %s\n", preview.CounterpartyID)
+	}
+	app.printf("Сумма: %.2f %s\n", preview.Amount, app.currency)
+	if preview.Fee > 0 {
+		app.printf("Комиссия: %.2f\n", preview.Fee)
+	}
+	app.printf("Баланс: %.2f -> %.2f %s\n", preview.BalanceBefore, preview.BalanceAfter, app.currency)
+	if preview.AvailableAfter != preview.BalanceAfter {
+		app.printf("Доступно после операции: %.2f %s\n", preview.AvailableAfter, app.currency)
+	}
+
+	app.print("Выполнить операцию? (y/n): ")
+	app.scanner.Scan()
+	if strings.ToLower(strings.TrimSpace(app.scanner.Text())) != "y" {
+		app.println("Операция отменена")
+		return false
+	}
+
+	return true
+}
+
+// undoLastOperation отменяет только что проведенную операцию после подтверждения
+func (app *BankApp) undoLastOperation(ctx context.Context) {
+	app.print("Отменить последнюю операцию? (y/n): ")
+	app.scanner.Scan()
+	if strings.ToLower(strings.TrimSpace(app.scanner.Text())) != "y" {
+		return
+	}
+
+	undone, err := app.currentAccount.UndoLast(ctx)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Операция %s (%s на %.2f) отменена\n", undone.ID, undone.Type, undone.Amount)
+}
+
+// printReceipt выводит квитанцию по операции
+func (app *BankApp) printReceipt(result models.OperationResult) {
+	if result.TransactionID != "" {
+		app.printf("Транзакция: %s от %s\n", result.TransactionID, app.formatTime(result.ValueDate))
+	}
+	if result.Fee > 0 {
+		app.printf("Комиссия: %.2f\n", result.Fee)
+	}
+	if result.ReceiptCode != "" {
+		app.printf("Код проверки квитанции: %s\n", result.ReceiptCode)
+	}
+	if result.UnderReview {
+		app.println("Операция передана на проверку")
+	}
+	if result.HeldAmount > 0 {
+		app.printf("Подозрительные купюры на %.2f отложены на проверку (%s)\n", result.HeldAmount, result.HoldID)
+	}
+	app.printf("Баланс после операции: %.2f %s\n", result.Balance, app.currency)
+}
+
+// showBalance показывает баланс
+func (app *BankApp) showBalance(ctx context.Context) {
+	balance := app.currentAccount.GetBalance(ctx)
+	app.printf("Текущий баланс: %.2f %s\n", balance, app.currency)
+
+	if available := app.currentAccount.GetAvailableBalance(ctx); available != balance {
+		app.printf("Доступно с учетом удержаний: %.2f %s\n", available, app.currency)
+	}
+}
+
+// showDailyAllowance показывает остаток дневных лимитов на списания
+func (app *BankApp) showDailyAllowance(ctx context.Context) {
+	allowance := app.currentAccount.GetDailyAllowance(ctx)
+
+	if allowance.AmountLimit > 0 {
+		app.printf("Сумма: использовано %.2f из %.2f, осталось %.2f\n",
+			allowance.AmountUsed, allowance.AmountLimit, allowance.AmountRemaining)
+	} else {
+		app.printf("Сумма: использовано %.2f, без ограничения\n", allowance.AmountUsed)
+	}
+
+	if allowance.CountLimit > 0 {
+		app.printf("Операции: использовано %d из %d, осталось %d\n",
+			allowance.CountUsed, allowance.CountLimit, allowance.CountRemaining)
+	} else {
+		app.printf("Операции: использовано %d, без ограничения\n", allowance.CountUsed)
+	}
+}
+
+// showStatement показывает выписку
+func (app *BankApp) showStatement(ctx context.Context) {
+	statement := app.currentAccount.GetStatement(ctx)
+	lines := strings.Split(strings.TrimRight(statement, "\n"), "\n")
+
+	if app.statementPageLines <= 0 || len(lines) <= app.statementPageLines {
+		fmt.Println(statement)
+		return
+	}
+
+	app.printf("Выписка содержит %d строк.\n", len(lines))
+	app.println("1. Просмотреть постранично")
+	app.println("2. Сохранить в файл")
+	app.println("3. Вывести целиком")
+	app.print("Выберите опцию: ")
+
+	app.scanner.Scan()
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "1":
+		app.pageLines(lines)
+	case "2":
+		app.exportStatement(ctx)
+	case "3":
+		fmt.Println(statement)
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+	}
+}
+
+// pageLines выводит строки порциями по statementPageLines
+func (app *BankApp) pageLines(lines []string) {
+	for start := 0; start < len(lines); start += app.statementPageLines {
+		end := start + app.statementPageLines
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		for _, line := range lines[start:end] {
+			fmt.Println(line)
+		}
+
+		if end == len(lines) {
+			return
+		}
+
+		app.printf("-- строки %d-%d из %d. Enter - далее, q - выход: ", start+1, end, len(lines))
+		app.scanner.Scan()
+		if strings.TrimSpace(app.scanner.Text()) == "q" {
+			return
+		}
+	}
+}
+
+// exportStatement выгружает выписку в файл в формате CSV или JSON
+func (app *BankApp) exportStatement(ctx context.Context) {
+	app.printf("Введите формат (csv/json/jsonl, Enter - %s): ", app.prefs.StatementFormat)
+	app.scanner.Scan()
+	format := models.ExportFormat(strings.ToLower(strings.TrimSpace(app.scanner.Text())))
+	if format == "" {
+		format = app.prefs.StatementFormat
+	}
+
+	if format != models.CSVFormat && format != models.JSONFormat && format != models.JSONLinesFormat {
+		app.printf("Ошибка: %v\n", errors.ErrUnsupportedFormat)
+		return
+	}
+
+	app.print("Введите путь к файлу: ")
+	app.scanner.Scan()
+	path := strings.TrimSpace(app.scanner.Text())
+
+	if path == "" {
+		app.println("Путь к файлу не может быть пустым")
+		return
+	}
+
+	// JSON - один документ, его нельзя выгружать частями
+	if format != models.JSONFormat {
+		app.exportResumable(ctx, format, path)
+		return
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		app.printf("Ошибка при создании файла: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	if err := app.currentAccount.ExportStatement(ctx, format, file); err != nil {
+		app.printf("Ошибка при экспорте: %v\n", err)
+		return
+	}
+
+	app.printf("Выписка сохранена в %s\n", path)
+}
+
+// exportResumable выгружает транзакции в файл частями. После каждой части
+// точка продолжения сохраняется рядом с файлом, и прерванную выгрузку можно
+// продолжить, дописав файл, вместо того чтобы начинать заново.
+func (app *BankApp) exportResumable(ctx context.Context, format models.ExportFormat, path string) {
+	checkpointPath := path + ".checkpoint"
+	checkpoint := ""
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if data, err := os.ReadFile(checkpointPath); err == nil {
+		app.print("Найдена прерванная выгрузка в этот файл. Продолжить? (y/n): ")
+		app.scanner.Scan()
+		if strings.ToLower(strings.TrimSpace(app.scanner.Text())) == "y" {
+			checkpoint = strings.TrimSpace(string(data))
+			flags = os.O_WRONLY | os.O_APPEND
+		}
+	}
+
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		app.printf("Ошибка при создании файла: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	rows := 0
+	for {
+		chunk, err := app.currentAccount.ExportChunk(ctx, format, checkpoint, exportChunkSize, file)
+		if err != nil {
+			app.printf("Ошибка при экспорте: %v\n", err)
+			return
+		}
+		rows += chunk.Rows
+		checkpoint = chunk.Checkpoint
+		if chunk.Done {
+			break
+		}
+
+		// Точка записывается после части, поэтому сбой между ними может
+		// повторить часть при продолжении, но не пропустить ее
+		if err := os.WriteFile(checkpointPath, []byte(checkpoint), 0o600); err != nil {
+			app.printf("Ошибка при экспорте: %v\n", err)
+			return
+		}
+	}
+
+	os.Remove(checkpointPath)
+	app.printf("Выписка сохранена в %s (транзакций: %d)\n", path, rows)
+}
+
+// attachToTransaction прикрепляет файл или ссылку к одной из последних транзакций
+func (app *BankApp) attachToTransaction(ctx context.Context) {
+	_, total, err := app.currentAccount.ListTransactions(ctx, 0, 0)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	if total == 0 {
+		app.println("История транзакций пуста")
+		return
+	}
+
+	recent, _, err := app.currentAccount.ListTransactions(ctx, total-pageSize, pageSize)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printHeader("Последние транзакции")
+	for _, tx := range recent {
+		app.printf("%s | %s | %s | %.2f | %s\n",
+			tx.ID, app.formatTime(tx.Timestamp), tx.Type, tx.Amount, tx.Message)
+	}
+
+	app.print("Введите ID транзакции: ")
+	app.scanner.Scan()
+	transactionID := strings.TrimSpace(app.scanner.Text())
+
+	app.print("Введите путь к файлу или URL документа: ")
+	app.scanner.Scan()
+	source := strings.TrimSpace(app.scanner.Text())
+
+	var attachment models.Attachment
+	if info, statErr := os.Stat(source); statErr == nil && !info.IsDir() {
+		data, readErr := os.ReadFile(source)
+		if readErr != nil {
+			app.printf("Ошибка при чтении файла: %v\n", readErr)
+			return
+		}
+		attachment, err = app.currentAccount.AttachFile(ctx, transactionID, source, data)
+	} else {
+		attachment, err = app.currentAccount.AttachReference(ctx, transactionID, source)
+	}
+
+	if err != nil {
+		app.printf("Ошибка при добавлении вложения: %v\n", err)
+		return
+	}
+
+	app.printf("Вложение %s добавлено к транзакции %s\n", attachment.ID, transactionID)
+}
+
+// showCreditInbox показывает входящие внешние платежи и позволяет принять или
+// вернуть их, а также переключить автоприем
+func (app *BankApp) showCreditInbox(ctx context.Context) {
+	credits := app.currentAccount.ListPendingCredits(ctx)
+	if len(credits) == 0 {
+		app.println("Входящих платежей нет")
+	} else {
+		app.printHeader("Входящие платежи")
+		for _, credit := range credits {
+			app.printf("%s | %s | %.2f | от: %s | %s\n",
+				credit.ID, app.formatTime(credit.ReceivedAt), credit.Amount, credit.Sender, credit.Reference)
+			if !credit.ExpiresAt.IsZero() {
+				app.printf("    принять до %s\n", app.formatTime(credit.ExpiresAt))
+			}
+		}
+	}
+
+	app.println("1. Принять платеж")
+	app.println("2. Вернуть платеж отправителю")
+	app.println("3. Включить автоприем")
+	app.println("4. Отключить автоприем")
+	app.println("5. Назад")
+	app.print("Выберите опцию: ")
+
+	app.scanner.Scan()
+	choice := strings.TrimSpace(app.scanner.Text())
+
+	var err error
+	switch choice {
+	case "1", "2":
+		app.print("Введите ID платежа: ")
+		app.scanner.Scan()
+		creditID := strings.TrimSpace(app.scanner.Text())
+		if choice == "1" {
+			err = app.currentAccount.AcceptCredit(ctx, creditID)
+		} else {
+			err = app.currentAccount.RejectCredit(ctx, creditID)
+		}
+	case "3", "4":
+		err = app.currentAccount.SetAutoAcceptCredits(ctx, choice == "3")
+	case "5":
+		return
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+		return
+	}
+
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.println("Готово")
+}
+
+// changePIN меняет PIN-код текущего счета
+func (app *BankApp) changePIN(ctx context.Context) {
+	app.print("Введите текущий PIN-код: ")
+	app.scanner.Scan()
+	oldPIN := strings.TrimSpace(app.scanner.Text())
+
+	app.print("Введите новый PIN-код (4-6 цифр): ")
+	app.scanner.Scan()
+	newPIN := strings.TrimSpace(app.scanner.Text())
+
+	if err := app.currentAccount.ChangePIN(ctx, oldPIN, newPIN); err != nil {
+		app.printf("Ошибка при смене PIN-кода: %v\n", err)
+		if stderrors.Is(err, errors.ErrAccountLocked) {
+			app.currentAccount = nil
+		}
+		return
+	}
+
+	app.println("PIN-код успешно изменен")
+}
+
+// readAmount читает сумму из ввода
+func (app *BankApp) readAmount(prompt string) (float64, error) {
+	app.print(prompt)
+	app.scanner.Scan()
+	input := strings.TrimSpace(app.scanner.Text())
+
+	return app.parseAmount(input)
+}
+
+// readOptionalAmount читает необязательную сумму; пустой ввод означает 0
+func (app *BankApp) readOptionalAmount(prompt string) (float64, error) {
+	app.print(prompt)
+	app.scanner.Scan()
+	input := strings.TrimSpace(app.scanner.Text())
+
+	if input == "" {
+		return 0, nil
+	}
+
+	return app.parseAmount(input)
+}
+
+// parseAmount разбирает сумму. Вместо числа можно ввести арифметическое
+// выражение, например 1500*3; его результат округляется до копеек и
+// показывается для подтверждения перед выполнением операции.
+func (app *BankApp) parseAmount(input string) (float64, error) {
+	if amount, err := strconv.ParseFloat(input, 64); err == nil {
+		if amount <= 0 {
+			app.printf("Ошибка: %v\n", errors.ErrInvalidAmount)
+			return 0, errors.ErrInvalidAmount
+		}
+		return amount, nil
+	}
+
+	compiled, err := expr.Compile(input)
+	if err == nil && len(compiled.Variables()) > 0 {
+		err = fmt.Errorf("%w: в сумме допустимы только числа", errors.ErrInvalidExpression)
+	}
+	var amount float64
+	if err == nil {
+		amount, err = compiled.Number(nil)
+	}
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return 0, errors.ErrInvalidAmount
+	}
+
+	amount = math.Round(amount*100) / 100
+	if amount <= 0 {
+		app.printf("Ошибка: %v\n", errors.ErrInvalidAmount)
+		return 0, errors.ErrInvalidAmount
+	}
+
+	app.printf("%s = %.2f. Подтвердить? (y/n): ", input, amount)
+	app.scanner.Scan()
+	if strings.ToLower(strings.TrimSpace(app.scanner.Text())) != "y" {
+		app.println("Сумма не подтверждена")
+		return 0, errors.ErrInvalidAmount
+	}
+
+	return amount, nil
+}
+
+// readMoment читает момент времени. Дата без времени означает конец этого дня,
+// время с точностью до минуты - конец этой минуты.
+func (app *BankApp) readMoment(prompt string) (time.Time, error) {
+	app.print(prompt)
+	app.scanner.Scan()
+	input := strings.TrimSpace(app.scanner.Text())
+
+	if moment, err := time.ParseInLocation("2006-01-02 15:04", input, time.Local); err == nil {
+		return moment.Add(time.Minute - time.Nanosecond), nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", input, time.Local)
+	if err != nil {
+		app.println("Ошибка: некорректная дата")
+		return time.Time{}, err
+	}
+
+	return date.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+// readOptionalDate читает необязательную дату в формате ГГГГ-ММ-ДД
+func (app *BankApp) readOptionalDate(prompt string) (time.Time, error) {
+	app.print(prompt)
+	app.scanner.Scan()
+	input := strings.TrimSpace(app.scanner.Text())
+
+	if input == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", input, time.Local)
+	if err != nil {
+		app.println("Ошибка: некорректная дата")
+		return time.Time{}, err
+	}
+
+	return date, nil
+}
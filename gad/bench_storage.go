@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"bankapp/interfaces"
+	"bankapp/models"
+	"bankapp/services"
+	"bankapp/storage"
+)
+
+const (
+	// benchWriteBatch и benchWriteDelay параметры отложенной записи для --backend write-behind
+	benchWriteBatch = 64
+	benchWriteDelay = 50 * time.Millisecond
+)
+
+// runBenchStorage выполняет команду bench-storage: нагрузочный прогон
+// хранилища с выводом перцентилей задержек и максимального устойчивого TPS
+func runBenchStorage(args []string) int {
+	flags := flag.NewFlagSet("bench-storage", flag.ContinueOnError)
+	backend := flags.String("backend", "memory", "обертка хранилища: memory (без обертки), write-behind или cached")
+	dsn := flags.String("dsn", "", "строка подключения к хранилищу под оберткой (по умолчанию в памяти)")
+	accounts := flags.Int("accounts", 1000, "количество счетов в наборе данных")
+	duration := flags.Duration("duration", 5*time.Second, "длительность одной ступени нагрузки")
+	workers := flags.Int("workers", 16, "предельное число параллельных воркеров")
+	p99 := flags.Duration("p99-target", 10*time.Millisecond, "целевой p99 для устойчивой нагрузки")
+	cacheSize := flags.Int("cache-size", 256, "размер кэша счетов для --backend cached")
+	cacheTTL := flags.Duration("cache-ttl", time.Minute, "время жизни записи кэша для --backend cached")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *accounts <= 0 || *duration <= 0 || *workers <= 0 || *p99 <= 0 || *cacheSize <= 0 || *cacheTTL <= 0 {
+		fmt.Fprintln(os.Stderr, "Ошибка: параметры прогона должны быть положительными")
+		return 2
+	}
+
+	ctx := context.Background()
+	store, closeStore, err := openBenchStorage(*backend, *dsn, *cacheSize, *cacheTTL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		return 2
+	}
+	defer closeStore(ctx)
+
+	report, err := services.BenchmarkStorage(ctx, store, models.BenchOptions{
+		Accounts:   *accounts,
+		Duration:   *duration,
+		MaxWorkers: *workers,
+		P99Target:  *p99,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка прогона: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Хранилище: %s, счетов: %d, ступень: %s, целевой p99: %s\n", *backend, *accounts, *duration, *p99)
+	fmt.Printf("%8s %10s %10s %12s %12s %12s %8s\n", "воркеры", "операции", "TPS", "p50", "p95", "p99", "ошибки")
+	for _, step := range report.Steps {
+		mark := ""
+		if !step.Sustainable(*p99) {
+			mark = " *"
+		}
+		fmt.Printf("%8d %10d %10.0f %12s %12s %12s %8d%s\n",
+			step.Workers, step.Ops, step.TPS, step.P50, step.P95, step.P99, step.Errors, mark)
+	}
+	fmt.Printf("Максимальный устойчивый TPS: %.0f\n", report.MaxTPS)
+
+	if cached, ok := store.(*storage.CachedStorage); ok {
+		stats := cached.Stats()
+		fmt.Printf("Кэш: попаданий %d, промахов %d (%.1f%%), вытеснено %d\n",
+			stats.Hits, stats.Misses, stats.HitRate()*100, stats.Evictions)
+	}
+
+	return 0
+}
+
+// openBenchStorage открывает хранилище для прогона по dsn (без dsn - в
+// памяти), оборачивает его выбранным backend и возвращает функцию закрытия
+func openBenchStorage(backend, dsn string, cacheSize int, cacheTTL time.Duration) (interfaces.Storage, func(context.Context) error, error) {
+	base := storage.NewMemoryStorage()
+	if dsn != "" {
+		opened, err := storage.Open(dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+		base = opened
+	}
+
+	closeBase := func(ctx context.Context) error {
+		if closable, ok := base.(interfaces.ClosableStorage); ok {
+			return closable.Close(ctx)
+		}
+		return nil
+	}
+
+	switch backend {
+	case "memory":
+		return base, closeBase, nil
+	case "write-behind":
+		writeBehind := storage.NewWriteBehindStorage(base, benchWriteBatch, benchWriteDelay)
+		// Отложенная запись закрывает только себя, обернутое хранилище закрывается следом
+		return writeBehind, func(ctx context.Context) error {
+			return errors.Join(writeBehind.Close(ctx), closeBase(ctx))
+		}, nil
+	case "cached":
+		cached := storage.NewCachedStorage(base, cacheSize, cacheTTL)
+		return cached, cached.Close, nil
+	default:
+		closeBase(context.Background())
+		return nil, nil, fmt.Errorf("неизвестное хранилище %q (доступны memory, write-behind и cached)", backend)
+	}
+}
@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// CachedStorage обертка над хранилищем с LRU-кэшем счетов. Загруженные
+// счета хранятся в кэше не дольше ttl, при превышении size вытесняются
+// давно не использованные (нулевые ttl и size - без ограничения). SaveAccount пишет в хранилище
+// сразу и обновляет кэш только после успешной записи. Если счета меняются
+// в хранилище в обход обертки, их нужно сбросить через Invalidate. Кэш
+// хранит и возвращает копии счетов, как и само хранилище.
+type CachedStorage struct {
+	storage interfaces.Storage
+	size    int
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	stats   models.CacheStats
+}
+
+// cacheEntry счет в кэше и момент, после которого он считается устаревшим
+type cacheEntry struct {
+	account *models.Account
+	expires time.Time
+}
+
+// NewCachedStorage оборачивает хранилище кэшем не более чем на size счетов
+func NewCachedStorage(storage interfaces.Storage, size int, ttl time.Duration) *CachedStorage {
+	return &CachedStorage{
+		storage: storage,
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// SaveAccount сохраняет счет в хранилище и обновляет кэш.
+// При ошибке записи счет удаляется из кэша, чтобы следующее
+// чтение взяло актуальную версию из хранилища.
+func (s *CachedStorage) SaveAccount(ctx context.Context, account *models.Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.storage.SaveAccount(ctx, account); err != nil {
+		s.removeLocked(account.ID)
+		return err
+	}
+
+	s.putLocked(account)
+	return nil
+}
+
+// SaveAccounts атомарно сохраняет счета, если обернутое хранилище это умеет,
+// иначе по одному, и обновляет кэш так же, как SaveAccount
+func (s *CachedStorage) SaveAccounts(ctx context.Context, accounts ...*models.Account) error {
+	batch, ok := s.storage.(interfaces.BatchStorage)
+	if !ok {
+		for _, account := range accounts {
+			if err := s.SaveAccount(ctx, account); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := batch.SaveAccounts(ctx, accounts...); err != nil {
+		for _, account := range accounts {
+			s.removeLocked(account.ID)
+		}
+		return err
+	}
+
+	for _, account := range accounts {
+		s.putLocked(account)
+	}
+	return nil
+}
+
+// Close закрывает обернутое хранилище, если ему это нужно
+func (s *CachedStorage) Close(ctx context.Context) error {
+	closable, ok := s.storage.(interfaces.ClosableStorage)
+	if !ok {
+		return nil
+	}
+
+	return closable.Close(ctx)
+}
+
+// LoadAccount возвращает счет из кэша или загружает его из хранилища
+func (s *CachedStorage) LoadAccount(ctx context.Context, accountID string) (*models.Account, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[accountID]; ok {
+		entry := element.Value.(*cacheEntry)
+		if s.ttl <= 0 || time.Now().Before(entry.expires) {
+			s.lru.MoveToFront(element)
+			s.stats.Hits++
+			return entry.account.Clone(), nil
+		}
+
+		s.removeLocked(accountID)
+	}
+
+	s.stats.Misses++
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.putLocked(account)
+	return account, nil
+}
+
+// GetAllAccounts возвращает все счета из хранилища
+func (s *CachedStorage) GetAllAccounts(ctx context.Context) ([]*models.Account, error) {
+	return s.storage.GetAllAccounts(ctx)
+}
+
+// ListAccounts возвращает страницу счетов из хранилища
+func (s *CachedStorage) ListAccounts(ctx context.Context, offset, limit int) ([]*models.Account, int, error) {
+	return s.storage.ListAccounts(ctx, offset, limit)
+}
+
+// ListAccountsByScope возвращает страницу счетов из выборки scope из хранилища
+func (s *CachedStorage) ListAccountsByScope(ctx context.Context, scope models.AccountScope, offset, limit int) ([]*models.Account, int, error) {
+	return s.storage.ListAccountsByScope(ctx, scope, offset, limit)
+}
+
+// SaveUser сохраняет пользователя
+func (s *CachedStorage) SaveUser(ctx context.Context, user *models.User) error {
+	return s.storage.SaveUser(ctx, user)
+}
+
+// LoadUser загружает пользователя по ID
+func (s *CachedStorage) LoadUser(ctx context.Context, userID string) (*models.User, error) {
+	return s.storage.LoadUser(ctx, userID)
+}
+
+// FindUserByUsername ищет пользователя по имени
+func (s *CachedStorage) FindUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	return s.storage.FindUserByUsername(ctx, username)
+}
+
+// GetAllUsers возвращает всех пользователей
+func (s *CachedStorage) GetAllUsers(ctx context.Context) ([]*models.User, error) {
+	return s.storage.GetAllUsers(ctx)
+}
+
+// Invalidate удаляет счета из кэша после их изменения в обход обертки;
+// без аргументов кэш очищается полностью
+func (s *CachedStorage) Invalidate(accountIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(accountIDs) == 0 {
+		s.entries = make(map[string]*list.Element)
+		s.lru.Init()
+		return
+	}
+
+	for _, id := range accountIDs {
+		s.removeLocked(id)
+	}
+}
+
+// Stats возвращает счетчики попаданий и промахов кэша
+func (s *CachedStorage) Stats() models.CacheStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	stats.Entries = s.lru.Len()
+	return stats
+}
+
+// putLocked помещает счет в начало очереди и вытесняет лишние записи. Вызывается под s.mu.
+func (s *CachedStorage) putLocked(account *models.Account) {
+	entry := &cacheEntry{account: account.Clone(), expires: time.Now().Add(s.ttl)}
+	if element, ok := s.entries[account.ID]; ok {
+		element.Value = entry
+		s.lru.MoveToFront(element)
+		return
+	}
+
+	s.entries[account.ID] = s.lru.PushFront(entry)
+	for s.size > 0 && s.lru.Len() > s.size {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.entries, oldest.Value.(*cacheEntry).account.ID)
+		s.stats.Evictions++
+	}
+}
+
+// removeLocked удаляет счет из кэша. Вызывается под s.mu.
+func (s *CachedStorage) removeLocked(accountID string) {
+	if element, ok := s.entries[accountID]; ok {
+		s.lru.Remove(element)
+		delete(s.entries, accountID)
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"log/slog"
+)
+
+// DepositCash принимает взнос наличными с разбивкой по купюрам. Подлинные
+// купюры зачисляются сразу, подозрительные откладываются в удержание до
+// проверки; зачисление и удержание сохраняются одной записью
+func (s *AccountServiceImpl) DepositCash(ctx context.Context, notes []models.CashNote) (result models.OperationResult, err error) {
+	var clean, held float64
+	var suspect []models.CashNote
+	for _, note := range notes {
+		if note.Value <= 0 || note.Count <= 0 || note.Suspect < 0 || note.Suspect > note.Count {
+			return models.OperationResult{}, errors.ErrInvalidCashNotes
+		}
+
+		clean += note.Value * float64(note.Count-note.Suspect)
+		if note.Suspect > 0 {
+			held += note.Value * float64(note.Suspect)
+			suspect = append(suspect, models.CashNote{Value: note.Value, Count: note.Suspect, Suspect: note.Suspect})
+		}
+	}
+
+	if clean+held <= 0 {
+		return models.OperationResult{}, errors.ErrInvalidCashNotes
+	}
+
+	defer func() {
+		if clean > 0 {
+			s.logOperation(ctx, "deposit", clean, err, slog.String("source", string(models.CashSource)))
+			s.auditOperation(ctx, "deposit", clean, string(models.CashSource), err)
+			if err == nil {
+				s.publish(ctx, models.DepositedNotification, result.TransactionID, clean, "")
+			}
+		}
+		if held > 0 && err == nil {
+			s.auditOperation(ctx, "cash_hold", held, result.HoldID, nil)
+		}
+	}()
+
+	err = s.retryOnConflict(ctx, func() error {
+		result, err = s.depositCash(ctx, notes, clean, held, suspect)
+		return err
+	})
+
+	return result, err
+}
+
+// depositCash зачисляет подлинные купюры и откладывает подозрительные;
+// DepositCash повторяет его при конфликте версий
+func (s *AccountServiceImpl) depositCash(ctx context.Context, notes []models.CashNote, clean, held float64, suspect []models.CashNote) (models.OperationResult, error) {
+	var hold models.CashHold
+	addHold := func(depositID string) {
+		if held <= 0 {
+			return
+		}
+
+		hold = models.CashHold{
+			ID:        s.newID("HOLD"),
+			AccountID: s.account.ID,
+			Amount:    held,
+			Notes:     suspect,
+			Status:    models.PendingHoldStatus,
+			DepositID: depositID,
+			CreatedAt: s.now(),
+		}
+		s.account.CashHolds = append(s.account.CashHolds, hold)
+	}
+
+	var result models.OperationResult
+	if clean > 0 {
+		var err error
+		result, err = s.deposit(ctx, clean, models.CashSource, func(transaction models.Transaction) {
+			if tx, err := s.findTransaction(transaction.ID); err == nil {
+				tx.Denominations = append([]models.CashNote(nil), notes...)
+			}
+			addHold(transaction.ID)
+		})
+		if err != nil {
+			return models.OperationResult{}, err
+		}
+	} else {
+		if err := s.checkVersion(ctx); err != nil {
+			return models.OperationResult{}, err
+		}
+
+		if err := checkOperable(s.account); err != nil {
+			return models.OperationResult{}, err
+		}
+
+		if err := s.checkSwitch(ctx, models.DepositTransaction); err != nil {
+			return models.OperationResult{}, err
+		}
+
+		addHold("")
+		if err := s.saveAccount(ctx); err != nil {
+			return models.OperationResult{}, err
+		}
+		result = models.OperationResult{Balance: s.account.Balance, ValueDate: hold.CreatedAt}
+	}
+
+	if held > 0 {
+		result.HeldAmount = held
+		result.HoldID = hold.ID
+	}
+
+	return result, nil
+}
+
+// ListCashHolds возвращает непроверенные подозрительные купюры по всем счетам
+func ListCashHolds(ctx context.Context, storage interfaces.Storage) ([]models.CashHold, error) {
+	accounts, err := storage.GetAllAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var holds []models.CashHold
+	for _, account := range accounts {
+		for _, hold := range account.CashHolds {
+			if hold.Status == models.PendingHoldStatus {
+				holds = append(holds, hold)
+			}
+		}
+	}
+
+	return holds, nil
+}
+
+// ResolveCashHold закрывает проверку подозрительных купюр: подлинные
+// зачисляются на счет отдельной проводкой, фальшивые изымаются без
+// зачисления. Зачисление и закрытие удержания сохраняются одной записью.
+func ResolveCashHold(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage, clock models.Clock,
+	accountID, holdID string, genuine bool) (resolved models.CashHold, err error) {
+	account, err := storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return models.CashHold{}, err
+	}
+
+	service := &AccountServiceImpl{
+		account: account,
+		storage: storage,
+		ledger:  ledger,
+		clock:   clock,
+	}
+
+	defer func() {
+		service.auditOperation(ctx, "resolve_cash_hold", resolved.Amount, holdID, err)
+	}()
+
+	err = service.retryOnConflict(ctx, func() error {
+		resolved, err = service.resolveCashHold(ctx, holdID, genuine)
+		return err
+	})
+
+	return resolved, err
+}
+
+// resolveCashHold закрывает удержание в текущей копии счета;
+// ResolveCashHold повторяет его при конфликте версий
+func (s *AccountServiceImpl) resolveCashHold(ctx context.Context, holdID string, genuine bool) (models.CashHold, error) {
+	hold, err := s.findCashHold(holdID)
+	if err != nil {
+		return models.CashHold{}, err
+	}
+
+	if genuine {
+		_, err := s.deposit(ctx, hold.Amount, models.CashSource, func(transaction models.Transaction) {
+			if tx, err := s.findTransaction(transaction.ID); err == nil {
+				tx.Denominations = append([]models.CashNote(nil), hold.Notes...)
+				tx.RelatedID = hold.DepositID
+			}
+
+			hold.Status = models.GenuineHoldStatus
+			hold.TransactionID = transaction.ID
+			hold.ResolvedAt = transaction.Timestamp
+		})
+		if err != nil {
+			return models.CashHold{}, err
+		}
+
+		return *hold, nil
+	}
+
+	if err := s.checkVersion(ctx); err != nil {
+		return models.CashHold{}, err
+	}
+
+	hold.Status = models.CounterfeitHoldStatus
+	hold.ResolvedAt = s.now()
+	resolved := *hold
+
+	if err := s.saveAccount(ctx); err != nil {
+		return models.CashHold{}, err
+	}
+
+	return resolved, nil
+}
+
+// findCashHold ищет непроверенное удержание по ID
+func (s *AccountServiceImpl) findCashHold(holdID string) (*models.CashHold, error) {
+	for i := range s.account.CashHolds {
+		hold := &s.account.CashHolds[i]
+		if hold.ID != holdID {
+			continue
+		}
+
+		if hold.Status != models.PendingHoldStatus {
+			return nil, errors.ErrHoldResolved
+		}
+
+		return hold, nil
+	}
+
+	return nil, errors.ErrHoldNotFound
+}
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"bankapp/services"
+	"context"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// depositCash принимает взнос наличными: запрашивает разбивку по купюрам
+// и подозрительные купюры, которые откладываются до проверки
+func (app *BankApp) depositCash(ctx context.Context, amount float64) (models.OperationResult, error) {
+	app.print("Купюры (номинал x количество через запятую, Enter - без разбивки): ")
+	app.scanner.Scan()
+	input := strings.TrimSpace(app.scanner.Text())
+	if input == "" {
+		return app.currentAccount.Deposit(ctx, amount, models.CashSource)
+	}
+
+	notes, err := parseCashNotes(input)
+	if err != nil {
+		return models.OperationResult{}, err
+	}
+
+	var total float64
+	for _, note := range notes {
+		total += note.Value * float64(note.Count)
+	}
+	if math.Round(total*100) != math.Round(amount*100) {
+		return models.OperationResult{}, errors.ErrCashNotesMismatch
+	}
+
+	app.print("Подозрительные купюры (номинал x количество, Enter - нет): ")
+	app.scanner.Scan()
+	if input := strings.TrimSpace(app.scanner.Text()); input != "" {
+		suspect, err := parseCashNotes(input)
+		if err != nil {
+			return models.OperationResult{}, err
+		}
+
+		if err := markSuspect(notes, suspect); err != nil {
+			return models.OperationResult{}, err
+		}
+	}
+
+	return app.currentAccount.DepositCash(ctx, notes)
+}
+
+// parseCashNotes разбирает разбивку вида "5000x2, 1000x3"; купюры
+// одного номинала объединяются
+func parseCashNotes(input string) ([]models.CashNote, error) {
+	var notes []models.CashNote
+	for _, part := range strings.Split(input, ",") {
+		value, count, ok := strings.Cut(strings.ToLower(strings.TrimSpace(part)), "x")
+		if !ok {
+			return nil, errors.ErrInvalidCashNotes
+		}
+
+		noteValue, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || noteValue <= 0 {
+			return nil, errors.ErrInvalidCashNotes
+		}
+
+		noteCount, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil || noteCount <= 0 {
+			return nil, errors.ErrInvalidCashNotes
+		}
+
+		merged := false
+		for i := range notes {
+			if notes[i].Value == noteValue {
+				notes[i].Count += noteCount
+				merged = true
+			}
+		}
+		if !merged {
+			notes = append(notes, models.CashNote{Value: noteValue, Count: noteCount})
+		}
+	}
+
+	return notes, nil
+}
+
+// markSuspect отмечает подозрительные купюры в разбивке взноса
+func markSuspect(notes, suspect []models.CashNote) error {
+	for _, s := range suspect {
+		found := false
+		for i := range notes {
+			if notes[i].Value == s.Value && notes[i].Count >= s.Count {
+				notes[i].Suspect = s.Count
+				found = true
+			}
+		}
+
+		if !found {
+			return errors.ErrInvalidCashNotes
+		}
+	}
+
+	return nil
+}
+
+// verifyCashHolds показывает непроверенные подозрительные купюры и
+// фиксирует результат проверки: подлинные зачисляются, фальшивые изымаются
+func (app *BankApp) verifyCashHolds(ctx context.Context) {
+	holds, err := services.ListCashHolds(ctx, app.storage)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printHeader("Подозрительные купюры")
+	if len(holds) == 0 {
+		app.println("Нет купюр, ожидающих проверки")
+		return
+	}
+
+	for _, hold := range holds {
+		app.printf("%s  счет %s  %.2f  от %s\n", hold.ID, hold.AccountID, hold.Amount, app.formatTime(hold.CreatedAt))
+		for _, note := range hold.Notes {
+			app.printf("    %.2f x %d\n", note.Value, note.Count)
+		}
+	}
+
+	app.print("ID удержания (Enter - назад): ")
+	app.scanner.Scan()
+	holdID := strings.TrimSpace(app.scanner.Text())
+	if holdID == "" {
+		return
+	}
+
+	var accountID string
+	for _, hold := range holds {
+		if hold.ID == holdID {
+			accountID = hold.AccountID
+		}
+	}
+	if accountID == "" {
+		app.printf("Ошибка: %v\n", errors.ErrHoldNotFound)
+		return
+	}
+
+	app.print("Купюры подлинные? (y/n): ")
+	app.scanner.Scan()
+	genuine := strings.ToLower(strings.TrimSpace(app.scanner.Text())) == "y"
+
+	hold, err := services.ResolveCashHold(ctx, app.storage, app.ledger, app.clock, accountID, holdID, genuine)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	if hold.Status == models.GenuineHoldStatus {
+		app.printf("Купюры подлинные: %.2f зачислено на счет %s (транзакция %s)\n", hold.Amount, accountID, hold.TransactionID)
+	} else {
+		app.printf("Купюры признаны фальшивыми и изъяты, сумма %.2f не зачислена\n", hold.Amount)
+	}
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"time"
+)
+
+// GetCashReport суммирует наличные операции всех счетов за календарный день,
+// которому принадлежит day: пополнения из кассы и снятия средств
+func GetCashReport(ctx context.Context, storage interfaces.Storage, day time.Time) (models.CashReport, error) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	to := from.AddDate(0, 0, 1)
+	report := models.CashReport{Date: from}
+
+	accounts, err := storage.GetAllAccounts(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, account := range accounts {
+		for _, tx := range account.Transactions {
+			if tx.Timestamp.Before(from) || !tx.Timestamp.Before(to) {
+				continue
+			}
+
+			switch {
+			case tx.Type == models.DepositTransaction && tx.Source == models.CashSource:
+				report.CashIn += tx.Amount
+				report.Deposits++
+			case tx.Type == models.WithdrawTransaction:
+				report.CashOut += tx.Amount
+				report.Withdrawals++
+			}
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Clock источник текущего времени. Сервисы получают время через Clock,
+// чтобы история счетов и сроки проверялись на заданном времени.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock часы, возвращающие системное время
+type SystemClock struct{}
+
+// DefaultClock часы по умолчанию
+var DefaultClock Clock = SystemClock{}
+
+// Now возвращает текущее системное время
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"bankapp/interfaces"
+	"bankapp/models"
+	"bankapp/services"
+	"bankapp/storage"
+)
+
+// runCompareStorage выполняет команду compare-storage: сверку балансов и
+// количества транзакций между основным хранилищем и репликой. С -interval
+// сверка повторяется по расписанию до SIGINT/SIGTERM. Код выхода 1 -
+// при последней сверке найдены расхождения.
+func runCompareStorage(args []string) int {
+	flags := flag.NewFlagSet("compare-storage", flag.ContinueOnError)
+	primaryDSN := flags.String("primary", "", "строка подключения к основному хранилищу")
+	replicaDSN := flags.String("replica", "", "строка подключения к реплике")
+	interval := flags.Duration("interval", 0, "период повторной сверки (0 - однократно)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *primaryDSN == "" || *replicaDSN == "" || *interval < 0 {
+		fmt.Fprintln(os.Stderr, "Ошибка: укажите -primary и -replica, период не может быть отрицательным")
+		return 2
+	}
+
+	primary, err := storage.Open(*primaryDSN)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка основного хранилища: %v\n", err)
+		return 2
+	}
+	replica, err := storage.Open(*replicaDSN)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка реплики: %v\n", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer closeStorage(context.Background(), primary)
+	defer closeStorage(context.Background(), replica)
+
+	code := compareOnce(ctx, primary, replica)
+	if *interval == 0 {
+		return code
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return code
+		case <-ticker.C:
+			code = compareOnce(ctx, primary, replica)
+		}
+	}
+}
+
+// compareOnce выполняет одну сверку и печатает отчет
+func compareOnce(ctx context.Context, primary, replica interfaces.Storage) int {
+	report, err := services.CompareStorages(ctx, primary, replica, models.DefaultClock)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка сверки: %v\n", err)
+		return 2
+	}
+
+	fmt.Printf("%s: счетов в основном хранилище %d, в реплике %d, расхождений %d\n",
+		report.CheckedAt.Format("2006-01-02 15:04:05"), report.PrimaryAccounts, report.ReplicaAccounts, len(report.Drifts))
+	for _, drift := range report.Drifts {
+		fmt.Printf("  %s: %s\n", drift.AccountID, drift.Problem)
+	}
+
+	if !report.Clean() {
+		return 1
+	}
+	return 0
+}
+
+// closeStorage закрывает хранилище, если оно это поддерживает
+func closeStorage(ctx context.Context, store interfaces.Storage) {
+	if closable, ok := store.(interfaces.ClosableStorage); ok {
+		if err := closable.Close(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка при закрытии хранилища: %v\n", err)
+		}
+	}
+}
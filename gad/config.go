@@ -0,0 +1,313 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+
+	"bankapp/errors"
+	"bankapp/i18n"
+	"bankapp/models"
+	"bankapp/services"
+	"bankapp/storage"
+)
+
+// envPrefix префикс переменных окружения, переопределяющих конфигурацию
+const envPrefix = "BANKAPP_"
+
+// MemoryBackend хранилище в памяти процесса, выбираемое по умолчанию
+const MemoryBackend = "memory"
+
+// Config настройки приложения, загружаемые при запуске
+type Config struct {
+	Storage            StorageConfig      `json:"storage"`
+	Currency           string             `json:"currency"`
+	Locale             string             `json:"locale"`
+	Limits             LimitsConfig       `json:"limits"`
+	Fees               []models.FeeRule   `json:"fees"`
+	FeesNext           []models.FeeRule   `json:"fees_next"`
+	Features           FeaturesConfig     `json:"features"`
+	LimitRules         []models.LimitRule `json:"limit_rules"`
+	RiskRules          []models.RiskRule  `json:"risk_rules"`
+	StatementPageLines int                `json:"statement_page_lines"`
+	UndoWindowSeconds  int                `json:"undo_window_seconds"`
+	Sweep              models.SweepPolicy `json:"sweep"`
+
+	// Archive архивация неактивных счетов
+	Archive models.ArchivePolicy `json:"archive"`
+
+	// TransactionTypes типы транзакций, зарегистрированные оператором
+	TransactionTypes []models.TransactionTypeInfo `json:"transaction_types"`
+
+	// ReceiptKey ключ подписи квитанций в base64 (32 байта). Без него ключ
+	// создается при запуске, и квитанции проверяются только до перезапуска.
+	ReceiptKey string `json:"receipt_key"`
+
+	// Email отправка выписок по электронной почте
+	Email EmailConfig `json:"email"`
+
+	// StatementFormats оформление выписок по форматам (text, html)
+	StatementFormats map[string]models.StatementLayout `json:"statement_formats"`
+}
+
+// StorageConfig выбор хранилища: DSN вида scheme://..., схема которого
+// выбирает драйвер, или только имя драйвера в Backend. EncryptionKeys -
+// ключи шифрования данных на диске в base64, текущий - первый.
+type StorageConfig struct {
+	Backend        string   `json:"backend"`
+	DSN            string   `json:"dsn"`
+	EncryptionKeys []string `json:"encryption_keys"`
+}
+
+// Keys декодирует ключи шифрования хранилища
+func (c StorageConfig) Keys() ([][]byte, error) {
+	keys := make([][]byte, 0, len(c.EncryptionKeys))
+	for i, encoded := range c.EncryptionKeys {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("%w: ключ шифрования #%d", errors.ErrInvalidConfig, i+1)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// ReceiptSecret декодирует ключ подписи квитанций; nil, если ключ не задан
+func (c Config) ReceiptSecret() ([]byte, error) {
+	if c.ReceiptKey == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(c.ReceiptKey))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%w: receipt_key", errors.ErrInvalidConfig)
+	}
+
+	return key, nil
+}
+
+// URI возвращает DSN хранилища; без DSN - пустой адрес драйвера Backend
+func (c StorageConfig) URI() string {
+	if c.DSN != "" {
+		return c.DSN
+	}
+
+	return c.Backend + "://"
+}
+
+// LimitsConfig лимиты, назначаемые новым счетам
+type LimitsConfig struct {
+	DailyAmount float64 `json:"daily_amount"`
+	DailyCount  int     `json:"daily_count"`
+	Overdraft   float64 `json:"overdraft"`
+}
+
+// EmailConfig SMTP-сервер для писем клиентам; пустой SMTPAddr - почта отключена.
+// Username пустой, если сервер не требует входа.
+type EmailConfig struct {
+	SMTPAddr string `json:"smtp_addr"`
+	From     string `json:"from"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// FeaturesConfig флаги функциональности по именам
+type FeaturesConfig map[string]models.FeatureFlag
+
+// Default конфигурация по умолчанию, совпадающая с прежним поведением приложения
+func Default() Config {
+	return Config{
+		Storage:  StorageConfig{Backend: MemoryBackend},
+		Currency: "RUB",
+		Locale:   "ru",
+		Limits: LimitsConfig{
+			DailyAmount: models.DefaultDailyAmountLimit,
+			DailyCount:  models.DefaultDailyCountLimit,
+		},
+		// Копия, чтобы разбор файла не изменил набор правил по умолчанию
+		Fees:               append([]models.FeeRule(nil), services.DefaultFeeRules...),
+		StatementPageLines: 50,
+	}
+}
+
+// Load читает конфигурацию из JSON-файла поверх значений по умолчанию и
+// применяет переменные окружения BANKAPP_*. Пустой path - только окружение.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("%w: %s: %v", errors.ErrInvalidConfig, path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// applyEnv переопределяет поля значениями переменных окружения
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	texts := map[string]*string{
+		"STORAGE_BACKEND": &c.Storage.Backend,
+		"STORAGE_DSN":     &c.Storage.DSN,
+		"CURRENCY":        &c.Currency,
+		"SWEEP_POOL":      &c.Sweep.PoolAccountID,
+		"LOCALE":          &c.Locale,
+		"RECEIPT_KEY":     &c.ReceiptKey,
+		"SMTP_ADDR":       &c.Email.SMTPAddr,
+		"SMTP_FROM":       &c.Email.From,
+		"SMTP_USERNAME":   &c.Email.Username,
+		"SMTP_PASSWORD":   &c.Email.Password,
+	}
+	for name, field := range texts {
+		if value, ok := lookup(envPrefix + name); ok {
+			*field = value
+		}
+	}
+
+	// Ключи шифрования передаются через запятую, чтобы ротацию можно было
+	// провести без файла конфигурации
+	if value, ok := lookup(envPrefix + "STORAGE_KEYS"); ok {
+		c.Storage.EncryptionKeys = strings.Split(value, ",")
+	}
+
+	ints := map[string]*int{
+		"DAILY_COUNT_LIMIT":    &c.Limits.DailyCount,
+		"STATEMENT_PAGE_LINES": &c.StatementPageLines,
+		"UNDO_WINDOW_SECONDS":  &c.UndoWindowSeconds,
+		"SWEEP_DORMANT_DAYS":   &c.Sweep.DormantDays,
+		"ARCHIVE_DAYS":         &c.Archive.InactiveDays,
+	}
+	for name, field := range ints {
+		value, ok := lookup(envPrefix + name)
+		if !ok {
+			continue
+		}
+
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%w: %s%s=%q", errors.ErrInvalidConfig, envPrefix, name, value)
+		}
+		*field = parsed
+	}
+
+	floats := map[string]*float64{
+		"DAILY_AMOUNT_LIMIT":   &c.Limits.DailyAmount,
+		"OVERDRAFT_LIMIT":      &c.Limits.Overdraft,
+		"SWEEP_DUST_THRESHOLD": &c.Sweep.DustThreshold,
+	}
+	for name, field := range floats {
+		value, ok := lookup(envPrefix + name)
+		if !ok {
+			continue
+		}
+
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %s%s=%q", errors.ErrInvalidConfig, envPrefix, name, value)
+		}
+		*field = parsed
+	}
+
+	return nil
+}
+
+// Validate проверяет значения конфигурации
+func (c Config) Validate() error {
+	if _, err := storage.Lookup(c.Storage.URI()); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrInvalidConfig, err)
+	}
+
+	if _, err := c.Storage.Keys(); err != nil {
+		return err
+	}
+
+	if _, err := c.ReceiptSecret(); err != nil {
+		return err
+	}
+
+	if len(c.Currency) != 3 || strings.ToUpper(c.Currency) != c.Currency {
+		return fmt.Errorf("%w: код валюты %q", errors.ErrInvalidConfig, c.Currency)
+	}
+
+	if !i18n.Supported(c.Locale) {
+		return fmt.Errorf("%w: неподдерживаемая локаль %q", errors.ErrInvalidConfig, c.Locale)
+	}
+
+	if c.Limits.DailyAmount < 0 || c.Limits.DailyCount < 0 || c.Limits.Overdraft < 0 {
+		return fmt.Errorf("%w: лимиты не могут быть отрицательными", errors.ErrInvalidConfig)
+	}
+
+	if err := services.ValidateFeeRules(c.Fees); err != nil {
+		return fmt.Errorf("%w: комиссии: %v", errors.ErrInvalidConfig, err)
+	}
+
+	if err := services.ValidateFeeRules(c.FeesNext); err != nil {
+		return fmt.Errorf("%w: fees_next: %v", errors.ErrInvalidConfig, err)
+	}
+
+	if err := services.ValidateFeatureFlags(c.Features); err != nil {
+		return fmt.Errorf("%w: %v", errors.ErrInvalidConfig, err)
+	}
+
+	if err := services.ValidateLimitRules(c.LimitRules); err != nil {
+		return fmt.Errorf("%w: лимиты: %v", errors.ErrInvalidConfig, err)
+	}
+
+	if err := services.ValidateRiskRules(c.RiskRules); err != nil {
+		return fmt.Errorf("%w: правила риска: %v", errors.ErrInvalidConfig, err)
+	}
+
+	if c.Email.SMTPAddr != "" {
+		if _, _, err := net.SplitHostPort(c.Email.SMTPAddr); err != nil {
+			return fmt.Errorf("%w: email.smtp_addr: %v", errors.ErrInvalidConfig, err)
+		}
+		if _, err := mail.ParseAddress(c.Email.From); err != nil {
+			return fmt.Errorf("%w: email.from: %v", errors.ErrInvalidConfig, err)
+		}
+	}
+
+	if c.StatementPageLines < 0 {
+		return fmt.Errorf("%w: statement_page_lines", errors.ErrInvalidConfig)
+	}
+
+	if c.UndoWindowSeconds < 0 {
+		return fmt.Errorf("%w: undo_window_seconds", errors.ErrInvalidConfig)
+	}
+
+	if c.Sweep.DustThreshold < 0 || c.Sweep.DormantDays < 0 {
+		return fmt.Errorf("%w: sweep", errors.ErrInvalidConfig)
+	}
+
+	if c.Archive.InactiveDays < 0 {
+		return fmt.Errorf("%w: archive", errors.ErrInvalidConfig)
+	}
+
+	if err := services.ValidateTransactionTypes(c.TransactionTypes); err != nil {
+		return fmt.Errorf("%w: transaction_types: %v", errors.ErrInvalidConfig, err)
+	}
+
+	if err := services.ValidateStatementFormats(c.StatementFormats); err != nil {
+		return fmt.Errorf("%w: statement_formats: %v", errors.ErrInvalidConfig, err)
+	}
+
+	return nil
+}
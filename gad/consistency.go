@@ -0,0 +1,80 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// CheckConsistency сверяет баланс каждого счета с его историей транзакций
+// и журналом событий. В режиме repair несогласованные счета помещаются
+// в карантин (замораживаются на момент now с указанием причины), остальные
+// продолжают работать.
+func CheckConsistency(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage, now time.Time, repair bool) ([]models.ConsistencyIssue, error) {
+	accounts, _, err := storage.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []models.ConsistencyIssue
+	for _, account := range accounts {
+		problem, err := checkAccountConsistency(ctx, ledger, account)
+		if err != nil {
+			return issues, err
+		}
+
+		if problem == "" {
+			continue
+		}
+
+		issue := models.ConsistencyIssue{
+			AccountID: account.ID,
+			Problem:   problem,
+		}
+
+		if repair {
+			changeStatus(models.DefaultIDGenerator, account, models.FrozenStatus, "карантин: "+problem, now)
+			account.Quarantined = true
+			account.QuarantineReason = problem
+			if err := storage.SaveAccount(ctx, account); err != nil {
+				return issues, err
+			}
+			issue.Quarantined = true
+		}
+
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// checkAccountConsistency возвращает описание расхождения или пустую строку
+func checkAccountConsistency(ctx context.Context, ledger interfaces.LedgerStorage, account *models.Account) (string, error) {
+	var history float64
+	for _, tx := range account.Transactions {
+		history += tx.SignedAmount()
+	}
+
+	if !sameAmount(history, account.Balance) {
+		return fmt.Sprintf("баланс %.2f не совпадает с историей транзакций %.2f", account.Balance, history), nil
+	}
+
+	replayed, _, err := ReplayBalance(ctx, ledger, account.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if !sameAmount(replayed, account.Balance) {
+		return fmt.Sprintf("баланс %.2f не совпадает с журналом событий %.2f", account.Balance, replayed), nil
+	}
+
+	return "", nil
+}
+
+// sameAmount сравнивает денежные суммы с точностью до копейки
+func sameAmount(a, b float64) bool {
+	return math.Round((a-b)*100) == 0
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"strconv"
+	"time"
+)
+
+// pendingCreditTTL срок, в течение которого входящий платеж можно принять;
+// после него платеж возвращается отправителю
+const pendingCreditTTL = 14 * 24 * time.Hour
+
+// ReceiveExternalCredit регистрирует входящий внешний платеж на счет.
+// Если на счете включен автоприем, платеж сразу зачисляется, иначе
+// (или если зачисление невозможно) попадает во входящие и ждет решения владельца.
+func ReceiveExternalCredit(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage, clock models.Clock,
+	accountID string, amount float64, sender, reference string) (models.PendingCredit, error) {
+	if amount <= 0 {
+		return models.PendingCredit{}, errors.ErrInvalidAmount
+	}
+
+	account, err := storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return models.PendingCredit{}, err
+	}
+
+	if account.Status == models.ClosedStatus {
+		return models.PendingCredit{}, errors.ErrAccountClosed
+	}
+
+	service := &AccountServiceImpl{
+		account: account,
+		storage: storage,
+		ledger:  ledger,
+		clock:   clock,
+	}
+
+	credit := models.PendingCredit{
+		ID:         service.newID("CR"),
+		Amount:     amount,
+		Sender:     sender,
+		Reference:  reference,
+		Status:     models.PendingCreditStatus,
+		ReceivedAt: service.now(),
+	}
+	credit.ExpiresAt = credit.ReceivedAt.Add(pendingCreditTTL)
+	account.PendingCredits = append(account.PendingCredits, credit)
+
+	if account.AutoAcceptCredits && service.AcceptCredit(ctx, credit.ID) == nil {
+		return account.PendingCredits[len(account.PendingCredits)-1], nil
+	}
+
+	return credit, storage.SaveAccount(ctx, account)
+}
+
+// ListPendingCredits возвращает входящие платежи, ожидающие решения
+func (s *AccountServiceImpl) ListPendingCredits(ctx context.Context) []models.PendingCredit {
+	now := s.now()
+
+	var pending []models.PendingCredit
+	for _, credit := range s.account.PendingCredits {
+		if credit.Active(now) {
+			pending = append(pending, credit)
+		}
+	}
+
+	return pending
+}
+
+// AcceptCredit принимает входящий платеж и зачисляет его на счет
+func (s *AccountServiceImpl) AcceptCredit(ctx context.Context, creditID string) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "accept_credit", 0, "платеж "+creditID, err)
+	}()
+
+	credit, err := s.findPendingCredit(ctx, creditID)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.Deposit(ctx, credit.Amount, models.TransferInSource)
+	if err != nil {
+		return err
+	}
+
+	credit.Status = models.AcceptedCreditStatus
+	credit.ResolvedAt = s.now()
+	credit.TransactionID = result.TransactionID
+
+	return s.saveAccount(ctx)
+}
+
+// RejectCredit отклоняет входящий платеж: средства возвращаются отправителю
+// и на счет не зачисляются
+func (s *AccountServiceImpl) RejectCredit(ctx context.Context, creditID string) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "reject_credit", 0, "платеж "+creditID, err)
+	}()
+
+	credit, err := s.findPendingCredit(ctx, creditID)
+	if err != nil {
+		return err
+	}
+
+	credit.Status = models.RejectedCreditStatus
+	credit.ResolvedAt = s.now()
+
+	return s.saveAccount(ctx)
+}
+
+// SetAutoAcceptCredits включает или отключает автоприем входящих платежей
+func (s *AccountServiceImpl) SetAutoAcceptCredits(ctx context.Context, enabled bool) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "set_auto_accept", 0, strconv.FormatBool(enabled), err)
+	}()
+
+	s.account.AutoAcceptCredits = enabled
+	return s.saveAccount(ctx)
+}
+
+// findPendingCredit ищет необработанный входящий платеж по ID. Просроченный
+// платеж возвращается отправителю, вызывающий получает ErrCreditExpired.
+func (s *AccountServiceImpl) findPendingCredit(ctx context.Context, creditID string) (*models.PendingCredit, error) {
+	if len(s.expirePending(ctx, s.now())) > 0 {
+		if err := s.saveAccount(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range s.account.PendingCredits {
+		credit := &s.account.PendingCredits[i]
+		if credit.ID != creditID {
+			continue
+		}
+
+		switch credit.Status {
+		case models.PendingCreditStatus:
+			return credit, nil
+		case models.ExpiredCreditStatus:
+			return nil, errors.ErrCreditExpired
+		default:
+			return nil, errors.ErrCreditResolved
+		}
+	}
+
+	return nil, errors.ErrCreditNotFound
+}
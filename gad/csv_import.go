@@ -0,0 +1,348 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"encoding/csv"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Имена файлов в отчете об ошибках импорта
+const (
+	accountsFile     = "accounts.csv"
+	transactionsFile = "transactions.csv"
+)
+
+// csvTable прочитанный CSV-файл: индексы колонок по заголовку и строки
+// с номерами строк в исходном файле
+type csvTable struct {
+	file    string
+	columns map[string]int
+	rows    [][]string
+	lines   []int
+}
+
+// value возвращает значение колонки в строке или пустую строку
+func (t csvTable) value(row []string, column string) string {
+	i, ok := t.columns[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// ImportCSV загружает счета и историю транзакций из CSV-файлов другой системы.
+//
+// Колонки счетов: id, owner_name, balance и необязательные created_at, owner
+// (имя существующего пользователя) и pin. Колонки транзакций: id, account_id,
+// timestamp, type, amount и необязательные direction, message, counterparty_id.
+// У переводов и сторно direction обязателен. Баланс каждого счета должен
+// совпадать с суммой его транзакций.
+// Остальные поля счета получают значения по умолчанию, поэтому выписка в
+// CSV загружается обратно без transfer_id, вложений, лимитов и хешей
+// цепочки; счета с отрицательным балансом не загружаются.
+//
+// Сначала проверяются все строки обоих файлов. Если найдена хотя бы одна
+// ошибка, в хранилище ничего не записывается, а отчет содержит ошибки по
+// строкам. Счета сохраняются одной записью, если хранилище это умеет, и
+// только после этого их транзакции попадают в журнал событий. ID и время
+// открытия новых счетов задают ids и clock.
+func ImportCSV(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage, ids models.IDGenerator, clock models.Clock,
+	accountsCSV, transactionsCSV io.Reader) (models.ImportReport, error) {
+	var report models.ImportReport
+
+	accountRows, err := readCSV(accountsFile, accountsCSV, &report, "id", "owner_name", "balance")
+	if err != nil {
+		return report, err
+	}
+
+	transactionRows, err := readCSV(transactionsFile, transactionsCSV, &report, "id", "account_id", "timestamp", "type", "amount")
+	if err != nil {
+		return report, err
+	}
+
+	var order []string
+	accounts := make(map[string]*models.Account)
+	balances := make(map[string]float64)
+	accountLines := make(map[string]int)
+	seen := make(map[string]bool)
+	for i, row := range accountRows.rows {
+		id := accountRows.value(row, "id")
+		account, balance, err := parseAccountRow(ctx, storage, ids, clock, accountRows, row)
+		if err == nil && seen[id] {
+			err = fmt.Errorf("%w: %s", errors.ErrDuplicateID, id)
+		}
+		seen[id] = true
+
+		if err != nil {
+			report.Errors = append(report.Errors, models.ImportRowError{File: accountsFile, Line: accountRows.lines[i], Err: err})
+			continue
+		}
+
+		order = append(order, id)
+		accounts[id] = account
+		balances[id] = balance
+		accountLines[id] = accountRows.lines[i]
+	}
+
+	seenTransactions := make(map[string]bool)
+	for i, row := range transactionRows.rows {
+		tx, accountID, err := parseTransactionRow(transactionRows, row)
+		switch {
+		case err != nil:
+		case seenTransactions[tx.ID]:
+			err = fmt.Errorf("%w: %s", errors.ErrDuplicateID, tx.ID)
+		case accounts[accountID] == nil && seen[accountID]:
+			// Счет отклонен, ошибка уже есть в отчете
+			continue
+		case accounts[accountID] == nil:
+			err = fmt.Errorf("%w: %s", errors.ErrAccountNotFound, accountID)
+		}
+
+		if err != nil {
+			report.Errors = append(report.Errors, models.ImportRowError{File: transactionsFile, Line: transactionRows.lines[i], Err: err})
+			continue
+		}
+
+		seenTransactions[tx.ID] = true
+		account := accounts[accountID]
+		account.Transactions = append(account.Transactions, tx)
+		account.Balance += tx.SignedAmount()
+	}
+
+	for _, id := range order {
+		account := accounts[id]
+		if !sameAmount(account.Balance, balances[id]) {
+			err := fmt.Errorf("%w: %.2f != %.2f", errors.ErrBalanceMismatch, balances[id], account.Balance)
+			report.Errors = append(report.Errors, models.ImportRowError{File: accountsFile, Line: accountLines[id], Err: err})
+		}
+	}
+
+	if len(report.Errors) > 0 {
+		sort.SliceStable(report.Errors, func(i, j int) bool {
+			a, b := report.Errors[i], report.Errors[j]
+			if a.File != b.File {
+				return a.File == accountsFile
+			}
+			return a.Line < b.Line
+		})
+		return report, errors.ErrImportRejected
+	}
+
+	batch := make([]*models.Account, 0, len(order))
+	var events []models.AccountEvent
+	for _, id := range order {
+		account := accounts[id]
+		sort.SliceStable(account.Transactions, func(i, j int) bool {
+			return account.Transactions[i].Timestamp.Before(account.Transactions[j].Timestamp)
+		})
+
+		for _, tx := range account.Transactions {
+			amount := tx.Amount
+			if tx.Type == models.ReversalTransaction {
+				// Сумма сторно в журнале хранится со знаком
+				amount = tx.SignedAmount()
+			}
+			events = append(events, newEvent(account.ID, importEventType(tx), amount, tx.ID, tx.Timestamp))
+		}
+		batch = append(batch, account)
+	}
+
+	if err := saveAccounts(ctx, storage, batch...); err != nil {
+		return report, err
+	}
+
+	report.Accounts = len(batch)
+	report.Transactions = len(events)
+	report.Committed = true
+
+	return report, recordEvents(ctx, ledger, events)
+}
+
+// readCSV читает CSV-файл с заголовком. Строки, которые не удалось разобрать,
+// попадают в отчет, отсутствие обязательной колонки - ошибка всего файла.
+func readCSV(file string, r io.Reader, report *models.ImportReport, required ...string) (csvTable, error) {
+	table := csvTable{file: file, columns: make(map[string]int)}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return table, fmt.Errorf("%s: %w", file, err)
+	}
+	for i, column := range header {
+		table.columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, column := range required {
+		if _, ok := table.columns[column]; !ok {
+			return table, fmt.Errorf("%s: %w: %s", file, errors.ErrMissingColumn, column)
+		}
+	}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return table, nil
+		}
+
+		var parseErr *csv.ParseError
+		if stderrors.As(err, &parseErr) {
+			report.Errors = append(report.Errors, models.ImportRowError{File: file, Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return table, fmt.Errorf("%s: %w", file, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		table.rows = append(table.rows, row)
+		table.lines = append(table.lines, line)
+	}
+}
+
+// parseAccountRow создает счет из строки файла счетов и возвращает
+// заявленный в файле баланс
+func parseAccountRow(ctx context.Context, storage interfaces.Storage, ids models.IDGenerator, clock models.Clock, table csvTable, row []string) (*models.Account, float64, error) {
+	id := table.value(row, "id")
+	if id == "" {
+		return nil, 0, fmt.Errorf("%w: id", errors.ErrEmptyField)
+	}
+
+	ownerName := table.value(row, "owner_name")
+	if ownerName == "" {
+		return nil, 0, fmt.Errorf("%w: owner_name", errors.ErrEmptyField)
+	}
+
+	balance, err := parseImportAmount(table.value(row, "balance"))
+	if err != nil {
+		return nil, 0, err
+	}
+	if balance < 0 {
+		return nil, 0, fmt.Errorf("%w: %.2f", errors.ErrNegativeBalance, balance)
+	}
+
+	if _, err := storage.LoadAccount(ctx, id); err == nil {
+		return nil, 0, fmt.Errorf("%w: %s", errors.ErrAccountExists, id)
+	}
+
+	account := models.NewAccount(ownerName, ids, clock)
+	account.ID = id
+
+	if value := table.value(row, "created_at"); value != "" {
+		if account.CreatedAt, err = parseImportTime(value); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if username := table.value(row, "owner"); username != "" {
+		user, err := storage.FindUserByUsername(ctx, username)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: %s", err, username)
+		}
+		account.OwnerID = user.ID
+	}
+
+	if pin := table.value(row, "pin"); pin != "" {
+		if err := SetPIN(account, pin); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return account, balance, nil
+}
+
+// parseTransactionRow создает транзакцию из строки файла транзакций
+// и возвращает ID счета, к которому она относится
+func parseTransactionRow(table csvTable, row []string) (models.Transaction, string, error) {
+	tx := models.Transaction{
+		ID:             table.value(row, "id"),
+		Type:           models.TransactionType(strings.ToUpper(table.value(row, "type"))),
+		Direction:      models.EntryDirection(strings.ToUpper(table.value(row, "direction"))),
+		Message:        table.value(row, "message"),
+		CounterpartyID: table.value(row, "counterparty_id"),
+	}
+	accountID := table.value(row, "account_id")
+
+	if tx.ID == "" {
+		return tx, "", fmt.Errorf("%w: id", errors.ErrEmptyField)
+	}
+	if accountID == "" {
+		return tx, "", fmt.Errorf("%w: account_id", errors.ErrEmptyField)
+	}
+
+	var err error
+	if tx.Timestamp, err = parseImportTime(table.value(row, "timestamp")); err != nil {
+		return tx, "", err
+	}
+
+	if tx.Amount, err = parseImportAmount(table.value(row, "amount")); err != nil {
+		return tx, "", err
+	}
+	if tx.Amount <= 0 {
+		return tx, "", fmt.Errorf("%w: %s", errors.ErrInvalidAmount, table.value(row, "amount"))
+	}
+
+	switch tx.Type {
+	case models.DepositTransaction:
+		tx.Direction = models.CreditEntry
+	case models.WithdrawTransaction, models.FeeTransaction:
+		tx.Direction = models.DebitEntry
+	case models.TransferTransaction, models.ReversalTransaction:
+		if tx.Direction != models.CreditEntry && tx.Direction != models.DebitEntry {
+			return tx, "", fmt.Errorf("%w: %s %q", errors.ErrUnknownTxType, tx.Type, tx.Direction)
+		}
+	default:
+		return tx, "", fmt.Errorf("%w: %s", errors.ErrUnknownTxType, tx.Type)
+	}
+
+	return tx, accountID, nil
+}
+
+// importEventType возвращает тип события журнала для импортированной транзакции
+func importEventType(tx models.Transaction) models.EventType {
+	switch tx.Type {
+	case models.DepositTransaction:
+		return models.DepositEvent
+	case models.WithdrawTransaction:
+		return models.WithdrawEvent
+	case models.FeeTransaction:
+		return models.FeeEvent
+	case models.ReversalTransaction:
+		return models.ReversalEvent
+	}
+
+	if tx.Direction == models.CreditEntry {
+		return models.TransferInEvent
+	}
+	return models.TransferOutEvent
+}
+
+// parseImportAmount разбирает сумму; допускается десятичная запятая
+func parseImportAmount(value string) (float64, error) {
+	amount, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("%w: %q", errors.ErrInvalidAmount, value)
+	}
+	return amount, nil
+}
+
+// parseImportTime разбирает дату в формате RFC 3339 или ГГГГ-ММ-ДД
+func parseImportTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", errors.ErrInvalidDate, value)
+}
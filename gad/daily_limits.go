@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"time"
+)
+
+// GetDailyAllowance возвращает использованную и оставшуюся часть дневных лимитов
+func (s *AccountServiceImpl) GetDailyAllowance(ctx context.Context) models.DailyAllowance {
+	used, count := outgoingToday(s.account, s.now())
+
+	allowance := models.DailyAllowance{
+		AmountLimit: s.account.DailyAmountLimit,
+		AmountUsed:  used,
+		CountLimit:  s.account.DailyCountLimit,
+		CountUsed:   count,
+	}
+
+	if allowance.AmountLimit > 0 {
+		allowance.AmountRemaining = max(allowance.AmountLimit-used, 0)
+	}
+
+	if allowance.CountLimit > 0 {
+		allowance.CountRemaining = max(allowance.CountLimit-count, 0)
+	}
+
+	return allowance
+}
+
+// checkDailyLimits проверяет, что списание укладывается в дневные лимиты счета
+func (s *AccountServiceImpl) checkDailyLimits(amount float64) error {
+	used, count := outgoingToday(s.account, s.now())
+
+	if s.account.DailyAmountLimit > 0 && used+amount > s.account.DailyAmountLimit {
+		return errors.ErrDailyLimitExceeded
+	}
+
+	if s.account.DailyCountLimit > 0 && count+1 > s.account.DailyCountLimit {
+		return errors.ErrDailyLimitExceeded
+	}
+
+	return nil
+}
+
+// outgoingToday считает по истории счета сумму и количество снятий
+// и исходящих переводов за календарные сутки, содержащие now
+func outgoingToday(account *models.Account, now time.Time) (float64, int) {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var used float64
+	var count int
+	for _, tx := range account.Transactions {
+		if tx.Timestamp.Before(startOfDay) {
+			continue
+		}
+
+		outgoing := tx.Type == models.WithdrawTransaction ||
+			(tx.Type == models.TransferTransaction && tx.Direction == models.DebitEntry)
+		if !outgoing {
+			continue
+		}
+
+		used += tx.Amount
+		count++
+	}
+
+	return used, count
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"time"
+)
+
+// SweepDormantBalances переводит на пул-счет положительные остатки ниже
+// порога с закрытых счетов и счетов без клиентских операций дольше
+// policy.DormantDays дней. Каждый перевод проводится обеими ногами через
+// журнал событий и записывается в журнал аудита. В режиме dryRun счета
+// не изменяются, отчет показывает, что было бы переведено.
+func (s *AdminServiceImpl) SweepDormantBalances(ctx context.Context, policy models.SweepPolicy, dryRun bool) (report models.SweepReport, err error) {
+	report = models.SweepReport{PoolAccountID: policy.PoolAccountID, DryRun: dryRun}
+
+	if policy.PoolAccountID == "" || policy.DustThreshold <= 0 {
+		return report, errors.ErrSweepNotConfigured
+	}
+
+	pool, err := s.storage.LoadAccount(ctx, policy.PoolAccountID)
+	if err != nil {
+		return report, err
+	}
+
+	if err := checkOperable(pool); err != nil {
+		return report, err
+	}
+
+	accounts, _, err := s.storage.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return report, err
+	}
+
+	dormantSince := s.clock.Now().AddDate(0, 0, -policy.DormantDays)
+	for _, account := range accounts {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		if account.ID == pool.ID || account.Balance == 0 {
+			continue
+		}
+		if account.Status != models.ClosedStatus && lastActivity(account).After(dormantSince) {
+			continue
+		}
+
+		result := models.SweepResult{AccountID: account.ID, Amount: account.Balance}
+		switch {
+		case account.Status == models.FrozenStatus:
+			result.SkipReason = "счет заморожен"
+		case account.Balance < 0:
+			result.SkipReason = "отрицательный остаток"
+		case account.Balance >= policy.DustThreshold:
+			result.SkipReason = "остаток выше порога"
+		}
+
+		if result.SkipReason != "" {
+			report.Results = append(report.Results, result)
+			report.Skipped++
+			continue
+		}
+
+		if !dryRun {
+			result.TransactionID, err = s.sweep(ctx, account, pool)
+			s.auditAdmin(ctx, "sweep_balance", account.ID, result.Amount, "пул-счет "+pool.ID, err)
+			if err != nil {
+				return report, err
+			}
+		}
+
+		result.Swept = true
+		report.Results = append(report.Results, result)
+		report.Swept++
+		report.Total += result.Amount
+	}
+
+	return report, nil
+}
+
+// sweep переводит весь остаток счета на пул-счет и сохраняет оба счета
+func (s *AdminServiceImpl) sweep(ctx context.Context, account, pool *models.Account) (string, error) {
+	service := &AccountServiceImpl{
+		account: account,
+		storage: s.storage,
+		ledger:  s.ledger,
+		ids:     s.ids,
+		clock:   s.clock,
+	}
+
+	transaction := service.postTransfer(pool, account.Balance, 0, false)
+
+	return transaction.ID, service.saveAccount(ctx, pool)
+}
+
+// lastActivity возвращает время последней клиентской операции по счету;
+// служебные записи и комиссии активностью не считаются
+func lastActivity(account *models.Account) time.Time {
+	var last time.Time
+	for _, tx := range account.Transactions {
+		switch tx.Type {
+		case models.StatusTransaction, models.OverdraftLimitTransaction, models.FeeTransaction,
+			models.FeeCorrectionTransaction, models.BalanceCorrectionTransaction:
+			continue
+		}
+
+		if tx.Timestamp.After(last) {
+			last = tx.Timestamp
+		}
+	}
+
+	return last
+}
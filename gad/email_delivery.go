@@ -0,0 +1,246 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/i18n"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// mimeLineLength длина строки тела письма в base64 по RFC 2045
+const mimeLineLength = 76
+
+// SMTPEmailSender отправляет письма через SMTP-сервер
+type SMTPEmailSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPEmailSender создает отправку писем через сервер addr (host:port)
+// от адреса from. auth может быть nil, если сервер не требует входа.
+func NewSMTPEmailSender(addr, from string, auth smtp.Auth) interfaces.EmailSender {
+	return &SMTPEmailSender{
+		addr: addr,
+		from: from,
+		auth: auth,
+	}
+}
+
+// SendEmail отправляет письмо. Текст и HTML передаются альтернативными частями.
+func (s *SMTPEmailSender) SendEmail(ctx context.Context, message models.EmailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return errors.ErrInvalidEmail
+	}
+
+	data, err := buildEmail(s.from, to.Address, message)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{to.Address}, data)
+}
+
+// buildEmail формирует MIME-сообщение: заголовки и тело в base64,
+// при наличии HTML - multipart/alternative
+func buildEmail(from, to string, message models.EmailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if message.HTMLBody == "" {
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+		writeBase64Lines(&buf, message.TextBody)
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.TextBody},
+		{"text/html; charset=utf-8", message.HTMLBody},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64Lines(w, part.content)
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+// writeBase64Lines записывает содержимое в base64 строками по mimeLineLength
+func writeBase64Lines(w io.Writer, content string) {
+	encoded := base64.StdEncoding.EncodeToString([]byte(content))
+	for len(encoded) > mimeLineLength {
+		w.Write([]byte(encoded[:mimeLineLength] + "\r\n"))
+		encoded = encoded[mimeLineLength:]
+	}
+	w.Write([]byte(encoded + "\r\n"))
+}
+
+// StatementMailer отправляет выписки за период письмом: текстом и,
+// по запросу, HTML-версией
+type StatementMailer struct {
+	sender interfaces.EmailSender
+	text   interfaces.StatementRenderer
+	html   interfaces.StatementRenderer
+	tr     *i18n.Translator
+}
+
+// NewStatementMailer создает рассылку выписок через sender
+func NewStatementMailer(sender interfaces.EmailSender, text, html interfaces.StatementRenderer, tr *i18n.Translator) *StatementMailer {
+	return &StatementMailer{
+		sender: sender,
+		text:   text,
+		html:   html,
+		tr:     tr,
+	}
+}
+
+// Mail оформляет выписку и отправляет ее на адрес to
+func (m *StatementMailer) Mail(ctx context.Context, to string, statement models.Statement, html bool) error {
+	var text bytes.Buffer
+	if err := m.text.Render(ctx, statement, &text); err != nil {
+		return err
+	}
+
+	message := models.EmailMessage{
+		To: to,
+		Subject: m.tr.Sprintf("Выписка по счету %s за %s - %s", statement.AccountID,
+			statement.From.Format("2006-01-02"), statement.To.Add(-time.Nanosecond).Format("2006-01-02")),
+		TextBody: text.String(),
+	}
+
+	if html {
+		var page bytes.Buffer
+		if err := m.html.Render(ctx, statement, &page); err != nil {
+			return err
+		}
+		message.HTMLBody = page.String()
+	}
+
+	return m.sender.SendEmail(ctx, message)
+}
+
+// WithStatementMailer задает отправку выписок по электронной почте
+func WithStatementMailer(mailer *StatementMailer) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.mailer = mailer
+	}
+}
+
+// SetStatementEmail сохраняет адрес для выписок и подписку на ежемесячную
+// рассылку. Пустой адрес отключает отправку выписок по почте.
+func (s *AccountServiceImpl) SetStatementEmail(ctx context.Context, email string, monthly bool) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "set_statement_email", 0, "", err)
+	}()
+
+	email = strings.TrimSpace(email)
+	if email != "" {
+		address, err := mail.ParseAddress(email)
+		if err != nil {
+			return errors.ErrInvalidEmail
+		}
+		email = address.Address
+	} else if monthly {
+		return errors.ErrNoStatementEmail
+	}
+
+	s.account.StatementEmail = email
+	s.account.MonthlyStatements = monthly
+	return s.saveAccount(ctx)
+}
+
+// EmailStatement отправляет выписку за период [from, to) на адрес счета
+func (s *AccountServiceImpl) EmailStatement(ctx context.Context, from, to time.Time, html bool) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "email_statement", 0, "", err)
+	}()
+
+	if s.mailer == nil {
+		return errors.ErrNoEmailSender
+	}
+
+	if s.account.StatementEmail == "" {
+		return errors.ErrNoStatementEmail
+	}
+
+	return s.mailer.Mail(ctx, s.account.StatementEmail, buildStatement(s.account, from, to, s.now()), html)
+}
+
+// SendMonthlyStatements рассылает выписки за календарный месяц period по
+// счетам, подписанным на ежемесячную рассылку. Счет, которому выписка за
+// этот месяц уже отправлена, пропускается, поэтому повторный запуск
+// дошлет только неотправленные выписки. Ошибка отправки по одному счету
+// не прерывает рассылку.
+func SendMonthlyStatements(ctx context.Context, storage interfaces.Storage, mailer *StatementMailer, clock models.Clock, period time.Time) (models.StatementMailReport, error) {
+	from := time.Date(period.Year(), period.Month(), 1, 0, 0, 0, 0, period.Location())
+	to := from.AddDate(0, 1, 0)
+	report := models.StatementMailReport{Period: from.Format("2006-01")}
+
+	accounts, _, err := storage.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return report, err
+	}
+
+	for _, account := range accounts {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		if !account.MonthlyStatements || account.StatementEmail == "" ||
+			account.Status == models.ClosedStatus || account.Archived() || account.StatementEmailPeriod >= report.Period {
+			continue
+		}
+
+		result := models.StatementMailResult{AccountID: account.ID, Email: account.StatementEmail}
+		if err := mailer.Mail(ctx, account.StatementEmail, buildStatement(account, from, to, clock.Now()), true); err != nil {
+			result.Error = err.Error()
+			report.Failed++
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		account.StatementEmailPeriod = report.Period
+		if err := storage.SaveAccount(ctx, account); err != nil {
+			return report, err
+		}
+		report.Sent++
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
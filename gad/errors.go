@@ -1,11 +1,119 @@
-package errors
-
-import "errors"
-
-// Кастомные ошибки
-var (
-	ErrInsufficientFunds   = errors.New("недостаточно средств на счете")
-	ErrInvalidAmount       = errors.New("некорректная сумма (отрицательная или нулевая)")
-	ErrAccountNotFound     = errors.New("счет не найден")
-	ErrSameAccountTransfer = errors.New("попытка перевода на тот же счёт")
-)
+package errors
+
+import "errors"
+
+// Кастомные ошибки
+var (
+	ErrInsufficientFunds    = errors.New("недостаточно средств на счете")
+	ErrInvalidAmount        = errors.New("некорректная сумма (отрицательная или нулевая)")
+	ErrAccountNotFound      = errors.New("счет не найден")
+	ErrSameAccountTransfer  = errors.New("попытка перевода на тот же счёт")
+	ErrUnbalancedEntries    = errors.New("сумма проводки не равна нулю")
+	ErrMissingReasonCode    = errors.New("не указан код причины проводки")
+	ErrEmptyEntries         = errors.New("проводка не содержит записей")
+	ErrUnauthorized         = errors.New("операция не разрешена")
+	ErrUnsupportedFormat    = errors.New("неподдерживаемый формат экспорта")
+	ErrInvalidPIN           = errors.New("неверный PIN-код")
+	ErrWeakPIN              = errors.New("PIN-код должен состоять из 4-6 цифр")
+	ErrPINNotSet            = errors.New("для счета не установлен PIN-код")
+	ErrAccountLocked        = errors.New("счет временно заблокирован из-за неверных попыток ввода PIN-кода")
+	ErrAccountFrozen        = errors.New("счет заморожен")
+	ErrAccountExists        = errors.New("счет уже существует")
+	ErrAccountClosed        = errors.New("счет закрыт")
+	ErrNonZeroBalance       = errors.New("на счете остались средства или задолженность")
+	ErrInvalidDepositSource = errors.New("неизвестный источник пополнения")
+	ErrCreditNotFound       = errors.New("входящий платеж не найден")
+	ErrCreditResolved       = errors.New("входящий платеж уже обработан")
+	ErrCreditExpired        = errors.New("срок приема платежа истек")
+	ErrStorageClosed        = errors.New("хранилище закрыто")
+	ErrInvalidPreferences   = errors.New("некорректные настройки отображения")
+	ErrInvalidStatus        = errors.New("недопустимая смена статуса счета")
+	ErrUserNotFound         = errors.New("пользователь не найден")
+	ErrUserExists           = errors.New("пользователь с таким именем уже существует")
+	ErrInvalidCredentials   = errors.New("неверное имя пользователя или пароль")
+	ErrWeakPassword         = errors.New("пароль должен содержать не менее 6 символов")
+	ErrAccessDenied         = errors.New("доступ запрещен")
+	ErrSnapshotNotFound     = errors.New("снимок баланса не найден")
+	ErrTransactionBlocked   = errors.New("операция заблокирована по результатам оценки риска")
+	ErrTransactionNotFound  = errors.New("транзакция не найдена")
+	ErrAttachmentNotFound   = errors.New("вложение не найдено")
+	ErrAttachmentTooLarge   = errors.New("вложение слишком большое")
+	ErrBlobStoreMissing     = errors.New("хранилище вложений не настроено")
+	ErrBlobNotFound         = errors.New("объект в хранилище вложений не найден")
+	ErrOverdraftExceeded    = errors.New("превышен лимит овердрафта")
+	ErrDailyLimitExceeded   = errors.New("превышен дневной лимит операций")
+	ErrAlreadyReversed      = errors.New("транзакция уже сторнирована")
+	ErrNotReversible        = errors.New("транзакцию этого типа нельзя сторнировать")
+	ErrInvalidConfig        = errors.New("некорректная конфигурация")
+	ErrUnsupportedLocale    = errors.New("неподдерживаемый язык интерфейса")
+	ErrInvalidExpression    = errors.New("некорректное выражение правила")
+	ErrOperationRestricted  = errors.New("операция запрещена правилом")
+	ErrInvalidStatementKey  = errors.New("некорректный ключ шифрования выписок")
+	ErrNoStatementSender    = errors.New("доставка выписок не настроена")
+	ErrInvalidFeatureFlag   = errors.New("некорректный флаг функциональности")
+	ErrImportRejected       = errors.New("импорт отклонен: в файлах есть ошибки")
+	ErrMissingColumn        = errors.New("в файле нет обязательной колонки")
+	ErrEmptyField           = errors.New("не заполнено обязательное поле")
+	ErrDuplicateID          = errors.New("повторяющийся идентификатор")
+	ErrNegativeBalance      = errors.New("отрицательный баланс")
+	ErrBalanceMismatch      = errors.New("баланс не совпадает с историей транзакций")
+	ErrInvalidDate          = errors.New("некорректная дата")
+	ErrUnknownTxType        = errors.New("неизвестный тип транзакции")
+	ErrBackupVersion        = errors.New("неподдерживаемая версия резервной копии")
+	ErrBackupChecksum       = errors.New("контрольная сумма резервной копии не совпадает")
+	ErrBackupUserConflict   = errors.New("имя из резервной копии занято другим пользователем")
+	ErrInvalidAlias         = errors.New("некорректный псевдоним или номер телефона")
+	ErrAliasTaken           = errors.New("псевдоним уже занят")
+	ErrAliasNotFound        = errors.New("псевдоним не найден")
+	ErrInvalidCashNotes     = errors.New("некорректная разбивка по купюрам")
+	ErrCashNotesMismatch    = errors.New("сумма купюр не совпадает с суммой взноса")
+	ErrHoldNotFound         = errors.New("удержание по подозрительным купюрам не найдено")
+	ErrHoldResolved         = errors.New("подозрительные купюры уже проверены")
+	ErrInvalidCategory      = errors.New("некорректная категория")
+	ErrInvalidTag           = errors.New("некорректный тег")
+	ErrNoteTooLong          = errors.New("заметка слишком длинная")
+	ErrInvalidLink          = errors.New("счет нельзя привязать как дочерний")
+	ErrNotLinkedChild       = errors.New("счет не привязан как дочерний")
+	ErrCategoryBlocked      = errors.New("категория запрещена родительским контролем")
+	ErrParentLimitExceeded  = errors.New("превышен дневной лимит, установленный родителем")
+	ErrSweepNotConfigured   = errors.New("перевод остатков на пул-счет не настроен")
+	ErrInvalidTimelineKind  = errors.New("неизвестный вид события хронологии")
+	ErrInvalidTxType        = errors.New("некорректное описание типа транзакции")
+	ErrInvalidDSN           = errors.New("некорректная строка подключения")
+	ErrUnknownDriver        = errors.New("неизвестный драйвер хранилища")
+	ErrStorageTampered      = errors.New("данные хранилища повреждены или изменены")
+	ErrStorageKeyMissing    = errors.New("нет ключа для расшифровки хранилища")
+	ErrInvalidStorageKey    = errors.New("ключ шифрования хранилища должен быть 32 байта")
+	ErrStoragePlaintext     = errors.New("файл хранилища не зашифрован, хотя заданы ключи шифрования")
+	ErrNotUnderReview       = errors.New("операция не ожидает проверки")
+	ErrOperationDisabled    = errors.New("операция временно приостановлена")
+	ErrNotSuspendable       = errors.New("операции этого типа нельзя приостановить")
+	ErrTransferNotFound     = errors.New("перевод, ожидающий подтверждения, не найден")
+	ErrTransferResolved     = errors.New("перевод уже подтвержден или отменен")
+	ErrTransferExpired      = errors.New("срок подтверждения перевода истек")
+	ErrInvalidGrant         = errors.New("некорректные условия доверенности")
+	ErrGrantNotFound        = errors.New("действующая доверенность не найдена")
+	ErrGrantLimitExceeded   = errors.New("превышен лимит переводов по доверенности")
+	ErrInvalidLoan          = errors.New("некорректные условия кредита")
+	ErrNotLoanAccount       = errors.New("счет не является кредитным")
+	ErrReceiptInvalid       = errors.New("код проверки квитанции не совпадает")
+	ErrNoReceiptKey         = errors.New("ключ подписи квитанций не задан")
+	ErrInvalidEmail         = errors.New("некорректный адрес электронной почты")
+	ErrNoStatementEmail     = errors.New("не указан адрес для отправки выписок")
+	ErrNoEmailSender        = errors.New("отправка почты не настроена")
+	ErrInputFailed          = errors.New("ошибка чтения ввода")
+	ErrInvalidCheckpoint    = errors.New("некорректная точка продолжения выгрузки")
+	ErrAccountArchived      = errors.New("счет в архиве")
+	ErrNotArchived          = errors.New("счет не в архиве")
+	ErrArchiveNotConfigured = errors.New("не задан срок неактивности для архивации")
+	ErrNothingToUndo        = errors.New("нет операции для отмены")
+	ErrUndoExpired          = errors.New("время на отмену операции истекло")
+	ErrUndoBlocked          = errors.New("после операции средства уже использованы")
+	ErrInvalidSimulation    = errors.New("некорректные параметры прогона")
+	ErrInvariantViolated    = errors.New("нарушен инвариант")
+	ErrRoundTripLoss        = errors.New("выгрузка и обратная загрузка теряют данные")
+	ErrInvalidLayout        = errors.New("некорректное оформление выписки")
+)
+
+// ErrConcurrentModification сохранение счета с устаревшей версией
+var ErrConcurrentModification = errors.New("счет был изменен другой операцией")
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+)
+
+// snapshotInterval количество событий журнала между снимками баланса
+const snapshotInterval = 100
+
+// recordEvent добавляет событие в журнал счета и периодически сохраняет снимок баланса.
+// Время события at совпадает со временем транзакции. Вызывается после
+// сохранения счета, чтобы неудачная запись не оставила в журнале события.
+func recordEvent(ctx context.Context, ledger interfaces.LedgerStorage, accountID string, eventType models.EventType, amount float64, transactionID string, at time.Time) error {
+	return appendEvent(ctx, ledger, newEvent(accountID, eventType, amount, transactionID, at))
+}
+
+// newEvent создает событие журнала по транзакции счета
+func newEvent(accountID string, eventType models.EventType, amount float64, transactionID string, at time.Time) models.AccountEvent {
+	return models.AccountEvent{
+		AccountID:     accountID,
+		Type:          eventType,
+		Amount:        amount,
+		TransactionID: transactionID,
+		Timestamp:     at,
+	}
+}
+
+// recordEvents добавляет в журнал события уже сохраненных счетов
+func recordEvents(ctx context.Context, ledger interfaces.LedgerStorage, events []models.AccountEvent) error {
+	for _, event := range events {
+		if err := appendEvent(ctx, ledger, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queueEvent откладывает событие журнала до сохранения счета: saveAccount
+// записывает накопленные события только после успешной записи счетов, так
+// что неудачное сохранение или повтор при конфликте версий не оставляют
+// в журнале лишних событий
+func (s *AccountServiceImpl) queueEvent(accountID string, eventType models.EventType, amount float64, transactionID string, at time.Time) {
+	s.queued = append(s.queued, newEvent(accountID, eventType, amount, transactionID, at))
+}
+
+// recordQueued записывает в журнал события сохраненной операции. Операция
+// уже сохранена, поэтому ошибка журнала ее не отменяет: ошибка пишется
+// в лог, а расхождение баланса с журналом покажет CheckConsistency.
+func (s *AccountServiceImpl) recordQueued(ctx context.Context, events []models.AccountEvent) {
+	if err := recordEvents(ctx, s.ledger, events); err != nil {
+		logger := s.logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.ErrorContext(ctx, "события операции не записаны в журнал",
+			slog.String("account_id", s.account.ID), slog.String("error", err.Error()))
+	}
+}
+
+// appendEvent добавляет готовое событие в журнал счета и периодически
+// сохраняет снимок баланса
+func appendEvent(ctx context.Context, ledger interfaces.LedgerStorage, event models.AccountEvent) error {
+	if err := ledger.AppendEvent(ctx, &event); err != nil {
+		return err
+	}
+
+	if event.Sequence%snapshotInterval != 0 {
+		return nil
+	}
+
+	balance, _, err := ReplayBalance(ctx, ledger, event.AccountID)
+	if err != nil {
+		return err
+	}
+
+	return ledger.SaveSnapshot(ctx, models.BalanceSnapshot{
+		AccountID: event.AccountID,
+		Sequence:  event.Sequence,
+		Balance:   balance,
+		Timestamp: event.Timestamp,
+	})
+}
+
+// ReplayBalance вычисляет баланс счета из журнала событий, начиная
+// с последнего снимка. Возвращает баланс и общее количество событий.
+func ReplayBalance(ctx context.Context, ledger interfaces.LedgerStorage, accountID string) (float64, int, error) {
+	snapshot, err := ledger.LoadSnapshot(ctx, accountID)
+	if err != nil && !stderrors.Is(err, errors.ErrSnapshotNotFound) {
+		return 0, 0, err
+	}
+
+	events, err := ledger.LoadEvents(ctx, accountID, snapshot.Sequence)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	balance := snapshot.Balance
+	for _, event := range events {
+		balance += event.Delta()
+	}
+
+	return balance, int(snapshot.Sequence) + len(events), nil
+}
+
+// GetBalanceAt вычисляет баланс счета на момент at по журналу событий.
+// Снимок используется, только если он сделан не позже at.
+func GetBalanceAt(ctx context.Context, ledger interfaces.LedgerStorage, accountID string, at time.Time) (float64, error) {
+	snapshot, err := ledger.LoadSnapshot(ctx, accountID)
+	if err != nil && !stderrors.Is(err, errors.ErrSnapshotNotFound) {
+		return 0, err
+	}
+
+	if snapshot.Timestamp.After(at) {
+		snapshot = models.BalanceSnapshot{}
+	}
+
+	events, err := ledger.LoadEvents(ctx, accountID, snapshot.Sequence)
+	if err != nil {
+		return 0, err
+	}
+
+	balance := snapshot.Balance
+	for _, event := range events {
+		if event.Timestamp.After(at) {
+			break
+		}
+		balance += event.Delta()
+	}
+
+	return balance, nil
+}
+
+// GetBalanceHistory возвращает балансы счета на конец каждого дня в
+// периоде [from, to) по журналу событий, например для построения графика
+func GetBalanceHistory(ctx context.Context, ledger interfaces.LedgerStorage, accountID string, from, to time.Time) ([]models.BalancePoint, error) {
+	events, err := ledger.LoadEvents(ctx, accountID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []models.BalancePoint
+	var balance float64
+	next := 0
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		for next < len(events) && events[next].Timestamp.Before(end) {
+			balance += events[next].Delta()
+			next++
+		}
+
+		history = append(history, models.BalancePoint{Date: day, Balance: balance})
+	}
+
+	return history, nil
+}
+
+// RebuildBalances пересобирает балансы всех счетов из журнала событий и
+// возвращает расхождения. В режиме dryRun счета не изменяются; иначе баланс
+// счета приводится к журналу, а расхождение истории транзакций с журналом
+// закрывается корректирующей транзакцией BALANCE_CORRECTION. Журнал считается
+// источником истины, поэтому событие для корректировки не записывается.
+func RebuildBalances(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage,
+	ids models.IDGenerator, now time.Time, dryRun bool) ([]models.RebuildResult, error) {
+	accounts, _, err := storage.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.RebuildResult, 0, len(accounts))
+	for _, account := range accounts {
+		balance, events, err := ReplayBalance(ctx, ledger, account.ID)
+		if err != nil {
+			return results, err
+		}
+
+		var history float64
+		for _, tx := range account.Transactions {
+			history += tx.SignedAmount()
+		}
+
+		result := models.RebuildResult{
+			AccountID:      account.ID,
+			StoredBalance:  account.Balance,
+			RebuiltBalance: balance,
+			Events:         events,
+		}
+		if !sameAmount(history, balance) {
+			result.Correction = balance - history
+		}
+		result.Mismatch = result.Correction != 0 || !sameAmount(account.Balance, balance)
+		results = append(results, result)
+
+		if dryRun || !result.Mismatch {
+			continue
+		}
+
+		if result.Correction != 0 {
+			account.Transactions = append(account.Transactions, balanceCorrection(ids, result.Correction, now))
+		}
+		account.Balance = balance
+
+		if err := storage.SaveAccount(ctx, account); err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// balanceCorrection создает транзакцию, сдвигающую сумму истории счета на difference
+func balanceCorrection(ids models.IDGenerator, difference float64, now time.Time) models.Transaction {
+	direction := models.CreditEntry
+	if difference < 0 {
+		direction = models.DebitEntry
+	}
+
+	return models.Transaction{
+		ID:        ids.NewID("TX"),
+		Type:      models.BalanceCorrectionTransaction,
+		Amount:    math.Abs(difference),
+		Timestamp: now,
+		Message:   fmt.Sprintf("Корректировка баланса по журналу событий на %.2f", difference),
+		Direction: direction,
+	}
+}
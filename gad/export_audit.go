@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"bankapp/config"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"bankapp/services"
+	"bankapp/storage"
+)
+
+// runExportAudit выполняет команду export-audit: выгружает журнал аудита
+// хранилища из конфигурации в JSON Lines или CEF для SIEM. Номер последней
+// выгруженной записи печатается в stderr и передается в -after при
+// следующей инкрементальной выгрузке.
+func runExportAudit(args []string) int {
+	flags := flag.NewFlagSet("export-audit", flag.ContinueOnError)
+	configPath := flags.String("config", os.Getenv("BANKAPP_CONFIG"), "путь к JSON-файлу конфигурации")
+	format := flags.String("format", string(models.JSONLinesFormat), "формат выгрузки: jsonl или cef")
+	after := flags.Uint64("after", 0, "выгрузить записи с номером больше указанного")
+	out := flags.String("out", "", "файл выгрузки (по умолчанию stdout)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка конфигурации: %v\n", err)
+		return 2
+	}
+
+	// Ключи уже проверены в config.Load
+	keys, _ := cfg.Storage.Keys()
+	store, err := storage.Open(cfg.Storage.URI(), storage.WithEncryptionKeys(keys...))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка хранилища: %v\n", err)
+		return 2
+	}
+	defer closeStorage(context.Background(), store)
+
+	journal, ok := store.(interfaces.JournalStorage)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Ошибка: хранилище не хранит журнал аудита между запусками")
+		return 2
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.OpenFile(*out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка при создании файла: %v\n", err)
+			return 2
+		}
+		defer file.Close()
+		w = file
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	audit := services.NewAuditLogger(journal.AuditLog(), models.DefaultClock)
+	last, err := services.ExportAuditLog(ctx, w, audit, models.ExportFormat(*format), *after)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка выгрузки: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "последняя запись: %d\n", last)
+	return 0
+}
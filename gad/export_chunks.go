@@ -0,0 +1,129 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// checkpointVersion версия формата точки продолжения выгрузки
+const checkpointVersion = "v1"
+
+// ExportChunk выгружает следующие limit транзакций счета в CSV или JSON Lines,
+// начиная с точки продолжения checkpoint (пустая - с начала, для CSV с
+// заголовком). Возвращает точку продолжения для следующего вызова: прерванную
+// выгрузку можно продолжить с последней полученной точки, не начиная заново.
+// История транзакций только дополняется, поэтому точка остается верной и
+// после новых операций по счету.
+func (s *AccountServiceImpl) ExportChunk(ctx context.Context, format models.ExportFormat, checkpoint string, limit int, w io.Writer) (models.ExportChunk, error) {
+	if err := ctx.Err(); err != nil {
+		return models.ExportChunk{}, err
+	}
+
+	if format != models.CSVFormat && format != models.JSONLinesFormat {
+		return models.ExportChunk{}, errors.ErrUnsupportedFormat
+	}
+
+	if limit <= 0 {
+		limit = len(s.account.Transactions)
+	}
+
+	start, err := s.parseCheckpoint(checkpoint)
+	if err != nil {
+		return models.ExportChunk{}, err
+	}
+
+	end := min(start+limit, len(s.account.Transactions))
+	transactions := s.account.Transactions[start:end]
+
+	switch format {
+	case models.CSVFormat:
+		err = s.writeCSVChunk(w, transactions, checkpoint == "")
+	case models.JSONLinesFormat:
+		err = writeJSONLinesChunk(w, transactions)
+	}
+	if err != nil {
+		return models.ExportChunk{}, err
+	}
+
+	chunk := models.ExportChunk{
+		Rows: len(transactions),
+		Done: end == len(s.account.Transactions),
+	}
+	chunk.Checkpoint = checkpoint
+	if end > 0 {
+		chunk.Checkpoint = s.checkpointAt(end)
+	}
+
+	return chunk, nil
+}
+
+// writeCSVChunk пишет транзакции в CSV, заголовок - только в начале выгрузки
+func (s *AccountServiceImpl) writeCSVChunk(w io.Writer, transactions []models.Transaction, header bool) error {
+	cw := csv.NewWriter(w)
+	if header {
+		if err := cw.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	for _, tx := range transactions {
+		if err := cw.Write(s.csvRecord(tx)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSONLinesChunk пишет транзакции по одному JSON-объекту в строке
+func writeJSONLinesChunk(w io.Writer, transactions []models.Transaction) error {
+	encoder := json.NewEncoder(w)
+	for _, tx := range transactions {
+		if err := encoder.Encode(toTransactionJSON(tx)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkpointAt точка продолжения после первых position транзакций: счет,
+// позиция и ID последней выгруженной транзакции для проверки
+func (s *AccountServiceImpl) checkpointAt(position int) string {
+	raw := fmt.Sprintf("%s:%s:%d:%s", checkpointVersion, s.account.ID, position, s.account.Transactions[position-1].ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// parseCheckpoint возвращает позицию, с которой продолжается выгрузка
+func (s *AccountServiceImpl) parseCheckpoint(checkpoint string) (int, error) {
+	if checkpoint == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(checkpoint)
+	if err != nil {
+		return 0, errors.ErrInvalidCheckpoint
+	}
+
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 || parts[0] != checkpointVersion || parts[1] != s.account.ID {
+		return 0, errors.ErrInvalidCheckpoint
+	}
+
+	position, err := strconv.Atoi(parts[2])
+	if err != nil || position <= 0 || position > len(s.account.Transactions) ||
+		s.account.Transactions[position-1].ID != parts[3] {
+		return 0, errors.ErrInvalidCheckpoint
+	}
+
+	return position, nil
+}
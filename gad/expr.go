@@ -0,0 +1,581 @@
+package expr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"bankapp/errors"
+)
+
+// Ограничения, защищающие от слишком сложных выражений в конфигурации
+const (
+	maxSourceLength = 1024
+	maxDepth        = 32
+)
+
+// Env значения переменных выражения: float64, string или bool
+type Env map[string]any
+
+// Expr скомпилированное выражение. Язык выражений не содержит циклов и
+// вызовов функций, поэтому вычисление всегда завершается за время,
+// пропорциональное размеру выражения.
+type Expr struct {
+	source string
+	root   node
+}
+
+// Compile разбирает выражение вида `amount > 1000 && account.status == "ACTIVE"`.
+// Поддерживаются числа, строки в двойных кавычках, true/false, переменные
+// с точками, скобки и операторы || && ! == != < <= > >= + - * /.
+func Compile(source string) (*Expr, error) {
+	if len(source) > maxSourceLength {
+		return nil, fmt.Errorf("%w: выражение длиннее %d символов", errors.ErrInvalidExpression, maxSourceLength)
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != endToken {
+		return nil, p.errorf(tok, "неожиданный %q", tok.text)
+	}
+
+	return &Expr{source: source, root: root}, nil
+}
+
+// String возвращает исходный текст выражения
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Variables возвращает имена переменных, используемых в выражении
+func (e *Expr) Variables() []string {
+	seen := make(map[string]bool)
+	e.root.variables(seen)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Eval вычисляет выражение
+func (e *Expr) Eval(env Env) (any, error) {
+	return e.root.eval(env)
+}
+
+// Bool вычисляет выражение, результат которого должен быть логическим
+func (e *Expr) Bool(env Env) (bool, error) {
+	value, err := e.Eval(env)
+	if err != nil {
+		return false, err
+	}
+
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: результат %q не логический", errors.ErrInvalidExpression, e.source)
+	}
+
+	return result, nil
+}
+
+// Number вычисляет выражение, результат которого должен быть числом
+func (e *Expr) Number(env Env) (float64, error) {
+	value, err := e.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+
+	result, ok := value.(float64)
+	if !ok {
+		return 0, fmt.Errorf("%w: результат %q не числовой", errors.ErrInvalidExpression, e.source)
+	}
+
+	return result, nil
+}
+
+// Лексический анализ
+
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	numberToken
+	stringToken
+	identToken
+	operatorToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators операторы, от длинных к коротким
+var operators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "(", ")"}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c >= '0' && c <= '9' || c == '.':
+			start := i
+			for i < len(source) && (source[i] >= '0' && source[i] <= '9' || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: numberToken, text: source[start:i], pos: start})
+		case c == '"':
+			start := i
+			i++
+			for i < len(source) && source[i] != '"' {
+				if source[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(source) {
+				return nil, fmt.Errorf("%w: позиция %d: незакрытая строка", errors.ErrInvalidExpression, start+1)
+			}
+			i++
+			tokens = append(tokens, token{kind: stringToken, text: source[start:i], pos: start})
+		case c == '_' || c < unicode.MaxASCII && unicode.IsLetter(c):
+			start := i
+			for i < len(source) && (source[i] == '_' || source[i] == '.' ||
+				source[i] < unicode.MaxASCII && (unicode.IsLetter(rune(source[i])) || unicode.IsDigit(rune(source[i])))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: identToken, text: source[start:i], pos: start})
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(source[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("%w: позиция %d: недопустимый символ %q", errors.ErrInvalidExpression, i+1, source[i])
+			}
+			tokens = append(tokens, token{kind: operatorToken, text: op, pos: i})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: endToken, pos: len(source)}), nil
+}
+
+// Синтаксический анализ: рекурсивный спуск по уровням приоритета
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != endToken {
+		p.pos++
+	}
+	return tok
+}
+
+// accept пропускает оператор, если он следующий
+func (p *parser) accept(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != operatorToken {
+		return "", false
+	}
+
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+
+	return "", false
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return fmt.Errorf("%w: позиция %d: %s", errors.ErrInvalidExpression, tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parseOr(depth int) (node, error) {
+	if depth > maxDepth {
+		return nil, p.errorf(p.peek(), "слишком глубокая вложенность")
+	}
+
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd(depth int) (node, error) {
+	left, err := p.parseComparison(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+
+		right, err := p.parseComparison(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) parseComparison(depth int) (node, error) {
+	left, err := p.parseSum(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+
+	right, err := p.parseSum(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &binaryNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseSum(depth int) (node, error) {
+	left, err := p.parseProduct(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseProduct(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseProduct(depth int) (node, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary(depth int) (node, error) {
+	if depth > maxDepth {
+		return nil, p.errorf(p.peek(), "слишком глубокая вложенность")
+	}
+
+	if op, ok := p.accept("!", "-"); ok {
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+
+	return p.parsePrimary(depth)
+}
+
+func (p *parser) parsePrimary(depth int) (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case numberToken:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "некорректное число %q", tok.text)
+		}
+		return &literalNode{value: value}, nil
+	case stringToken:
+		value, err := strconv.Unquote(tok.text)
+		if err != nil {
+			return nil, p.errorf(tok, "некорректная строка %s", tok.text)
+		}
+		return &literalNode{value: value}, nil
+	case identToken:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		}
+		return &variableNode{name: tok.text}, nil
+	case operatorToken:
+		if tok.text == "(" {
+			inner, err := p.parseOr(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, p.errorf(p.peek(), "ожидается )")
+			}
+			return inner, nil
+		}
+	case endToken:
+		return nil, p.errorf(tok, "неожиданный конец выражения")
+	}
+
+	return nil, p.errorf(tok, "неожиданный %q", tok.text)
+}
+
+// Вычисление
+
+type node interface {
+	eval(env Env) (any, error)
+	variables(seen map[string]bool)
+}
+
+type literalNode struct {
+	value any
+}
+
+func (n *literalNode) eval(Env) (any, error) {
+	return n.value, nil
+}
+
+func (n *literalNode) variables(map[string]bool) {}
+
+type variableNode struct {
+	name string
+}
+
+func (n *variableNode) eval(env Env) (any, error) {
+	value, ok := env[n.name]
+	if !ok {
+		return nil, fmt.Errorf("%w: неизвестная переменная %s", errors.ErrInvalidExpression, n.name)
+	}
+
+	if number, ok := value.(int); ok {
+		return float64(number), nil
+	}
+
+	return value, nil
+}
+
+func (n *variableNode) variables(seen map[string]bool) {
+	seen[n.name] = true
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(env Env) (any, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		if n.op == "!" {
+			return !v, nil
+		}
+	case float64:
+		if n.op == "-" {
+			return -v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: оператор %s неприменим к %v", errors.ErrInvalidExpression, n.op, value)
+}
+
+func (n *unaryNode) variables(seen map[string]bool) {
+	n.operand.variables(seen)
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(env Env) (any, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// Логические операторы вычисляются сокращенно
+	if n.op == "&&" || n.op == "||" {
+		l, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: оператор %s требует логических значений", errors.ErrInvalidExpression, n.op)
+		}
+		if (n.op == "&&" && !l) || (n.op == "||" && l) {
+			return l, nil
+		}
+
+		right, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: оператор %s требует логических значений", errors.ErrInvalidExpression, n.op)
+		}
+		return r, nil
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		eq, err := equal(left, right)
+		if err != nil {
+			return nil, err
+		}
+		return !eq, nil
+	}
+
+	if l, ok := left.(string); ok {
+		r, ok := right.(string)
+		if !ok {
+			return nil, n.mismatch(left, right)
+		}
+		return compareStrings(n.op, l, r)
+	}
+
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return nil, n.mismatch(left, right)
+	}
+
+	switch n.op {
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, fmt.Errorf("%w: деление на ноль", errors.ErrInvalidExpression)
+		}
+		return l / r, nil
+	}
+
+	return nil, n.mismatch(left, right)
+}
+
+func (n *binaryNode) variables(seen map[string]bool) {
+	n.left.variables(seen)
+	n.right.variables(seen)
+}
+
+func (n *binaryNode) mismatch(left, right any) error {
+	return fmt.Errorf("%w: оператор %s неприменим к %v и %v", errors.ErrInvalidExpression, n.op, left, right)
+}
+
+// equal сравнивает значения одного типа
+func equal(left, right any) (bool, error) {
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return l == r, nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return l == r, nil
+		}
+	case bool:
+		if r, ok := right.(bool); ok {
+			return l == r, nil
+		}
+	}
+
+	return false, fmt.Errorf("%w: нельзя сравнить %v и %v", errors.ErrInvalidExpression, left, right)
+}
+
+// compareStrings упорядочивает строки; сложение строк объединяет их
+func compareStrings(op, l, r string) (any, error) {
+	switch op {
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "+":
+		return l + r, nil
+	}
+
+	return nil, fmt.Errorf("%w: оператор %s неприменим к строкам", errors.ErrInvalidExpression, op)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+)
+
+// ConfigFeatureFlags флаги функциональности из конфигурации
+type ConfigFeatureFlags struct {
+	flags map[string]models.FeatureFlag
+}
+
+// NewFeatureFlags создает флаги функциональности. Неизвестный флаг выключен.
+func NewFeatureFlags(flags map[string]models.FeatureFlag) interfaces.FeatureFlags {
+	return &ConfigFeatureFlags{flags: flags}
+}
+
+// Enabled сообщает, включен ли флаг для счета. Счет попадает в долю Percent
+// детерминированно по хэшу имени флага и ID счета, поэтому при увеличении
+// доли уже включенные счета остаются включенными.
+func (f *ConfigFeatureFlags) Enabled(ctx context.Context, flag string, account *models.Account) bool {
+	setting, ok := f.flags[flag]
+	if !ok || !setting.Enabled || account == nil {
+		return false
+	}
+
+	if slices.Contains(setting.Accounts, account.ID) || slices.Contains(setting.Owners, account.OwnerID) {
+		return true
+	}
+
+	return rolloutBucket(flag, account.ID) < setting.Percent
+}
+
+// rolloutBucket номер корзины счета от 0 до 99 для постепенного включения флага
+func rolloutBucket(flag, accountID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(flag + ":" + accountID))
+	return int(h.Sum32() % 100)
+}
+
+// ValidateFeatureFlags проверяет доли включения флагов
+func ValidateFeatureFlags(flags map[string]models.FeatureFlag) error {
+	for name, flag := range flags {
+		if flag.Percent < 0 || flag.Percent > 100 {
+			return fmt.Errorf("%w: %s: percent %d", errors.ErrInvalidFeatureFlag, name, flag.Percent)
+		}
+	}
+	return nil
+}
+
+// FlaggedFeePolicy политика комиссий, переключаемая флагом: счета, для
+// которых флаг включен, обслуживаются новой политикой, остальные - прежней
+type FlaggedFeePolicy struct {
+	flags    interfaces.FeatureFlags
+	flag     string
+	enabled  interfaces.FeePolicy
+	fallback interfaces.FeePolicy
+}
+
+// NewFlaggedFeePolicy создает политику комиссий, переключаемую флагом flag
+func NewFlaggedFeePolicy(flags interfaces.FeatureFlags, flag string, enabled, fallback interfaces.FeePolicy) interfaces.FeePolicy {
+	return &FlaggedFeePolicy{
+		flags:    flags,
+		flag:     flag,
+		enabled:  enabled,
+		fallback: fallback,
+	}
+}
+
+// CalculateFee рассчитывает комиссию по политике, выбранной для счета
+func (p *FlaggedFeePolicy) CalculateFee(ctx context.Context, account *models.Account, txType models.TransactionType, amount float64) (float64, error) {
+	if p.flags.Enabled(ctx, p.flag, account) {
+		return p.enabled.CalculateFee(ctx, account, txType, amount)
+	}
+	return p.fallback.CalculateFee(ctx, account, txType, amount)
+}
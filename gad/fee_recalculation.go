@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// RecalculateFees пересчитывает по текущей политике комиссии, списанные за
+// период [from, to), и проводит корректирующие транзакции FEE_CORRECTION со
+// ссылкой на исходную комиссию. Ранее проведенные корректировки учитываются,
+// поэтому повторный запуск не дублирует возвраты. Сторнированные комиссии
+// пропускаются. В режиме dryRun счета не изменяются.
+func RecalculateFees(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage,
+	policy interfaces.FeePolicy, ids models.IDGenerator, clock models.Clock, from, to time.Time, dryRun bool) (models.FeeRecalculationReport, error) {
+	report := models.FeeRecalculationReport{
+		From:   from,
+		To:     to,
+		DryRun: dryRun,
+	}
+
+	accounts, _, err := storage.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return report, err
+	}
+
+	for _, account := range accounts {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		results, err := recalculateAccountFees(ctx, storage, ledger, policy, ids, account, from, to, clock.Now(), dryRun)
+		if err != nil {
+			return report, err
+		}
+
+		affected := false
+		for _, result := range results {
+			report.Results = append(report.Results, result)
+			if result.SkipReason != "" {
+				continue
+			}
+
+			affected = true
+			if difference := result.Difference(); difference > 0 {
+				report.Refunded += difference
+			} else {
+				report.Collected -= difference
+			}
+		}
+
+		if affected {
+			report.Accounts++
+		}
+	}
+
+	return report, nil
+}
+
+// recalculateAccountFees пересчитывает комиссии одного счета и возвращает
+// результаты только по комиссиям, сумма которых расходится с ожидаемой
+func recalculateAccountFees(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage,
+	policy interfaces.FeePolicy, ids models.IDGenerator, account *models.Account, from, to, now time.Time, dryRun bool) ([]models.FeeRecalculationResult, error) {
+	transactions := account.Transactions
+
+	operations := make(map[string]models.Transaction, len(transactions))
+	corrected := make(map[string]float64)
+	for _, tx := range transactions {
+		operations[tx.ID] = tx
+		if tx.Type == models.FeeCorrectionTransaction {
+			corrected[tx.RelatedID] += tx.SignedAmount()
+		}
+	}
+
+	var results []models.FeeRecalculationResult
+	var events []models.AccountEvent
+	var balance float64
+	for _, tx := range transactions {
+		balanceBefore := balance
+		balance += tx.SignedAmount()
+
+		if tx.Type != models.FeeTransaction || tx.ReversedBy != "" ||
+			tx.Timestamp.Before(from) || !tx.Timestamp.Before(to) {
+			continue
+		}
+
+		// Комиссия без связанной операции - плата за обслуживание от баланса на момент списания
+		ruleType, amount := models.MaintenanceFee, balanceBefore
+		if operation, ok := operations[tx.RelatedID]; ok {
+			ruleType, amount = operation.Type, operation.Amount
+		}
+
+		expected, err := policy.CalculateFee(ctx, account, ruleType, amount)
+		if err != nil {
+			return nil, err
+		}
+
+		result := models.FeeRecalculationResult{
+			AccountID:   account.ID,
+			OwnerName:   account.OwnerName,
+			FeeID:       tx.ID,
+			OperationID: tx.RelatedID,
+			Charged:     math.Round((tx.Amount-corrected[tx.ID])*100) / 100,
+			Expected:    expected,
+		}
+
+		difference := result.Difference()
+		switch {
+		case math.Abs(difference) < 0.005:
+			continue
+		case account.Status == models.ClosedStatus:
+			result.SkipReason = "счет закрыт"
+		case difference < 0 && -difference > account.AvailableFundsAt(now):
+			result.SkipReason = "недостаточно средств для доначисления"
+		case !dryRun:
+			event := postFeeCorrection(ids, account, tx.ID, difference, now)
+			events = append(events, event)
+			result.CorrectionID = event.TransactionID
+		}
+
+		results = append(results, result)
+	}
+
+	if dryRun || len(account.Transactions) == len(transactions) {
+		return results, nil
+	}
+
+	if err := storage.SaveAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return results, recordEvents(ctx, ledger, events)
+}
+
+// postFeeCorrection проводит на момент now корректировку комиссии feeID:
+// положительная difference возвращается клиенту, отрицательная доначисляется.
+// Событие журнала возвращается для записи после сохранения счета.
+func postFeeCorrection(ids models.IDGenerator, account *models.Account, feeID string, difference float64, now time.Time) models.AccountEvent {
+	transaction := models.Transaction{
+		ID:        ids.NewID("TX"),
+		Type:      models.FeeCorrectionTransaction,
+		Amount:    math.Abs(difference),
+		Timestamp: now,
+		Message:   fmt.Sprintf("Корректировка комиссии %s после пересчета", feeID),
+		Direction: models.CreditEntry,
+		RelatedID: feeID,
+	}
+	if difference < 0 {
+		transaction.Direction = models.DebitEntry
+	}
+
+	for i := range account.Transactions {
+		if fee := &account.Transactions[i]; fee.ID == feeID {
+			fee.CorrectedBy = append(fee.CorrectedBy, transaction.ID)
+		}
+	}
+
+	account.Balance += transaction.SignedAmount()
+	account.Transactions = append(account.Transactions, transaction)
+
+	return newEvent(account.ID, models.AdjustmentEvent, transaction.SignedAmount(), transaction.ID, transaction.Timestamp)
+}
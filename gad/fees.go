@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// DefaultFeeRules набор правил комиссий по умолчанию: фиксированная
+// комиссия за перевод, процент за крупные снятия и ежемесячная плата за обслуживание
+var DefaultFeeRules = []models.FeeRule{
+	{Type: models.TransferTransaction, Fixed: 10},
+	{Type: models.WithdrawTransaction, Percent: 1, Threshold: 50000},
+	{Type: models.MaintenanceFee, Fixed: 50},
+}
+
+// RuleFeePolicy политика комиссий на основе набора правил
+type RuleFeePolicy struct {
+	rules      []models.FeeRule
+	conditions []conditionRule
+	clock      models.Clock
+}
+
+// NewRuleFeePolicy создает политику комиссий из набора правил. Условия
+// правил проверяются на текущее время clock.
+func NewRuleFeePolicy(rules []models.FeeRule, clock models.Clock) interfaces.FeePolicy {
+	policy := &RuleFeePolicy{
+		rules: append([]models.FeeRule(nil), rules...),
+		clock: clock,
+	}
+
+	for _, rule := range rules {
+		policy.conditions = append(policy.conditions, compileConditionRule(string(rule.Type), rule.When))
+	}
+
+	return policy
+}
+
+// LoadFeeRules читает набор правил комиссий из JSON-конфигурации
+func LoadFeeRules(r io.Reader) ([]models.FeeRule, error) {
+	var rules []models.FeeRule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateFeeRules(rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// ValidateFeeRules проверяет суммы и условия правил комиссий
+func ValidateFeeRules(rules []models.FeeRule) error {
+	for _, rule := range rules {
+		if rule.Fixed < 0 || rule.Percent < 0 || rule.Threshold < 0 {
+			return errors.ErrInvalidAmount
+		}
+
+		if rule.When == "" {
+			continue
+		}
+
+		if _, err := CompileCondition(rule.When); err != nil {
+			return fmt.Errorf("правило %s: %w", rule.Type, err)
+		}
+	}
+
+	return nil
+}
+
+// CalculateFee суммирует комиссии всех правил для типа операции
+func (p *RuleFeePolicy) CalculateFee(ctx context.Context, account *models.Account, txType models.TransactionType, amount float64) (float64, error) {
+	var fee float64
+	for i, rule := range p.rules {
+		if rule.Type != txType {
+			continue
+		}
+
+		applies, err := p.conditions[i].matches(p.clock.Now(), account, txType, amount, "")
+		if err != nil {
+			return 0, err
+		}
+		if !applies {
+			continue
+		}
+
+		fee += rule.Fixed
+		if rule.Percent > 0 && amount > rule.Threshold {
+			fee += amount * rule.Percent / 100
+		}
+	}
+
+	return math.Round(fee*100) / 100, nil
+}
+
+// WithFeePolicy подключает политику комиссий к операциям счета
+func WithFeePolicy(policy interfaces.FeePolicy) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.feePolicy = policy
+	}
+}
+
+// calculateFee рассчитывает комиссию за операцию; без политики комиссия нулевая
+func (s *AccountServiceImpl) calculateFee(ctx context.Context, txType models.TransactionType, amount float64) (float64, error) {
+	if s.feePolicy == nil {
+		return 0, nil
+	}
+
+	return s.feePolicy.CalculateFee(ctx, s.account, txType, amount)
+}
+
+// chargeFee списывает комиссию за операцию relatedID
+func (s *AccountServiceImpl) chargeFee(fee float64, relatedID string) {
+	s.applyFee(fee, relatedID, fmt.Sprintf("Комиссия %.2f за операцию %s", fee, relatedID))
+}
+
+// applyFee списывает комиссию со счета отдельной транзакцией FEE.
+// relatedID пустой для комиссий, не связанных с операцией.
+func (s *AccountServiceImpl) applyFee(fee float64, relatedID, message string) {
+	if fee <= 0 {
+		return
+	}
+
+	transaction := models.Transaction{
+		ID:        s.newID("TX"),
+		Type:      models.FeeTransaction,
+		Amount:    fee,
+		Timestamp: s.now(),
+		Message:   message,
+		Direction: models.DebitEntry,
+		RelatedID: relatedID,
+	}
+
+	s.queueEvent(s.account.ID, models.FeeEvent, fee, transaction.ID, transaction.Timestamp)
+	s.account.Balance -= fee
+	s.account.Transactions = append(s.account.Transactions, transaction)
+}
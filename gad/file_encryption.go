@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+
+	"bankapp/errors"
+)
+
+// encryptedMagic заголовок зашифрованного файла хранилища; за ним следуют
+// идентификатор ключа, nonce и шифртекст AES-256-GCM
+var encryptedMagic = []byte("BANKAPP-AESGCM-1")
+
+// keyIDSize длина идентификатора ключа - префикса SHA-256 от ключа
+const keyIDSize = 8
+
+// keyID вычисляет идентификатор ключа, по которому при чтении выбирается ключ
+func keyID(key []byte) []byte {
+	sum := sha256.Sum256(key)
+	return sum[:keyIDSize]
+}
+
+// encryptSnapshot шифрует данные ключом key. Заголовок входит в
+// аутентифицируемые данные, поэтому подмена идентификатора ключа
+// обнаруживается так же, как изменение шифртекста.
+func encryptSnapshot(key, plain []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := append(append([]byte(nil), encryptedMagic...), keyID(key)...)
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append(header, nonce...)
+	return gcm.Seal(out, nonce, plain, header), nil
+}
+
+// decryptSnapshot расшифровывает данные подходящим ключом из keys и
+// возвращает индекс использованного ключа. Незашифрованные данные
+// возвращаются как есть с индексом -1, только если ключей нет или явно
+// разрешена миграция allowPlaintext: иначе подмена файла незашифрованным
+// прошла бы незамеченной.
+func decryptSnapshot(keys [][]byte, data []byte, allowPlaintext bool) ([]byte, int, error) {
+	if !bytes.HasPrefix(data, encryptedMagic) {
+		if len(keys) > 0 && !allowPlaintext {
+			return nil, 0, errors.ErrStoragePlaintext
+		}
+		return data, -1, nil
+	}
+
+	headerSize := len(encryptedMagic) + keyIDSize
+	if len(data) < headerSize {
+		return nil, 0, errors.ErrStorageTampered
+	}
+
+	header, id := data[:headerSize], data[len(encryptedMagic):headerSize]
+	for i, key := range keys {
+		if !bytes.Equal(keyID(key), id) {
+			continue
+		}
+
+		gcm, err := newGCM(key)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		rest := data[headerSize:]
+		if len(rest) < gcm.NonceSize() {
+			return nil, 0, errors.ErrStorageTampered
+		}
+
+		plain, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], header)
+		if err != nil {
+			return nil, 0, errors.ErrStorageTampered
+		}
+
+		return plain, i, nil
+	}
+
+	return nil, 0, errors.ErrStorageKeyMissing
+}
+
+// newGCM создает AES-256-GCM для 32-байтового ключа
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, errors.ErrInvalidStorageKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"context"
+
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// Ledger возвращает журнал событий, хранящийся в файле вместе со счетами
+func (s *FileStorage) Ledger() interfaces.LedgerStorage {
+	return fileLedgerStorage{file: s}
+}
+
+// Aliases возвращает индекс псевдонимов, хранящийся в файле вместе со счетами
+func (s *FileStorage) Aliases() interfaces.AliasStorage {
+	return fileAliasStorage{file: s}
+}
+
+// AuditLog возвращает журнал аудита, хранящийся в файле вместе со счетами
+func (s *FileStorage) AuditLog() interfaces.AuditStorage {
+	return fileAuditStorage{file: s}
+}
+
+// fileLedgerStorage журнал событий FileStorage. Каждое изменение
+// записывает файл; если запись не удалась, изменение отменяется.
+type fileLedgerStorage struct {
+	file *FileStorage
+}
+
+// AppendEvent добавляет событие в журнал счета и записывает файл
+func (l fileLedgerStorage) AppendEvent(ctx context.Context, event *models.AccountEvent) error {
+	l.file.mu.Lock()
+	defer l.file.mu.Unlock()
+
+	previous, existed := l.file.ledger.events[event.AccountID]
+	if err := l.file.ledger.AppendEvent(ctx, event); err != nil {
+		return err
+	}
+
+	if err := l.file.persistLocked(); err != nil {
+		if existed {
+			l.file.ledger.events[event.AccountID] = previous
+		} else {
+			delete(l.file.ledger.events, event.AccountID)
+		}
+		event.Sequence = 0
+		return err
+	}
+
+	return nil
+}
+
+// LoadEvents возвращает события счета с номером больше afterSequence
+func (l fileLedgerStorage) LoadEvents(ctx context.Context, accountID string, afterSequence uint64) ([]models.AccountEvent, error) {
+	l.file.mu.Lock()
+	defer l.file.mu.Unlock()
+
+	return l.file.ledger.LoadEvents(ctx, accountID, afterSequence)
+}
+
+// SaveSnapshot сохраняет снимок баланса счета и записывает файл
+func (l fileLedgerStorage) SaveSnapshot(ctx context.Context, snapshot models.BalanceSnapshot) error {
+	l.file.mu.Lock()
+	defer l.file.mu.Unlock()
+
+	previous, existed := l.file.ledger.snapshots[snapshot.AccountID]
+	if err := l.file.ledger.SaveSnapshot(ctx, snapshot); err != nil {
+		return err
+	}
+
+	if err := l.file.persistLocked(); err != nil {
+		if existed {
+			l.file.ledger.snapshots[snapshot.AccountID] = previous
+		} else {
+			delete(l.file.ledger.snapshots, snapshot.AccountID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// LoadSnapshot возвращает последний снимок баланса счета
+func (l fileLedgerStorage) LoadSnapshot(ctx context.Context, accountID string) (models.BalanceSnapshot, error) {
+	l.file.mu.Lock()
+	defer l.file.mu.Unlock()
+
+	return l.file.ledger.LoadSnapshot(ctx, accountID)
+}
+
+// fileAliasStorage индекс псевдонимов FileStorage. Каждое изменение
+// записывает файл; если запись не удалась, изменение отменяется.
+type fileAliasStorage struct {
+	file *FileStorage
+}
+
+// SaveAlias сохраняет псевдоним и записывает файл
+func (a fileAliasStorage) SaveAlias(ctx context.Context, alias models.AccountAlias) error {
+	a.file.mu.Lock()
+	defer a.file.mu.Unlock()
+
+	previous, existed := a.file.aliases.aliases[alias.Alias]
+	if err := a.file.aliases.SaveAlias(ctx, alias); err != nil {
+		return err
+	}
+
+	if err := a.file.persistLocked(); err != nil {
+		if existed {
+			a.file.aliases.aliases[alias.Alias] = previous
+		} else {
+			delete(a.file.aliases.aliases, alias.Alias)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAlias удаляет псевдоним и записывает файл
+func (a fileAliasStorage) DeleteAlias(ctx context.Context, alias string) error {
+	a.file.mu.Lock()
+	defer a.file.mu.Unlock()
+
+	previous := a.file.aliases.aliases[alias]
+	if err := a.file.aliases.DeleteAlias(ctx, alias); err != nil {
+		return err
+	}
+
+	if err := a.file.persistLocked(); err != nil {
+		a.file.aliases.aliases[alias] = previous
+		return err
+	}
+
+	return nil
+}
+
+// LoadAlias загружает псевдоним
+func (a fileAliasStorage) LoadAlias(ctx context.Context, alias string) (models.AccountAlias, error) {
+	a.file.mu.Lock()
+	defer a.file.mu.Unlock()
+
+	return a.file.aliases.LoadAlias(ctx, alias)
+}
+
+// ListAliases возвращает псевдонимы счета в алфавитном порядке
+func (a fileAliasStorage) ListAliases(ctx context.Context, accountID string) ([]models.AccountAlias, error) {
+	a.file.mu.Lock()
+	defer a.file.mu.Unlock()
+
+	return a.file.aliases.ListAliases(ctx, accountID)
+}
+
+// AppendAliasChange добавляет запись в историю изменений псевдонимов и записывает файл
+func (a fileAliasStorage) AppendAliasChange(ctx context.Context, change models.AliasChange) error {
+	a.file.mu.Lock()
+	defer a.file.mu.Unlock()
+
+	previous := a.file.aliases.changes
+	if err := a.file.aliases.AppendAliasChange(ctx, change); err != nil {
+		return err
+	}
+
+	if err := a.file.persistLocked(); err != nil {
+		a.file.aliases.changes = previous
+		return err
+	}
+
+	return nil
+}
+
+// ListAliasChanges возвращает историю изменений псевдонимов счета в порядке записи
+func (a fileAliasStorage) ListAliasChanges(ctx context.Context, accountID string) ([]models.AliasChange, error) {
+	a.file.mu.Lock()
+	defer a.file.mu.Unlock()
+
+	return a.file.aliases.ListAliasChanges(ctx, accountID)
+}
+
+// fileAuditStorage журнал аудита FileStorage. Каждая запись сохраняется
+// в файл; если запись файла не удалась, она не попадает в журнал.
+type fileAuditStorage struct {
+	file *FileStorage
+}
+
+// AppendAuditEntry добавляет запись в журнал и записывает файл
+func (a fileAuditStorage) AppendAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	a.file.mu.Lock()
+	defer a.file.mu.Unlock()
+
+	previous := a.file.audit.entries
+	if err := a.file.audit.AppendAuditEntry(ctx, entry); err != nil {
+		return err
+	}
+
+	if err := a.file.persistLocked(); err != nil {
+		a.file.audit.entries = previous
+		entry.Sequence = 0
+		return err
+	}
+
+	return nil
+}
+
+// QueryAuditEntries возвращает страницу подходящих записей и их общее количество
+func (a fileAuditStorage) QueryAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, int, error) {
+	a.file.mu.Lock()
+	defer a.file.mu.Unlock()
+
+	return a.file.audit.QueryAuditEntries(ctx, filter)
+}
@@ -0,0 +1,349 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// FileStorage встроенное хранилище в одном файле для развертываний без
+// внешней БД. Данные держатся в памяти и после каждого изменения целиком
+// записываются во временный файл, который сбрасывается на диск и атомарно
+// переименовывается поверх основного: после сбоя в файле остается либо
+// прежнее, либо новое состояние. SaveAccounts сохраняет несколько счетов
+// одной записью, поэтому обе ноги перевода попадают на диск вместе.
+// В том же файле хранятся журнал событий, псевдонимы и журнал аудита
+// (см. Ledger, Aliases и AuditLog), чтобы после перезапуска балансы
+// сходились с журналом. С ключами шифрования файл записывается
+// зашифрованным AES-256-GCM.
+type FileStorage struct {
+	path string
+	keys [][]byte
+
+	mu      sync.Mutex
+	memory  *MemoryStorage
+	ledger  *MemoryLedgerStorage
+	aliases *MemoryAliasStorage
+	audit   *MemoryAuditStorage
+}
+
+// fileSnapshot содержимое файла хранилища
+type fileSnapshot struct {
+	Accounts     []*models.Account        `json:"accounts"`
+	Users        []*models.User           `json:"users"`
+	Events       []models.AccountEvent    `json:"events,omitempty"`
+	Snapshots    []models.BalanceSnapshot `json:"balance_snapshots,omitempty"`
+	Aliases      []models.AccountAlias    `json:"aliases,omitempty"`
+	AliasChanges []models.AliasChange     `json:"alias_changes,omitempty"`
+	Audit        []models.AuditEntry      `json:"audit,omitempty"`
+}
+
+// OpenFileStorage открывает хранилище в файле path, создавая его при первой
+// записи. Если заданы ключи, файл шифруется первым из них, остальные нужны
+// только для чтения файла, зашифрованного до ротации: такой файл сразу
+// перезаписывается текущим ключом. Незашифрованный файл при заданных ключах
+// не открывается (ErrStoragePlaintext); для перехода на шифрование он
+// открывается через Open с WithPlaintextMigration.
+func OpenFileStorage(path string, keys ...[]byte) (*FileStorage, error) {
+	return openFileStorage(path, keys, false)
+}
+
+// openFileStorage открывает хранилище; migratePlaintext разрешает прочитать
+// незашифрованный файл и перезаписать его зашифрованным
+func openFileStorage(path string, keys [][]byte, migratePlaintext bool) (*FileStorage, error) {
+	for _, key := range keys {
+		if len(key) != 32 {
+			return nil, errors.ErrInvalidStorageKey
+		}
+	}
+
+	s := &FileStorage{
+		path: path,
+		keys: keys,
+		memory: &MemoryStorage{
+			accounts: make(map[string]*models.Account),
+			users:    make(map[string]*models.User),
+		},
+		ledger: &MemoryLedgerStorage{
+			events:    make(map[string][]models.AccountEvent),
+			snapshots: make(map[string]models.BalanceSnapshot),
+		},
+		aliases: &MemoryAliasStorage{
+			aliases: make(map[string]models.AccountAlias),
+		},
+		audit: &MemoryAuditStorage{},
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, used, err := decryptSnapshot(keys, data, migratePlaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot fileSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	for _, account := range snapshot.Accounts {
+		s.memory.accounts[account.ID] = account
+	}
+	for _, user := range snapshot.Users {
+		s.memory.users[user.ID] = user
+	}
+	for _, event := range snapshot.Events {
+		s.ledger.events[event.AccountID] = append(s.ledger.events[event.AccountID], event)
+	}
+	for _, balance := range snapshot.Snapshots {
+		s.ledger.snapshots[balance.AccountID] = balance
+	}
+	for _, alias := range snapshot.Aliases {
+		s.aliases.aliases[alias.Alias] = alias
+	}
+	s.aliases.changes = snapshot.AliasChanges
+	s.audit.entries = snapshot.Audit
+
+	if len(keys) > 0 && used != 0 {
+		if err := s.persistLocked(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// openFileDriver драйвер для DSN вида file:///path/to/bank.json
+func openFileDriver(dsn string, options OpenOptions) (interfaces.Storage, error) {
+	path := strings.TrimPrefix(dsn, "file://")
+	if path == "" {
+		return nil, errors.ErrInvalidDSN
+	}
+
+	return openFileStorage(path, options.EncryptionKeys, options.MigratePlaintext)
+}
+
+// SaveAccount сохраняет счет и записывает файл
+func (s *FileStorage) SaveAccount(ctx context.Context, account *models.Account) error {
+	return s.SaveAccounts(ctx, account)
+}
+
+// SaveAccounts сохраняет счета одной записью файла: если версия хотя бы
+// одного счета устарела или запись не удалась, не сохраняется ни один
+func (s *FileStorage) SaveAccounts(ctx context.Context, accounts ...*models.Account) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := make(map[string]*models.Account, len(accounts))
+	for _, account := range accounts {
+		stored, exists := s.memory.accounts[account.ID]
+		if exists && stored.Version != account.Version {
+			return errors.ErrConcurrentModification
+		}
+		previous[account.ID] = stored
+	}
+
+	// В памяти хранятся копии: при неудачной записи файла прежние копии
+	// возвращаются на место, а счета вызывающего остаются с прежней версией
+	for _, account := range accounts {
+		account.SealHistory()
+		stored := account.Clone()
+		stored.Version++
+		s.memory.accounts[account.ID] = stored
+	}
+
+	if err := s.persistLocked(); err != nil {
+		for _, account := range accounts {
+			if stored := previous[account.ID]; stored != nil {
+				s.memory.accounts[account.ID] = stored
+			} else {
+				delete(s.memory.accounts, account.ID)
+			}
+		}
+		return err
+	}
+
+	for _, account := range accounts {
+		account.Version++
+	}
+
+	return nil
+}
+
+// LoadAccount загружает счет по ID
+func (s *FileStorage) LoadAccount(ctx context.Context, accountID string) (*models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.memory.LoadAccount(ctx, accountID)
+}
+
+// GetAllAccounts возвращает все счета
+func (s *FileStorage) GetAllAccounts(ctx context.Context) ([]*models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.memory.GetAllAccounts(ctx)
+}
+
+// ListAccounts возвращает страницу счетов и их общее количество
+func (s *FileStorage) ListAccounts(ctx context.Context, offset, limit int) ([]*models.Account, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.memory.ListAccounts(ctx, offset, limit)
+}
+
+// ListAccountsByScope возвращает страницу счетов из выборки scope и их количество
+func (s *FileStorage) ListAccountsByScope(ctx context.Context, scope models.AccountScope, offset, limit int) ([]*models.Account, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.memory.ListAccountsByScope(ctx, scope, offset, limit)
+}
+
+// SaveUser сохраняет пользователя и записывает файл
+func (s *FileStorage) SaveUser(ctx context.Context, user *models.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.memory.users[user.ID]
+	s.memory.users[user.ID] = user.Clone()
+
+	if err := s.persistLocked(); err != nil {
+		if existed {
+			s.memory.users[user.ID] = previous
+		} else {
+			delete(s.memory.users, user.ID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// LoadUser загружает пользователя по ID
+func (s *FileStorage) LoadUser(ctx context.Context, userID string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.memory.LoadUser(ctx, userID)
+}
+
+// FindUserByUsername ищет пользователя по имени
+func (s *FileStorage) FindUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.memory.FindUserByUsername(ctx, username)
+}
+
+// GetAllUsers возвращает всех пользователей
+func (s *FileStorage) GetAllUsers(ctx context.Context) ([]*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.memory.GetAllUsers(ctx)
+}
+
+// persistLocked записывает состояние во временный файл рядом с основным,
+// сбрасывает его на диск и переименовывает поверх основного. Вызывается под s.mu.
+func (s *FileStorage) persistLocked() error {
+	var snapshot fileSnapshot
+	for _, account := range s.memory.accounts {
+		snapshot.Accounts = append(snapshot.Accounts, account)
+	}
+	for _, user := range s.memory.users {
+		snapshot.Users = append(snapshot.Users, user)
+	}
+	sort.Slice(snapshot.Accounts, func(i, j int) bool { return snapshot.Accounts[i].ID < snapshot.Accounts[j].ID })
+	sort.Slice(snapshot.Users, func(i, j int) bool { return snapshot.Users[i].ID < snapshot.Users[j].ID })
+
+	for _, events := range s.ledger.events {
+		snapshot.Events = append(snapshot.Events, events...)
+	}
+	for _, balance := range s.ledger.snapshots {
+		snapshot.Snapshots = append(snapshot.Snapshots, balance)
+	}
+	for _, alias := range s.aliases.aliases {
+		snapshot.Aliases = append(snapshot.Aliases, alias)
+	}
+	// События счета идут подряд по возрастанию номера: при чтении они
+	// добавляются в журнал в том же порядке
+	sort.Slice(snapshot.Events, func(i, j int) bool {
+		a, b := snapshot.Events[i], snapshot.Events[j]
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		return a.Sequence < b.Sequence
+	})
+	sort.Slice(snapshot.Snapshots, func(i, j int) bool { return snapshot.Snapshots[i].AccountID < snapshot.Snapshots[j].AccountID })
+	sort.Slice(snapshot.Aliases, func(i, j int) bool { return snapshot.Aliases[i].Alias < snapshot.Aliases[j].Alias })
+	snapshot.AliasChanges = s.aliases.changes
+	snapshot.Audit = s.audit.entries
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if len(s.keys) > 0 {
+		if data, err = encryptSnapshot(s.keys[0], data); err != nil {
+			return err
+		}
+	}
+
+	dir := filepath.Dir(s.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+
+	// Переименование становится надежным только после сброса каталога
+	dirFile, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer dirFile.Close()
+
+	return dirFile.Sync()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"bankapp/models"
+	"bankapp/services"
+	"bankapp/sim"
+)
+
+// runFuzz выполняет команду fuzz: случайные прогоны операций с проверкой
+// инвариантов. Каждый следующий прогон использует зерно на единицу больше.
+// Код выхода 1 - найдено нарушение, оно воспроизводится с напечатанным -seed.
+func runFuzz(args []string) int {
+	defaults := sim.DefaultOptions(0)
+	flags := flag.NewFlagSet("fuzz", flag.ContinueOnError)
+	seed := flags.Int64("seed", 0, "зерно первого прогона (0 - от текущего времени)")
+	runs := flags.Int("runs", 1, "количество прогонов")
+	accounts := flags.Int("accounts", defaults.Accounts, "количество счетов")
+	steps := flags.Int("steps", defaults.Steps, "количество операций в прогоне")
+	maxAmount := flags.Float64("max-amount", defaults.MaxAmount, "предельная сумма операции")
+	overdraft := flags.Float64("overdraft", defaults.Overdraft, "предельный лимит овердрафта счета (0 - без овердрафта)")
+	feeRules := flags.String("fee-rules", "", "JSON-файл правил комиссий (по умолчанию встроенные правила прогона)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *runs <= 0 {
+		fmt.Fprintln(os.Stderr, "Ошибка: количество прогонов должно быть положительным")
+		return 2
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	opts := sim.Options{
+		Accounts:  *accounts,
+		Steps:     *steps,
+		MaxAmount: *maxAmount,
+		Overdraft: *overdraft,
+		FeeRules:  defaults.FeeRules,
+	}
+	if *feeRules != "" {
+		rules, err := loadFuzzFeeRules(*feeRules)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка правил комиссий: %v\n", err)
+			return 2
+		}
+		opts.FeeRules = rules
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for i := 0; i < *runs; i++ {
+		opts.Seed = *seed + int64(i)
+		report, err := sim.Run(ctx, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка прогона (seed %d): %v\n", opts.Seed, err)
+			return 2
+		}
+
+		fmt.Printf("seed %d: шагов %d, проведено %d, отклонено %d, сумма балансов %.2f\n",
+			report.Seed, report.Steps, report.Applied, report.Rejected, report.Total)
+
+		if err := report.Err(); err != nil {
+			fmt.Println("Последние операции:")
+			for _, step := range report.Trace {
+				fmt.Printf("  %s\n", step)
+			}
+			fmt.Printf("Ошибка: %v\n", err)
+			return 1
+		}
+	}
+
+	fmt.Printf("Нарушений не найдено за %d прогонов\n", *runs)
+	return 0
+}
+
+// loadFuzzFeeRules читает правила комиссий прогона из файла
+func loadFuzzFeeRules(path string) ([]models.FeeRule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return services.LoadFeeRules(file)
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// ChainHash вычисляет хеш транзакции по ее неизменяемым полям и PrevHash.
+// Разметка пользователя, вложения, отметки о сторно, проверке и
+// корректировках, а также RelatedID (у взноса после проверки купюр)
+// меняются после проведения и в хеш не входят.
+func (t Transaction) ChainHash() string {
+	h := sha256.New()
+	for _, field := range []string{
+		t.ID,
+		string(t.Type),
+		string(t.Direction),
+		strconv.FormatFloat(t.Amount, 'f', -1, 64),
+		t.Timestamp.UTC().Format(time.RFC3339Nano),
+		t.Message,
+		t.TransferID,
+		t.CounterpartyID,
+		string(t.Source),
+		t.ReversalOf,
+		t.PrevHash,
+	} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SealHistory включает в цепочку хешей транзакции, добавленные после
+// последней запечатанной. Уже запечатанные транзакции не пересчитываются,
+// иначе правка истории скрыла бы сама себя; по той же причине HistoryHash
+// не обновляется, если конец цепочки с ним уже не совпадает.
+func (a *Account) SealHistory() {
+	start, prev := 0, ""
+	for i := len(a.Transactions) - 1; i >= 0; i-- {
+		if a.Transactions[i].Hash != "" {
+			start, prev = i+1, a.Transactions[i].Hash
+			break
+		}
+	}
+
+	intact := a.HistoryHash == prev
+	for i := start; i < len(a.Transactions); i++ {
+		tx := &a.Transactions[i]
+		tx.PrevHash = prev
+		tx.Hash = tx.ChainHash()
+		prev = tx.Hash
+	}
+
+	if intact {
+		a.HistoryHash = prev
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"bankapp/models"
+	"context"
+)
+
+// VerifyHistory проверяет цепочку хешей транзакций счета и сообщает об
+// измененных, удаленных и переставленных транзакциях
+func (s *AdminServiceImpl) VerifyHistory(ctx context.Context, accountID string) (models.HistoryReport, error) {
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return models.HistoryReport{}, err
+	}
+
+	return verifyHistory(account), nil
+}
+
+// verifyHistory сверяет хеши транзакций счета с их содержимым и друг с другом.
+// Транзакции, записанные до появления цепочки, запечатываются при следующем
+// сохранении счета и нарушением не считаются, пока за ними нет запечатанных.
+func verifyHistory(account *models.Account) models.HistoryReport {
+	report := models.HistoryReport{
+		AccountID:    account.ID,
+		Transactions: len(account.Transactions),
+	}
+
+	lastSealed := -1
+	for i, tx := range account.Transactions {
+		if tx.Hash != "" {
+			lastSealed = i
+		}
+	}
+
+	addIssue := func(index int, transactionID, problem string) {
+		report.Issues = append(report.Issues, models.HistoryIssue{
+			Index:         index,
+			TransactionID: transactionID,
+			Problem:       problem,
+		})
+	}
+
+	prev := ""
+	for i, tx := range account.Transactions {
+		if tx.Hash == "" {
+			if i < lastSealed {
+				addIssue(i, tx.ID, "транзакция исключена из цепочки хешей")
+			}
+			continue
+		}
+
+		report.Sealed++
+		if tx.PrevHash != prev {
+			addIssue(i, tx.ID, "предыдущая транзакция удалена, изменена или переставлена")
+		}
+		if tx.ChainHash() != tx.Hash {
+			addIssue(i, tx.ID, "содержимое транзакции изменено")
+		}
+		prev = tx.Hash
+	}
+
+	if account.HistoryHash != prev {
+		addIssue(len(account.Transactions), "", "последние транзакции удалены из истории")
+	}
+
+	return report
+}
@@ -0,0 +1,110 @@
+package i18n
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"bankapp/errors"
+)
+
+// DefaultLocale язык исходных сообщений интерфейса
+const DefaultLocale = "ru"
+
+// catalogs каталоги переводов: ключом служит исходное сообщение на русском
+var catalogs = map[string]map[string]string{
+	DefaultLocale: {},
+	"en":          english,
+}
+
+// Supported проверяет, что для локали есть каталог сообщений
+func Supported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Locales возвращает доступные локали в алфавитном порядке
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+// Translator переводит сообщения интерфейса на язык локали. Сообщения без
+// перевода выводятся в исходном виде. Нулевой Translator ничего не переводит.
+type Translator struct {
+	locale  string
+	catalog map[string]string
+}
+
+// New создает переводчик для локали
+func New(locale string) (*Translator, error) {
+	catalog, ok := catalogs[locale]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errors.ErrUnsupportedLocale, locale)
+	}
+
+	return &Translator{
+		locale:  locale,
+		catalog: catalog,
+	}, nil
+}
+
+// Locale возвращает локаль переводчика
+func (t *Translator) Locale() string {
+	if t == nil {
+		return DefaultLocale
+	}
+
+	return t.locale
+}
+
+// T переводит сообщение
+func (t *Translator) T(message string) string {
+	if t == nil {
+		return message
+	}
+
+	if translated, ok := t.catalog[message]; ok {
+		return translated
+	}
+
+	return message
+}
+
+// Sprintf форматирует переведенный шаблон; ошибки среди аргументов тоже переводятся
+func (t *Translator) Sprintf(format string, args ...any) string {
+	for i, arg := range args {
+		if err, ok := arg.(error); ok {
+			args[i] = t.Error(err)
+		}
+	}
+
+	return fmt.Sprintf(t.T(format), args...)
+}
+
+// Error переводит текст ошибки. В обернутой ошибке переводится текст
+// каждой ошибки цепочки, найденной в каталоге.
+func (t *Translator) Error(err error) string {
+	message := err.Error()
+	if t == nil {
+		return message
+	}
+
+	if translated, ok := t.catalog[message]; ok {
+		return translated
+	}
+
+	for inner := stderrors.Unwrap(err); inner != nil; inner = stderrors.Unwrap(inner) {
+		if translated, ok := t.catalog[inner.Error()]; ok {
+			message = strings.Replace(message, inner.Error(), translated, 1)
+		}
+	}
+
+	return message
+}
@@ -0,0 +1,659 @@
+package i18n
+
+// english каталог сообщений для локали en
+var english = map[string]string{
+	"=== Банковское приложение ===":                       "=== Banking application ===",
+	"Ошибка при проверке согласованности: %v\n":           "Consistency check failed: %v\n",
+	"Счет %s помещен в карантин: %s\n":                    "Account %s quarantined: %s\n",
+	"Счет %s несогласован: %s\n":                          "Account %s is inconsistent: %s\n",
+	"Проверка согласованности: найдено проблем: %d\n":     "Consistency check: %d problem(s) found\n",
+	"Главное меню":                                        "Main menu",
+	"1. Создать счет":                                     "1. Create account",
+	"2. Выбрать счет":                                     "2. Select account",
+	"3. Показать мои счета":                               "3. Show my accounts",
+	"4. Настройки":                                        "4. Settings",
+	"6. Выйти из профиля":                                 "6. Log out",
+	"7. Выйти":                                            "7. Exit",
+	"Выберите опцию: ":                                    "Choose an option: ",
+	"До свидания!":                                        "Goodbye!",
+	"Неверный выбор. Попробуйте снова.":                   "Invalid choice. Please try again.",
+	"Меню счета":                                          "Account menu",
+	"1. Пополнить счет":                                   "1. Deposit",
+	"2. Снять средства":                                   "2. Withdraw",
+	"3. Перевести другому счету":                          "3. Transfer to another account",
+	"4. Просмотреть баланс":                               "4. View balance",
+	"5. Получить выписку":                                 "5. Get statement",
+	"6. Экспортировать выписку в файл":                    "6. Export statement to file",
+	"7. Сменить PIN-код":                                  "7. Change PIN",
+	"8. Прикрепить вложение к транзакции":                 "8. Attach a file to a transaction",
+	"9. Остаток дневного лимита":                          "9. Remaining daily limit",
+	"10. Входящие платежи":                                "10. Incoming payments",
+	"11. Мини-выписка (последние операции)":               "11. Mini statement (recent operations)",
+	"12. Псевдонимы и номер телефона":                     "12. Aliases and phone number",
+	"13. Отправить выписку":                               "13. Send statement",
+	"14. Ключ шифрования выписок":                         "14. Statement encryption key",
+	"15. Выписка за период в HTML":                        "15. Statement for a period as HTML",
+	"26. Вернуться в главное меню":                        "26. Back to main menu",
+	"Возврат в главное меню...":                           "Returning to main menu...",
+	"Введите имя владельца счета: ":                       "Enter account owner name: ",
+	"Имя владельца не может быть пустым":                  "Owner name cannot be empty",
+	"Придумайте PIN-код (4-6 цифр): ":                     "Choose a PIN (4-6 digits): ",
+	"Ошибка: %v\n":                                        "Error: %v\n",
+	"Ошибка при создании счета: %v\n":                     "Failed to create account: %v\n",
+	"Счет успешно создан!\n":                              "Account created successfully!\n",
+	"ID счета: %s\n":                                      "Account ID: %s\n",
+	"Владелец: %s\n":                                      "Owner: %s\n",
+	"Введите PIN-код: ":                                   "Enter PIN: ",
+	"Счет %s выбран для работы\n":                         "Account %s selected\n",
+	"Ошибка при получении счетов: %v\n":                   "Failed to load accounts: %v\n",
+	"Мои счета":                                           "My accounts",
+	"ID: %s | Владелец: %s | Баланс: %.2f\n":              "ID: %s | Owner: %s | Balance: %.2f\n",
+	"Счета не найдены":                                    "No accounts found",
+	"\n--- Все счета (%d-%d из %d) ---\n":                 "\n--- All accounts (%d-%d of %d) ---\n",
+	"активен":                                             "active",
+	"карантин: %s":                                        "quarantine: %s",
+	"заморожен":                                           "frozen",
+	"закрыт":                                              "closed",
+	"ID: %s | Владелец: %s | Баланс: %.2f | Статус: %s\n": "ID: %s | Owner: %s | Balance: %.2f | Status: %s\n",
+	"    Менеджер: %s\n":                                  "    Manager: %s\n",
+	"    Заметки: %s\n":                                   "    Notes: %s\n",
+	"Enter - следующая страница, q - выход: ":             "Enter - next page, q - quit: ",
+	"Минимальная сумма (Enter - без ограничения): ":       "Minimum amount (Enter - no limit): ",
+	"Максимальная сумма (Enter - без ограничения): ":      "Maximum amount (Enter - no limit): ",
+	"ID счета контрагента (Enter - любой): ":              "Counterparty account ID (Enter - any): ",
+	"Дата с (ГГГГ-ММ-ДД, Enter - без ограничения): ":      "Date from (YYYY-MM-DD, Enter - no limit): ",
+	"Дата по (ГГГГ-ММ-ДД, Enter - без ограничения): ":     "Date to (YYYY-MM-DD, Enter - no limit): ",
+	"Текст в описании (Enter - любой): ":                  "Text in description (Enter - any): ",
+	"Ошибка при поиске: %v\n":                             "Search failed: %v\n",
+	"Транзакции не найдены":                               "No transactions found",
+	"\n--- Найдено транзакций: %d (показаны %d-%d) ---\n": "\n--- Transactions found: %d (showing %d-%d) ---\n",
+	"Введите сумму для пополнения: ":                      "Enter amount to deposit: ",
+	"16. Категории, теги и заметки":                       "16. Categories, tags and notes",
+	"Категория (Enter - без категории): ":                 "Category (Enter - none): ",
+	"Теги через пробел (Enter - без тегов): ":             "Space-separated tags (Enter - none): ",
+	"Заметка (Enter - без заметки): ":                     "Note (Enter - none): ",
+	"Категории, теги и заметки":                           "Categories, tags and notes",
+	"1. Выписка по категории или тегу":                    "1. Statement by category or tag",
+	"2. Расходы по категориям":                            "2. Spending by category",
+	"3. Изменить категорию, теги и заметку транзакции":    "3. Edit transaction category, tags and note",
+	"4. Назад": "4. Back",
+	"Категория (Enter - любая): ":                                               "Category (Enter - any): ",
+	"Тег (Enter - любой): ":                                                     "Tag (Enter - any): ",
+	"Транзакций: %d, итого: %.2f %s\n":                                          "Transactions: %d, total: %.2f %s\n",
+	"Дата с (ГГГГ-ММ-ДД, Enter - начало месяца): ":                              "From (YYYY-MM-DD, Enter - start of month): ",
+	"Дата по (ГГГГ-ММ-ДД, Enter - сегодня): ":                                   "To (YYYY-MM-DD, Enter - today): ",
+	"Баланс на %s: %.2f %s\n":                                                   "Balance at %s: %.2f %s\n",
+	"Дата с (ГГГГ-ММ-ДД, Enter - 30 дней назад): ":                              "From (YYYY-MM-DD, Enter - 30 days ago): ",
+	"Списаний за период нет":                                                    "No debits for the period",
+	"без категории":                                                             "uncategorized",
+	"%-20s %10.2f (операций: %d)\n":                                             "%-20s %10.2f (operations: %d)\n",
+	"Всего списаний: %.2f %s\n":                                                 "Total debits: %.2f %s\n",
+	"Категория (Enter - без изменений, - - убрать): ":                           "Category (Enter - keep, - - remove): ",
+	"Теги через пробел (Enter - без изменений, - - убрать): ":                   "Space-separated tags (Enter - keep, - - remove): ",
+	"Заметка (Enter - без изменений, - - убрать): ":                             "Note (Enter - keep, - - remove): ",
+	"    категория: %s, теги: %s\n":                                             "    category: %s, tags: %s\n",
+	"    заметка: %s\n":                                                         "    note: %s\n",
+	"17. Детские счета":                                                         "17. Child accounts",
+	"Детские счета":                                                             "Child accounts",
+	"Дочерних счетов нет":                                                       "No child accounts",
+	"%s | %s | потрачено сегодня: %.2f\n":                                       "%s | %s | spent today: %.2f\n",
+	"    дневной лимит: %.2f\n":                                                 "    daily limit: %.2f\n",
+	"    запрещенные категории: %s\n":                                           "    blocked categories: %s\n",
+	"1. Привязать дочерний счет":                                                "1. Link a child account",
+	"2. Ограничения дочернего счета":                                            "2. Child account controls",
+	"3. Отвязать дочерний счет":                                                 "3. Unlink a child account",
+	"PIN-код дочернего счета: ":                                                 "Child account PIN: ",
+	"Счет %s привязан как дочерний\n":                                           "Account %s linked as a child\n",
+	"Дневной лимит трат (Enter - без ограничения): ":                            "Daily spending limit (Enter - no limit): ",
+	"Запрещенные категории через запятую (Enter - нет): ":                       "Comma-separated blocked categories (Enter - none): ",
+	"Источник (1 - наличные, 2 - чек, 3 - внешний перевод, Enter - наличные): ": "Source (1 - cash, 2 - cheque, 3 - incoming transfer, Enter - cash): ",
+	"18. Статистика":                                                            "18. Statistics",
+	"Статистика":                                                                "Statistics",
+	"Количество месяцев (Enter - 3): ":                                          "Number of months (Enter - 3): ",
+	"\n%s: поступления %.2f, списания %.2f\n":                                   "\n%s: in %.2f, out %.2f\n",
+	"Расходы по категориям:":                                                    "Spending by category:",
+	"\nСредний баланс: %.2f %s\n":                                               "\nAverage balance: %.2f %s\n",
+	"Чистый поток: %+.2f %s\n":                                                  "Net flow: %+.2f %s\n",
+	"Крупнейшие операции:":                                                      "Largest transactions:",
+	"Ошибка при пополнении: %v\n":                                               "Deposit failed: %v\n",
+	"Счет успешно пополнен на %.2f\n":                                           "Deposited %.2f successfully\n",
+	"Введите сумму для снятия: ":                                                "Enter amount to withdraw: ",
+	"Ошибка при снятии: %v\n":                                                   "Withdrawal failed: %v\n",
+	"Со счета успешно снято %.2f\n":                                             "Withdrew %.2f successfully\n",
+	"Введите сумму для перевода: ":                                              "Enter amount to transfer: ",
+	"Введите ID, псевдоним или телефон получателя: ":                            "Enter recipient account ID, alias or phone: ",
+	"Ошибка при переводе: %v\n":                                                 "Transfer failed: %v\n",
+	"Успешно переведено %.2f на счет %s\n":                                      "Transferred %.2f to account %s\n",
+	"Транзакция: %s от %s\n":                                                    "Transaction: %s at %s\n",
+	"Комиссия: %.2f\n":                                                          "Fee: %.2f\n",
+	"Операция передана на проверку":                                             "The operation has been sent for review",
+	"Баланс после операции: %.2f %s\n":                                          "Balance after operation: %.2f %s\n",
+	"Текущий баланс: %.2f %s\n":                                                 "Current balance: %.2f %s\n",
+	"Доступно с учетом удержаний: %.2f %s\n":                                    "Available after holds: %.2f %s\n",
+	"Сумма: использовано %.2f из %.2f, осталось %.2f\n":                         "Amount: used %.2f of %.2f, %.2f remaining\n",
+	"Сумма: использовано %.2f, без ограничения\n":                               "Amount: used %.2f, no limit\n",
+	"Операции: использовано %d из %d, осталось %d\n":                            "Operations: used %d of %d, %d remaining\n",
+	"Операции: использовано %d, без ограничения\n":                              "Operations: used %d, no limit\n",
+	"Выписка содержит %d строк.\n":                                              "The statement has %d lines.\n",
+	"1. Просмотреть постранично":                                                "1. View page by page",
+	"2. Сохранить в файл":                                                       "2. Save to file",
+	"3. Вывести целиком":                                                        "3. Print in full",
+	"-- строки %d-%d из %d. Enter - далее, q - выход: ":                         "-- lines %d-%d of %d. Enter - next, q - quit: ",
+	"Введите формат (csv/json, Enter - %s): ":                                   "Enter format (csv/json, Enter - %s): ",
+	"Введите путь к файлу: ":                                                    "Enter file path: ",
+	"Путь к файлу не может быть пустым":                                         "File path cannot be empty",
+	"Ошибка при создании файла: %v\n":                                           "Failed to create file: %v\n",
+	"Ошибка при экспорте: %v\n":                                                 "Export failed: %v\n",
+	"Выписка сохранена в %s\n":                                                  "Statement saved to %s\n",
+	"Начало периода (ГГГГ-ММ-ДД, Enter - с открытия счета): ":                   "Period start (YYYY-MM-DD, Enter - since opening): ",
+	"Конец периода включительно (ГГГГ-ММ-ДД, Enter - по сегодня): ":             "Period end, inclusive (YYYY-MM-DD, Enter - through today): ",
+	"Выписка по счету":                                                          "Account statement",
+	"Владелец":                                                                  "Owner",
+	"Счет":                                                                      "Account",
+	"Период":                                                                    "Period",
+	"Дата":                                                                      "Date",
+	"Тип":                                                                       "Type",
+	"Описание":                                                                  "Description",
+	"Сумма":                                                                     "Amount",
+	"Остаток":                                                                   "Balance",
+	"Пояснение":                                                                 "Remark",
+	"Остаток на начало периода":                                                 "Opening balance",
+	"Остаток на конец периода":                                                  "Closing balance",
+	"Сформировано":                                                              "Generated",
+	"Ошибка при отправке выписки: %v\n":                                         "Failed to send statement: %v\n",
+	"Выписка зашифрована и отправлена":                                          "Statement encrypted and sent",
+	"Выписка отправлена без шифрования":                                         "Statement sent unencrypted",
+	"Ключ шифрования выписок загружен":                                          "Statement encryption key is set",
+	"Ключ шифрования выписок не загружен":                                       "No statement encryption key",
+	"Ключ X25519 (base64, \"-\" - удалить): ":                                   "X25519 key (base64, \"-\" - remove): ",
+	"Ключ удален":                                                               "Key removed",
+	"Ключ сохранен, выписки будут шифроваться":                                  "Key saved, statements will be encrypted",
+	"История транзакций пуста":                                                  "Transaction history is empty",
+	"Последние транзакции":                                                      "Recent transactions",
+	"Введите ID транзакции: ":                                                   "Enter transaction ID: ",
+	"Введите путь к файлу или URL документа: ":                                  "Enter file path or document URL: ",
+	"Ошибка при чтении файла: %v\n":                                             "Failed to read file: %v\n",
+	"Ошибка при добавлении вложения: %v\n":                                      "Failed to add attachment: %v\n",
+	"Вложение %s добавлено к транзакции %s\n":                                   "Attachment %s added to transaction %s\n",
+	"Входящих платежей нет":                                                     "No incoming payments",
+	"Входящие платежи":                                                          "Incoming payments",
+	"%s | %s | %.2f | от: %s | %s\n":                                            "%s | %s | %.2f | from: %s | %s\n",
+	"1. Принять платеж":                                                         "1. Accept payment",
+	"2. Вернуть платеж отправителю":                                             "2. Return payment to sender",
+	"3. Включить автоприем":                                                     "3. Enable auto-accept",
+	"4. Отключить автоприем":                                                    "4. Disable auto-accept",
+	"Переводов, ожидающих подтверждения, нет":                                   "No transfers awaiting confirmation",
+	"Переводы с подтверждением":                                                 "Transfers with confirmation",
+	"%s | %s | %.2f (комиссия %.2f) | на: %s | до %s\n":                         "%s | %s | %.2f (fee %.2f) | to: %s | until %s\n",
+	"1. Создать перевод":                                                        "1. Create transfer",
+	"2. Подтвердить перевод":                                                    "2. Confirm transfer",
+	"3. Отменить перевод":                                                       "3. Cancel transfer",
+	"Введите ID перевода: ":                                                     "Enter transfer ID: ",
+	"Перевод отменен, удержание снято":                                          "Transfer cancelled, hold released",
+	"Действующих доверенностей нет":                                             "No active access grants",
+	"Доверенности":                                                              "Access grants",
+	"%s | %s | %s | переведено %.2f из %.2f | до %s\n":                          "%s | %s | %s | transferred %.2f of %.2f | until %s\n",
+	"1. Выдать доверенность":                                                    "1. Grant access",
+	"2. Отозвать доверенность":                                                  "2. Revoke access",
+	"Введите ID доверенности: ":                                                 "Enter grant ID: ",
+	"Доверенность отозвана":                                                     "Access revoked",
+	"Объем доступа (VIEW - просмотр, TRANSFER - и переводы): ":                  "Access scope (VIEW - view only, TRANSFER - also transfers): ",
+	"Общий лимит переводов: ":                                                   "Total transfer limit: ",
+	"Срок действия в днях: ":                                                    "Validity in days: ",
+	"Некорректный срок действия":                                                "Invalid validity period",
+	"Доверенность %s выдана пользователю %s до %s\n":                            "Access grant %s issued to %s until %s\n",
+	"Счет %s выбран для работы по доверенности (%s до %s)\n":                    "Account %s selected under access grant (%s until %s)\n",
+	"    по доверенности %s до %s\n":                                            "    under access grant %s until %s\n",
+	"%s по доверенности %s":                                                     "%s under grant %s",
+	"21. Доверенности":                                                          "21. Access grants",
+	"1. Выдать кредит":                                                          "1. Issue a loan",
+	"2. Провести платежи по графику":                                            "2. Post scheduled repayments",
+	"Текущий счет для выдачи и погашения: ":                                     "Checking account for disbursement and repayment: ",
+	"Сумма кредита: ":                                                           "Loan amount: ",
+	"Годовая ставка, %: ":                                                       "Annual rate, %: ",
+	"Некорректная ставка":                                                       "Invalid rate",
+	"Срок в месяцах: ":                                                          "Term in months: ",
+	"Некорректный срок":                                                         "Invalid term",
+	"Кредит выдан, кредитный счет %s\n":                                         "Loan issued, loan account %s\n",
+	"Платежи по кредитам":                                                       "Loan repayments",
+	"%s  платеж %d  %.2f\n":                                                     "%s  payment %d  %.2f\n",
+	"%s  платеж %d  пропущен: %s\n":                                             "%s  payment %d  skipped: %s\n",
+	"текущий счет недоступен":                                                   "checking account unavailable",
+	"Проведено: %d, пропущено: %d, сумма: %.2f\n":                               "Posted: %d, skipped: %d, total: %.2f\n",
+	"Остаток основного долга: %.2f %s\n":                                        "Remaining principal: %.2f %s\n",
+	"Внесено платежей: %d из %d\n":                                              "Payments made: %d of %d\n",
+	"Кредит погашен":                                                            "Loan repaid",
+	"Следующий платеж: %.2f %s, %s\n":                                           "Next payment: %.2f %s, %s\n",
+	"График платежей":                                                           "Repayment schedule",
+	"%3d  %s  платеж %.2f  проценты %.2f  долг %.2f  остаток %.2f\n":            "%3d  %s  payment %.2f  interest %.2f  principal %.2f  remaining %.2f\n",
+	"22. Кредит: остаток и график платежей":                                     "22. Loan: balance and schedule",
+	"\nЗавершение работы...":                                                    "\nShutting down...",
+	"Ошибка при закрытии хранилища: %v\n":                                       "Failed to close storage: %v\n",
+	"%s = %.2f. Подтвердить? (y/n): ":                                           "%s = %.2f. Confirm? (y/n): ",
+	"Сумма не подтверждена":                                                     "Amount not confirmed",
+	"Операция невозможна: %v\n":                                                 "Operation not possible: %v\n",
+	"Получатель: %s\n":                                                          "Recipient: %s\n",
+	"Сумма: %.2f %s\n":                                                          "Amount: %.2f %s\n",
+	"Баланс: %.2f -> %.2f %s\n":                                                 "Balance: %.2f -> %.2f %s\n",
+	"Доступно после операции: %.2f %s\n":                                        "Available after operation: %.2f %s\n",
+	"Выполнить операцию? (y/n): ":                                               "Proceed? (y/n): ",
+	"Операция отменена":                                                         "Operation cancelled",
+	"Код проверки квитанции: %s\n":                                              "Receipt verification code: %s\n",
+	"Формат (1 - текст, 2 - JSON, Enter - текст): ":                             "Format (1 - text, 2 - JSON, Enter - text): ",
+	"Квитанция":                                                                 "Receipt",
+	"Счет: %s\n":                                                                "Account: %s\n",
+	"Операция: %s %s\n":                                                         "Operation: %s %s\n",
+	"Контрагент: %s\n":                                                          "Counterparty: %s\n",
+	"Вставьте квитанцию в формате JSON: ":                                       "Paste the receipt as JSON: ",
+	"Квитанция недействительна: %v\n":                                           "Receipt is not valid: %v\n",
+	"Квитанция подлинная":                                                       "Receipt is authentic",
+	"Выписка по счету %s за %s - %s":                                            "Statement for account %s, %s - %s",
+	"Ошибка ежемесячной рассылки выписок: %v\n":                                 "Monthly statement mailing failed: %v\n",
+	"Выписка для счета %s не отправлена на %s: %s\n":                            "Statement for account %s not sent to %s: %s\n",
+	"Выписки за %s: отправлено %d, ошибок %d\n":                                 "Statements for %s: %d sent, %d failed\n",
+	"Адрес для выписок не указан":                                               "No statement email address set",
+	"Адрес для выписок: %s\n":                                                   "Statement email address: %s\n",
+	"Ежемесячная рассылка включена":                                             "Monthly statements are on",
+	"1. Изменить адрес и ежемесячную рассылку":                                  "1. Change address and monthly statements",
+	"2. Отправить выписку за период":                                            "2. Send statement for a period",
+	"Адрес электронной почты (Enter - отключить): ":                             "Email address (Enter - turn off): ",
+	"Присылать выписку ежемесячно? (y/n): ":                                     "Send a statement every month? (y/n): ",
+	"Отправка выписок по почте отключена":                                       "Statements by email turned off",
+	"Адрес для выписок сохранен":                                                "Statement email address saved",
+	"Добавить HTML-версию? (y/n): ":                                             "Include an HTML version? (y/n): ",
+	"Выписка отправлена по электронной почте":                                   "Statement sent by email",
+	"24. Выписки по электронной почте":                                          "24. Statements by email",
+	"\nВвод завершен":                                                           "\nEnd of input",
+	"Введите формат (csv/json/jsonl, Enter - %s): ":                             "Enter format (csv/json/jsonl, Enter - %s): ",
+	"Найдена прерванная выгрузка в этот файл. Продолжить? (y/n): ":              "Found an interrupted export to this file. Resume? (y/n): ",
+	"Выписка сохранена в %s (транзакций: %d)\n":                                 "Statement saved to %s (transactions: %d)\n",
+	"Ошибка при отмене просроченных операций: %v\n":                             "Error cancelling expired operations: %v\n",
+	"Перевод %s по счету %s не подтвержден в срок, удержание %.2f снято\n":      "Transfer %s from account %s was not confirmed in time, hold of %.2f released\n",
+	"Платеж %s на счет %s не принят в срок, %.2f возвращено отправителю\n":      "Incoming payment %s to account %s was not accepted in time, %.2f returned to sender\n",
+	"    принять до %s\n":                                                       "    accept by %s\n",
+	"Транзакций: %d, в цепочке хешей: %d\n":                                     "Transactions: %d, in hash chain: %d\n",
+	"Нарушений целостности не найдено":                                          "No integrity violations found",
+	"Найдено нарушений целостности: %d\n":                                       "Integrity violations found: %d\n",
+	"транзакция исключена из цепочки хешей":                                     "transaction removed from the hash chain",
+	"предыдущая транзакция удалена, изменена или переставлена":                  "previous transaction deleted, modified or reordered",
+	"содержимое транзакции изменено":                                            "transaction content modified",
+	"последние транзакции удалены из истории":                                   "latest transactions deleted from history",
+	"1. Архивировать неактивные счета":                                          "1. Archive inactive accounts",
+	"2. Показать архив":                                                         "2. Show archive",
+	"3. Вернуть счет из архива":                                                 "3. Restore an account from the archive",
+	"Перенести счета в архив? (y/n): ":                                          "Move the accounts to the archive? (y/n): ",
+	"Архивация отменена":                                                        "Archiving cancelled",
+	"\n--- Предварительный расчет архивации (без операций %d дн.) ---\n":        "\n--- Archiving preview (no activity for %d days) ---\n",
+	"\n--- Архивация счетов (без операций %d дн.) ---\n":                        "\n--- Archiving accounts (no activity for %d days) ---\n",
+	"%s | %s | последняя операция: %s":                                          "%s | %s | last activity: %s",
+	"Счетов к архивации: %d, пропущено: %d\n":                                   "Accounts to archive: %d, skipped: %d\n",
+	"Архив пуст":                                                                "The archive is empty",
+	"Архив счетов":                                                              "Account archive",
+	"ID: %s | Владелец: %s | в архиве с %s\n":                                   "ID: %s | Owner: %s | archived since %s\n",
+	"Счет %s возвращен из архива\n":                                             "Account %s restored from the archive\n",
+	"есть незавершенные операции":                                               "there are unfinished operations",
+	"25. Отменить последнюю операцию":                                           "25. Undo last operation",
+	"Отменить последнюю операцию? (y/n): ":                                      "Undo the last operation? (y/n): ",
+	"Операция %s (%s на %.2f) отменена\n":                                       "Operation %s (%s for %.2f) undone\n",
+	"Перевод %s подтвержден\n":                                                  "Transfer %s confirmed\n",
+	"Перевод %s создан, %.2f удержано до %s\n":                                  "Transfer %s created, %.2f held until %s\n",
+	"5. Назад":                           "5. Back",
+	"19. История баланса":                "19. Balance history",
+	"20. Переводы с подтверждением":      "20. Transfers with confirmation",
+	"5. Проверить квитанцию":             "5. Verify a receipt",
+	"23. Квитанция по операции":          "23. Operation receipt",
+	"1. Баланс на дату":                  "1. Balance at a date",
+	"2. История баланса по дням":         "2. Daily balance history",
+	"3. Назад":                           "3. Back",
+	"Введите ID платежа: ":               "Enter payment ID: ",
+	"Готово":                             "Done",
+	"Введите текущий PIN-код: ":          "Enter current PIN: ",
+	"Введите новый PIN-код (4-6 цифр): ": "Enter new PIN (4-6 digits): ",
+	"Ошибка при смене PIN-кода: %v\n":    "Failed to change PIN: %v\n",
+	"PIN-код успешно изменен":            "PIN changed successfully",
+	"Ошибка: некорректная дата":          "Error: invalid date",
+	"Вход":     "Sign in",
+	"1. Войти": "1. Log in",
+	"2. Зарегистрироваться": "2. Register",
+	"3. Выйти":                                                                    "3. Exit",
+	"Меню администратора":                                                         "Administrator menu",
+	"1. Показать все счета":                                                       "1. Show all accounts",
+	"2. Поиск транзакций":                                                         "2. Search transactions",
+	"3. Заморозить счет":                                                          "3. Freeze account",
+	"4. Разморозить счет":                                                         "4. Unfreeze account",
+	"5. Пересобрать балансы из журнала событий":                                   "5. Rebuild balances from the event log",
+	"6. Сверка проводок":                                                          "6. Reconcile entries",
+	"7. Установить лимит овердрафта":                                              "7. Set overdraft limit",
+	"8. Баланс счета на дату":                                                     "8. Account balance at a date",
+	"9. Установить дневные лимиты":                                                "9. Set daily limits",
+	"10. Заметки и менеджер счета":                                                "10. Account notes and manager",
+	"11. Списать плату за обслуживание":                                           "11. Charge maintenance fees",
+	"12. Сторнировать транзакцию":                                                 "12. Reverse a transaction",
+	"13. Закрыть счет":                                                            "13. Close account",
+	"14. Кассовый отчет за день":                                                  "14. Daily cash report",
+	"15. Зарегистрировать входящий внешний платеж":                                "15. Register an incoming external payment",
+	"16. Журнал аудита":                                                           "16. Audit log",
+	"17. Пересчет комиссий за период":                                             "17. Recalculate fees for a period",
+	"18. Импорт счетов из CSV":                                                    "18. Import accounts from CSV",
+	"19. Повторная доставка вебхуков":                                             "19. Replay webhooks",
+	"20. Проверка подозрительных купюр":                                           "20. Verify suspect notes",
+	"21. Перевод остатков неактивных счетов":                                      "21. Sweep dormant account balances",
+	"22. Хронология счета":                                                        "22. Account timeline",
+	"23. Операция пользовательского типа":                                         "23. Custom-type transaction",
+	"24. Операции на проверке":                                                    "24. Flagged operations review",
+	"25. Приостановка операций":                                                   "25. Operation suspension",
+	"26. Кредиты":                                                                 "26. Loans",
+	"27. Резервная копия":                                                         "27. Backup",
+	"28. Проверка целостности истории":                                            "28. Verify history integrity",
+	"29. Архив счетов":                                                            "29. Account archive",
+	"30. Настройки":                                                               "30. Settings",
+	"31. Проводка по счетам":                                                      "31. Ledger posting",
+	"32. Выгрузка журнала аудита":                                                 "32. Export audit log",
+	"33. Выйти из профиля":                                                        "33. Log out",
+	"34. Выйти":                                                                   "34. Exit",
+	"Добро пожаловать, %s!\n":                                                     "Welcome, %s!\n",
+	"Ошибка при регистрации: %v\n":                                                "Registration failed: %v\n",
+	"Пользователь %s зарегистрирован\n":                                           "User %s registered\n",
+	"Вы первый пользователь и получили роль администратора":                       "You are the first user and have been granted the administrator role",
+	"Вы вышли из профиля":                                                         "You have logged out",
+	"Имя пользователя: ":                                                          "Username: ",
+	"Пароль: ":                                                                    "Password: ",
+	"Счет %s заморожен\n":                                                         "Account %s frozen\n",
+	"Счет %s разморожен\n":                                                        "Account %s unfrozen\n",
+	"Ошибка при пересборке балансов: %v\n":                                        "Failed to rebuild balances: %v\n",
+	"Счет %s: баланс %.2f, по журналу %.2f, история %+.2f (событий: %d)\n":        "Account %s: balance %.2f, per log %.2f, history %+.2f (events: %d)\n",
+	"Проверено счетов: %d, расхождений: %d\n":                                     "Accounts checked: %d, mismatches: %d\n",
+	"Исправить балансы по журналу? (y/n): ":                                       "Correct balances from the log? (y/n): ",
+	"Пересборка отменена":                                                         "Rebuild cancelled",
+	"Исправлено счетов: %d\n":                                                     "Accounts corrected: %d\n",
+	"Ошибка при сверке: %v\n":                                                     "Reconciliation failed: %v\n",
+	"Проверено проводок: %d\n":                                                    "Entries checked: %d\n",
+	"Сумма внутренних проводок: %.2f\n":                                           "Internal entries total: %.2f\n",
+	"Несбалансированный перевод %s\n":                                             "Unbalanced transfer %s\n",
+	"Расхождение по счету %s\n":                                                   "Mismatch on account %s\n",
+	"Расхождений не обнаружено":                                                   "No discrepancies found",
+	"Введите лимит овердрафта (0 - отключить): ":                                  "Enter overdraft limit (0 - disable): ",
+	"Лимит овердрафта счета %s установлен: %.2f\n":                                "Overdraft limit for account %s set to %.2f\n",
+	"Дата (ГГГГ-ММ-ДД или ГГГГ-ММ-ДД ЧЧ:ММ): ":                                    "Date (YYYY-MM-DD or YYYY-MM-DD HH:MM): ",
+	"Баланс счета %s на %s: %.2f\n":                                               "Balance of account %s at %s: %.2f\n",
+	"Дневной лимит суммы (0 - без ограничения): ":                                 "Daily amount limit (0 - no limit): ",
+	"Дневной лимит количества операций (0 - без ограничения): ":                   "Daily operation count limit (0 - no limit): ",
+	"Дневные лимиты счета %s обновлены\n":                                         "Daily limits for account %s updated\n",
+	"Менеджер: %s\n":                                                              "Manager: %s\n",
+	"Заметки: %s\n":                                                               "Notes: %s\n",
+	"Новый менеджер (Enter - без изменений, - - снять): ":                         "New manager (Enter - keep, - - remove): ",
+	"Новые заметки (Enter - без изменений, - - очистить): ":                       "New notes (Enter - keep, - - clear): ",
+	"Данные счета %s обновлены\n":                                                 "Account %s details updated\n",
+	"Ошибка при расчете платы: %v\n":                                              "Failed to calculate fees: %v\n",
+	"Списать плату? (y/n): ":                                                      "Charge the fees? (y/n): ",
+	"Списание отменено":                                                           "Charging cancelled",
+	"Ошибка при списании платы: %v\n":                                             "Failed to charge fees: %v\n",
+	"\n--- Предварительный расчет платы за %s ---\n":                              "\n--- Maintenance fee preview for %s ---\n",
+	"\n--- Списание платы за %s ---\n":                                            "\n--- Maintenance fees charged for %s ---\n",
+	"%s | пропущен: %s\n":                                                         "%s | skipped: %s\n",
+	"Счетов к списанию: %d, пропущено: %d, сумма: %.2f\n":                         "Accounts to charge: %d, skipped: %d, total: %.2f\n",
+	"Перевести остатки? (y/n): ":                                                  "Sweep the balances? (y/n): ",
+	"Перевод отменен":                                                             "Sweep cancelled",
+	"\n--- Предварительный расчет перевода на пул-счет %s ---\n":                  "\n--- Sweep preview to pool account %s ---\n",
+	"\n--- Перевод остатков на пул-счет %s ---\n":                                 "\n--- Balances swept to pool account %s ---\n",
+	"%s | %.2f | пропущен: %s\n":                                                  "%s | %.2f | skipped: %s\n",
+	"Счетов к переводу: %d, пропущено: %d, сумма: %.2f\n":                         "Accounts to sweep: %d, skipped: %d, total: %.2f\n",
+	"отрицательный остаток":                                                       "negative balance",
+	"остаток выше порога":                                                         "balance above the threshold",
+	"Начало периода (ГГГГ-ММ-ДД): ":                                               "Period start (YYYY-MM-DD): ",
+	"Конец периода включительно (ГГГГ-ММ-ДД): ":                                   "Period end, inclusive (YYYY-MM-DD): ",
+	"Ошибка при пересчете комиссий: %v\n":                                         "Fee recalculation failed: %v\n",
+	"Провести корректировки? (y/n): ":                                             "Post the corrections? (y/n): ",
+	"Купюры (номинал x количество через запятую, Enter - без разбивки): ":         "Notes (value x count, comma-separated, Enter - no breakdown): ",
+	"Подозрительные купюры (номинал x количество, Enter - нет): ":                 "Suspect notes (value x count, Enter - none): ",
+	"Подозрительные купюры на %.2f отложены на проверку (%s)\n":                   "Suspect notes worth %.2f held for verification (%s)\n",
+	"Подозрительные купюры":                                                       "Suspect notes",
+	"Нет купюр, ожидающих проверки":                                               "No notes awaiting verification",
+	"%s  счет %s  %.2f  от %s\n":                                                  "%s  account %s  %.2f  on %s\n",
+	"ID удержания (Enter - назад): ":                                              "Hold ID (Enter - back): ",
+	"Купюры подлинные? (y/n): ":                                                   "Are the notes genuine? (y/n): ",
+	"Купюры подлинные: %.2f зачислено на счет %s (транзакция %s)\n":               "Notes are genuine: %.2f credited to account %s (transaction %s)\n",
+	"Купюры признаны фальшивыми и изъяты, сумма %.2f не зачислена\n":              "Notes found counterfeit and withheld, %.2f not credited\n",
+	"Корректировки отменены":                                                      "Corrections cancelled",
+	"Операции на проверке":                                                        "Flagged operations",
+	"Нет операций, ожидающих проверки":                                            "No operations awaiting review",
+	"%s  счет %s (%s)  %s  %.2f  риск %.2f  от %s\n":                              "%s  account %s (%s)  %s  %.2f  risk %.2f  at %s\n",
+	"ID транзакции (Enter - назад): ":                                             "Transaction ID (Enter - back): ",
+	"Одобрить операцию? (y - одобрить, n - отклонить и сторнировать): ":           "Approve the operation? (y - approve, n - reject and reverse): ",
+	"Операция %s одобрена\n":                                                      "Operation %s approved\n",
+	"Операция %s отклонена и сторнирована\n":                                      "Operation %s rejected and reversed\n",
+	"Приостановка операций":                                                       "Operation suspension",
+	"Все операции доступны":                                                       "All operations are available",
+	"%s  с %s  (%s)  %s\n":                                                        "%s  since %s  (%s)  %s\n",
+	"1. Приостановить операции":                                                   "1. Suspend operations",
+	"2. Возобновить операции":                                                     "2. Resume operations",
+	"Тип операции (DEPOSIT, WITHDRAW, TRANSFER): ":                                "Operation type (DEPOSIT, WITHDRAW, TRANSFER): ",
+	"Сообщение для клиентов: ":                                                    "Message for customers: ",
+	"Операции %s возобновлены\n":                                                  "%s operations resumed\n",
+	"Операции %s приостановлены\n":                                                "%s operations suspended\n",
+	"Ошибка при проведении корректировок: %v\n":                                   "Failed to post corrections: %v\n",
+	"Предварительный пересчет комиссий за %s":                                     "Fee recalculation preview for %s",
+	"Пересчет комиссий за %s":                                                     "Fee recalculation for %s",
+	"Расхождений не найдено":                                                      "No discrepancies found",
+	"%s (%s) | комиссия %s | списано %.2f, по правилам %.2f, корректировка %+.2f": "%s (%s) | fee %s | charged %.2f, per rules %.2f, correction %+.2f",
+	" | пропущено: %s":                                                            " | skipped: %s",
+	"Затронуто счетов: %d, к возврату: %.2f, к доначислению: %.2f\n":              "Accounts affected: %d, to refund: %.2f, to collect: %.2f\n",
+	"Путь к файлу счетов (CSV): ":                                                 "Accounts file path (CSV): ",
+	"Путь к файлу транзакций (CSV): ":                                             "Transactions file path (CSV): ",
+	"Ошибка при импорте: %v\n":                                                    "Import failed: %v\n",
+	"Импортировано счетов: %d, транзакций: %d\n":                                  "Imported accounts: %d, transactions: %d\n",
+	"Вебхук не настроен":                                                          "Webhook is not configured",
+	"Начало периода (ГГГГ-ММ-ДД, Enter - без ограничения): ":                      "Period start (YYYY-MM-DD, Enter - unbounded): ",
+	"Конец периода включительно (ГГГГ-ММ-ДД, Enter - без ограничения): ":          "Period end, inclusive (YYYY-MM-DD, Enter - unbounded): ",
+	"ID транзакций через запятую (Enter - все): ":                                 "Transaction IDs, comma-separated (Enter - all): ",
+	"Ошибка при повторной доставке: %v\n":                                         "Replay failed: %v\n",
+	"Найдено уведомлений: %d, доставлено: %d\n":                                   "Notifications found: %d, delivered: %d\n",
+	"Не доставлены: %s\n":                                                         "Not delivered: %s\n",
+	"1. Создать резервную копию":                                                  "1. Create a backup",
+	"2. Восстановить из резервной копии":                                          "2. Restore from a backup",
+	"Ошибка при создании резервной копии: %v\n":                                   "Failed to create backup: %v\n",
+	"Резервная копия сохранена в %s\n":                                            "Backup saved to %s\n",
+	"Ошибка при проверке резервной копии: %v\n":                                   "Backup check failed: %v\n",
+	"Восстановить? (y/n): ":                                                       "Restore? (y/n): ",
+	"Восстановление отменено":                                                     "Restore cancelled",
+	"Ошибка при восстановлении: %v\n":                                             "Restore failed: %v\n",
+	"Проверка резервной копии от %s":                                              "Backup check, created %s",
+	"Восстановление из резервной копии от %s":                                     "Restore from backup created %s",
+	"Версия формата: %d\n":                                                        "Schema version: %d\n",
+	"Пользователей: %d, счетов: %d, событий журнала: %d\n":                        "Users: %d, accounts: %d, ledger events: %d\n",
+	"Пропущены существующие счета: %s\n":                                          "Existing accounts skipped: %s\n",
+	"Уже существуют: %s\n":                                                        "Already exist: %s\n",
+	"Имя занято другим пользователем: %s\n":                                       "Name is taken by another user: %s\n",
+	"Ошибка при сторнировании: %v\n":                                              "Reversal failed: %v\n",
+	"Транзакция %s сторнирована\n":                                                "Transaction %s reversed\n",
+	"Остаток %.2f. Куда перевести (ID счета или псевдоним): ":                     "Remaining balance %.2f. Move it to (account ID or alias): ",
+	"Ошибка при закрытии счета: %v\n":                                             "Failed to close account: %v\n",
+	"Счет %s закрыт\n":                                                            "Account %s closed\n",
+	"Дата (ГГГГ-ММ-ДД, Enter - сегодня): ":                                        "Date (YYYY-MM-DD, Enter - today): ",
+	"Ошибка при формировании отчета: %v\n":                                        "Failed to build report: %v\n",
+	"\n--- Кассовый отчет за %s ---\n":                                            "\n--- Cash report for %s ---\n",
+	"Поступило наличными: %.2f (операций: %d)\n":                                  "Cash in: %.2f (operations: %d)\n",
+	"Выдано наличными: %.2f (операций: %d)\n":                                     "Cash out: %.2f (operations: %d)\n",
+	"Итого по кассе: %.2f\n":                                                      "Cash net: %.2f\n",
+	"Введите ID счета или псевдоним: ":                                            "Enter account ID or alias: ",
+	"Введите ID счета или псевдоним получателя: ":                                 "Enter recipient account ID or alias: ",
+	"Введите сумму: ":                                                             "Enter amount: ",
+	"Отправитель: ":                                                               "Sender: ",
+	"Назначение платежа: ":                                                        "Payment reference: ",
+	"Платеж %s зачислен на счет %s\n":                                             "Payment %s credited to account %s\n",
+	"Платеж %s ожидает подтверждения владельца счета\n":                           "Payment %s is awaiting the account owner's confirmation\n",
+	"Пользователь (Enter - любой): ":                                              "User (Enter - any): ",
+	"ID счета (Enter - любой): ":                                                  "Account ID (Enter - any): ",
+	"Только неуспешные попытки? (y/n): ":                                          "Failed attempts only? (y/n): ",
+	"Виды событий: TRANSACTION, STATUS, LIMIT, DISPUTE, AUDIT":                    "Event kinds: TRANSACTION, STATUS, LIMIT, DISPUTE, AUDIT",
+	"Виды событий через запятую (Enter - все): ":                                  "Event kinds, comma-separated (Enter - all): ",
+	"События не найдены":                                                          "No events found",
+	"\n--- Хронология счета %s ---\n":                                             "\n--- Account %s timeline ---\n",
+	"неизвестный вид события хронологии":                                          "unknown timeline event kind",
+	"Пользовательские типы транзакций не настроены":                               "No custom transaction types configured",
+	"Доступные типы:":                                                             "Available types:",
+	"Тип транзакции: ":                                                            "Transaction type: ",
+	"Комментарий: ":                                                               "Comment: ",
+	"Транзакция %s проведена по счету %s\n":                                       "Transaction %s posted to account %s\n",
+	"Суммы ног со знаком: плюс - зачисление, минус - списание.":                   "Signed leg amounts: plus credits, minus debits.",
+	"Пустой ID счета завершает ввод.":                                             "An empty account ID ends the input.",
+	"ID счета или псевдоним: ":                                                    "Account ID or alias: ",
+	"Код основания: ":                                                             "Reason code: ",
+	"Сумма: ":                                                                     "Amount: ",
+	"Проводка по %d счетам выполнена\n":                                           "Posting to %d accounts completed\n",
+	"Формат (jsonl/cef): ":                                                        "Format (jsonl/cef): ",
+	"Выгрузить записи после номера (Enter - все): ":                               "Export entries after number (Enter - all): ",
+	"Некорректный номер записи":                                                   "Invalid entry number",
+	"Журнал аудита выгружен в %s, последняя запись %d\n":                          "Audit log exported to %s, last entry %d\n",
+	"Итоги по группам:\n":                                                         "Totals by group:\n",
+	"некорректное описание типа транзакции":                                       "invalid transaction type definition",
+	"Записи не найдены":                                                           "No entries found",
+	"\n--- Журнал аудита (%d-%d из %d) ---\n":                                     "\n--- Audit log (%d-%d of %d) ---\n",
+	"успешно":    "success",
+	"ошибка: %s": "error: %s",
+	"Счет по умолчанию %s. Введите PIN-код (Enter - пропустить): ":           "Default account %s. Enter PIN (Enter - skip): ",
+	"Продолжить работу со счетом %s? Введите PIN-код (Enter - пропустить): ": "Continue with account %s? Enter PIN (Enter - skip): ",
+	"Настройки":                                  "Settings",
+	"Язык: %s\n":                                 "Language: %s\n",
+	"Формат дат: %s (%s)\n":                      "Date format: %s (%s)\n",
+	"Счет по умолчанию: %s\n":                    "Default account: %s\n",
+	"Цвет: %s\n":                                 "Color: %s\n",
+	"Формат выписки: %s\n":                       "Statement format: %s\n",
+	"Язык (%s, \"-\" - язык приложения): ":       "Language (%s, \"-\" - application language): ",
+	"Формат дат (iso/ru/us): ":                   "Date format (iso/ru/us): ",
+	"Счет по умолчанию (ID, \"-\" - сбросить): ": "Default account (ID, \"-\" - clear): ",
+	"Цвет (вкл/выкл): ":                          "Color (on/off): ",
+	"вкл":                                        "on",
+	"Формат выписки (csv/json): ":                "Statement format (csv/json): ",
+	"Настройки сохранены":                        "Settings saved",
+	"выкл":                                       "off",
+	"Псевдонимы счета":                           "Account aliases",
+	"Псевдонимов нет":                            "No aliases",
+	"1. Привязать псевдоним или телефон":         "1. Link an alias or phone number",
+	"2. Отвязать псевдоним":                      "2. Unlink an alias",
+	"3. Переименовать псевдоним":                 "3. Rename an alias",
+	"4. История изменений":                       "4. Change history",
+	"Псевдоним (латиница, 3-32 символа) или телефон: ": "Alias (Latin letters, 3-32 characters) or phone: ",
+	"Псевдоним %s привязан к счету\n":                  "Alias %s linked to the account\n",
+	"Псевдоним или телефон: ":                          "Alias or phone: ",
+	"Текущий псевдоним: ":                              "Current alias: ",
+	"Новый псевдоним: ":                                "New alias: ",
+	"Псевдоним переименован в %s\n":                    "Alias renamed to %s\n",
+	"Псевдоним отвязан":                                "Alias unlinked",
+	"%s (%d байт)":                                     "%s (%d bytes)",
+	"Выписка по счету:\n":                              "Account statement:\n",
+	"на проверке":                                      "under review",
+	"сторнирована операцией %s":                        "reversed by %s",
+	"сторно операции %s":                               "reversal of %s",
+	"скорректирована операцией %s":                     "corrected by %s",
+	"корректировка комиссии %s":                        "correction of fee %s",
+	"    вложение %s: %s\n":                            "    attachment %s: %s\n",
+	"Текущий баланс: %.2f\n":                           "Current balance: %.2f\n",
+	"Мини-выписка %s\n":                                "Mini statement %s\n",
+	"Баланс: %.2f\n":                                   "Balance: %.2f\n",
+	"недостаточно средств на счете":                    "insufficient funds",
+	"некорректная сумма (отрицательная или нулевая)":   "invalid amount (negative or zero)",
+	"счет не найден":                                   "account not found",
+	"попытка перевода на тот же счёт":                  "cannot transfer to the same account",
+	"сумма проводки не равна нулю":                     "entry amounts do not sum to zero",
+	"не указан код причины проводки":                   "entry reason code is missing",
+	"проводка не содержит записей":                     "entry has no lines",
+	"операция не разрешена":                            "operation not permitted",
+	"неподдерживаемый формат экспорта":                 "unsupported export format",
+	"неверный PIN-код":                                 "invalid PIN",
+	"PIN-код должен состоять из 4-6 цифр":              "PIN must be 4-6 digits",
+	"для счета не установлен PIN-код":                  "no PIN is set for the account",
+	"счет временно заблокирован из-за неверных попыток ввода PIN-кода": "account temporarily locked after failed PIN attempts",
+	"счет заморожен":      "account is frozen",
+	"счет уже существует": "account already exists",
+	"счет закрыт":         "account is closed",
+	"на счете остались средства или задолженность":       "account still has a balance or debt",
+	"неизвестный источник пополнения":                    "unknown deposit source",
+	"входящий платеж не найден":                          "incoming payment not found",
+	"входящий платеж уже обработан":                      "incoming payment already processed",
+	"хранилище закрыто":                                  "storage is closed",
+	"некорректные настройки отображения":                 "invalid display settings",
+	"недопустимая смена статуса счета":                   "invalid account status change",
+	"пользователь не найден":                             "user not found",
+	"пользователь с таким именем уже существует":         "a user with this name already exists",
+	"неверное имя пользователя или пароль":               "invalid username or password",
+	"пароль должен содержать не менее 6 символов":        "password must be at least 6 characters",
+	"доступ запрещен":                                    "access denied",
+	"снимок баланса не найден":                           "balance snapshot not found",
+	"операция заблокирована по результатам оценки риска": "operation blocked by risk assessment",
+	"транзакция не найдена":                              "transaction not found",
+	"вложение не найдено":                                "attachment not found",
+	"вложение слишком большое":                           "attachment is too large",
+	"хранилище вложений не настроено":                    "attachment storage is not configured",
+	"объект в хранилище вложений не найден":              "object not found in attachment storage",
+	"превышен лимит овердрафта":                          "overdraft limit exceeded",
+	"превышен дневной лимит операций":                    "daily operation limit exceeded",
+	"транзакция уже сторнирована":                        "transaction already reversed",
+	"транзакцию этого типа нельзя сторнировать":          "transactions of this type cannot be reversed",
+	"некорректная конфигурация":                          "invalid configuration",
+	"неподдерживаемый язык интерфейса":                   "unsupported interface language",
+	"некорректный псевдоним или номер телефона":          "invalid alias or phone number",
+	"псевдоним уже занят":                                "alias is already taken",
+	"некорректное выражение правила":                     "invalid rule expression",
+	"неподдерживаемая версия резервной копии":            "unsupported backup version",
+	"контрольная сумма резервной копии не совпадает":     "backup checksum mismatch",
+	"имя из резервной копии занято другим пользователем": "backup user name is taken by another user",
+	"некорректный ключ шифрования выписок":               "invalid statement encryption key",
+	"доставка выписок не настроена":                      "statement delivery is not configured",
+	"некорректный флаг функциональности":                 "invalid feature flag",
+	"импорт отклонен: в файлах есть ошибки":              "import rejected: the files contain errors",
+	"в файле нет обязательной колонки":                   "required column is missing from the file",
+	"не заполнено обязательное поле":                     "required field is empty",
+	"повторяющийся идентификатор":                        "duplicate identifier",
+	"отрицательный баланс":                               "negative balance",
+	"баланс не совпадает с историей транзакций":          "balance does not match the transaction history",
+	"некорректная дата":                                  "invalid date",
+	"неизвестный тип транзакции":                         "unknown transaction type",
+	"операция запрещена правилом":                        "operation restricted by rule",
+	"операция не ожидает проверки":                       "operation is not awaiting review",
+	"операция временно приостановлена":                   "operation temporarily suspended",
+	"операции этого типа нельзя приостановить":           "operations of this type cannot be suspended",
+	"перевод, ожидающий подтверждения, не найден":        "transfer awaiting confirmation not found",
+	"перевод уже подтвержден или отменен":                "transfer already confirmed or cancelled",
+	"срок подтверждения перевода истек":                  "transfer confirmation period expired",
+	"некорректные условия доверенности":                  "invalid access grant terms",
+	"действующая доверенность не найдена":                "no active access grant found",
+	"превышен лимит переводов по доверенности":           "access grant transfer limit exceeded",
+	"некорректные условия кредита":                       "invalid loan terms",
+	"счет не является кредитным":                         "not a loan account",
+	"код проверки квитанции не совпадает":                "receipt verification code does not match",
+	"ключ подписи квитанций не задан":                    "receipt signing key is not configured",
+	"некорректный адрес электронной почты":               "invalid email address",
+	"не указан адрес для отправки выписок":               "no statement email address set",
+	"отправка почты не настроена":                        "email sending is not configured",
+	"ошибка чтения ввода":                                "failed to read input",
+	"некорректная точка продолжения выгрузки":            "invalid export checkpoint",
+	"счет в архиве":                                      "account is archived",
+	"счет не в архиве":                                   "account is not archived",
+	"не задан срок неактивности для архивации":           "inactivity period for archiving is not set",
+	"нет операции для отмены":                            "no operation to undo",
+	"время на отмену операции истекло":                   "the time to undo the operation has expired",
+	"после операции средства уже использованы":           "the funds have already been used since the operation",
+	"некорректные параметры прогона":                     "invalid simulation parameters",
+	"нарушен инвариант":                                  "invariant violated",
+	"некорректное оформление выписки":                    "invalid statement layout",
+	"срок приема платежа истек":                          "payment acceptance period expired",
+	"псевдоним не найден":                                "alias not found",
+	"некорректная разбивка по купюрам":                   "invalid note breakdown",
+	"сумма купюр не совпадает с суммой взноса":           "note total does not match the deposit amount",
+	"удержание по подозрительным купюрам не найдено":     "suspect note hold not found",
+	"подозрительные купюры уже проверены":                "suspect notes already verified",
+	"некорректная категория":                             "invalid category",
+	"некорректный тег":                                   "invalid tag",
+	"заметка слишком длинная":                            "note is too long",
+	"счет нельзя привязать как дочерний":                 "account cannot be linked as a child",
+	"счет не привязан как дочерний":                      "account is not linked as a child",
+	"категория запрещена родительским контролем":         "category blocked by parental controls",
+	"превышен дневной лимит, установленный родителем":    "daily limit set by the parent exceeded",
+	"перевод остатков на пул-счет не настроен":           "sweep to the pool account is not configured",
+	"счет был изменен другой операцией":                  "the account was modified by another operation",
+	"недостаточно средств для доначисления":              "insufficient funds to collect",
+	"плата за период уже списана":                        "fee for the period already charged",
+	"плата не предусмотрена":                             "no fee applies",
+	"недостаточно средств":                               "insufficient funds",
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// IDGenerator генерирует идентификаторы счетов, транзакций и других сущностей.
+// Префикс указывает вид сущности (ACC, TX, TR и т.д.).
+type IDGenerator interface {
+	NewID(prefix string) string
+}
+
+// UUIDv7Generator генерирует идентификаторы на основе UUIDv7: 48 бит
+// времени в миллисекундах и 74 случайных бита, что исключает совпадения
+// при создании нескольких сущностей в один момент
+type UUIDv7Generator struct{}
+
+// DefaultIDGenerator генератор идентификаторов по умолчанию
+var DefaultIDGenerator IDGenerator = UUIDv7Generator{}
+
+// NewID возвращает префикс и новый UUIDv7
+func (UUIDv7Generator) NewID(prefix string) string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixMilli())<<16)
+	rand.Read(b[6:])
+
+	b[6] = b[6]&0x0f | 0x70 // версия 7
+	b[8] = b[8]&0x3f | 0x80 // вариант RFC 9562
+
+	return fmt.Sprintf("%s%x-%x-%x-%x-%x", prefix, b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
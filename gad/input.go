@@ -0,0 +1,36 @@
+package app
+
+import "bankapp/interfaces"
+
+// lineInput отслеживает конец ввода. Меню не проверяют результат Scan:
+// после конца ввода Text возвращает пустую строку, текущее действие
+// завершается как при пустом ответе, а главный цикл останавливается.
+type lineInput struct {
+	source interfaces.InputSource
+	closed bool
+	err    error
+}
+
+// Scan читает следующую строку
+func (in *lineInput) Scan() bool {
+	if in.closed {
+		return false
+	}
+
+	if in.source.Scan() {
+		return true
+	}
+
+	in.closed = true
+	in.err = in.source.Err()
+	return false
+}
+
+// Text возвращает последнюю прочитанную строку
+func (in *lineInput) Text() string {
+	if in.closed {
+		return ""
+	}
+
+	return in.source.Text()
+}
@@ -1,19 +1,246 @@
-package interfaces
-
-import "bankapp/models"
-
-// AccountService - основной интерфейс для работы со счетом
-type AccountService interface {
-	Deposit(amount float64) error
-	Withdraw(amount float64) error
-	Transfer(to *models.Account, amount float64) error
-	GetBalance() float64
-	GetStatement() string
-}
-
-// Storage - интерфейс для работы с хранилищем данных
-type Storage interface {
-	SaveAccount(account *models.Account) error
-	LoadAccount(accountID string) (*models.Account, error)
-	GetAllAccounts() ([]*models.Account, error)
-}
+package interfaces
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"bankapp/models"
+)
+
+// AccountService - основной интерфейс для работы со счетом
+type AccountService interface {
+	Deposit(ctx context.Context, amount float64, source models.DepositSource) (models.OperationResult, error)
+	Withdraw(ctx context.Context, amount float64) (models.OperationResult, error)
+	Transfer(ctx context.Context, to *models.Account, amount float64) (models.OperationResult, error)
+	AccountID() string
+	GetBalance(ctx context.Context) float64
+	GetBalanceAt(ctx context.Context, at time.Time) (float64, error)
+	GetBalanceHistory(ctx context.Context, from, to time.Time) ([]models.BalancePoint, error)
+	GetStatement(ctx context.Context) string
+	GetMiniStatement(ctx context.Context, count int) string
+	ExportStatement(ctx context.Context, format models.ExportFormat, w io.Writer) error
+	BuildStatement(ctx context.Context, from, to time.Time) models.Statement
+	ListTransactions(ctx context.Context, offset, limit int) ([]models.Transaction, int, error)
+	ChangePIN(ctx context.Context, oldPIN, newPIN string) error
+	AttachFile(ctx context.Context, transactionID, name string, data []byte) (models.Attachment, error)
+	AttachReference(ctx context.Context, transactionID, reference string) (models.Attachment, error)
+	GetAttachment(ctx context.Context, transactionID, attachmentID string) (models.Attachment, []byte, error)
+	GetDailyAllowance(ctx context.Context) models.DailyAllowance
+	Reverse(ctx context.Context, transactionID string) error
+	UndoLast(ctx context.Context) (models.Transaction, error)
+	ListPendingCredits(ctx context.Context) []models.PendingCredit
+	AcceptCredit(ctx context.Context, creditID string) error
+	RejectCredit(ctx context.Context, creditID string) error
+	SetAutoAcceptCredits(ctx context.Context, enabled bool) error
+	DepositCash(ctx context.Context, notes []models.CashNote) (models.OperationResult, error)
+	EditTransactionDetails(ctx context.Context, transactionID string, details models.TransactionDetails) error
+	FilterTransactions(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, int, error)
+	SpendingByCategory(ctx context.Context, from, to time.Time) []models.CategorySpending
+	LinkChild(ctx context.Context, childID, childPIN string) error
+	UnlinkChild(ctx context.Context, childID string) error
+	SetChildControls(ctx context.Context, childID string, controls models.SpendingControls) error
+	ListChildren(ctx context.Context) ([]models.ChildAccount, error)
+	SetStatementKey(ctx context.Context, publicKey []byte) error
+	HasStatementKey() bool
+	DeliverStatement(ctx context.Context, format models.ExportFormat) (models.StatementDelivery, error)
+	GetAvailableBalance(ctx context.Context) float64
+	InitiateTransfer(ctx context.Context, to *models.Account, amount float64) (models.PendingTransfer, error)
+	ConfirmTransfer(ctx context.Context, transferID string) (models.OperationResult, error)
+	CancelTransfer(ctx context.Context, transferID string) error
+	ListPendingTransfers(ctx context.Context) []models.PendingTransfer
+	GrantAccess(ctx context.Context, username string, scope models.GrantScope, transferLimit float64, expiresAt time.Time) (models.AccessGrant, error)
+	RevokeAccess(ctx context.Context, grantID string) error
+	ListAccessGrants(ctx context.Context) []models.AccessGrant
+	GetLoanSummary(ctx context.Context) (models.LoanSummary, error)
+	GetLoanSchedule(ctx context.Context) ([]models.LoanPayment, error)
+	PreviewDeposit(ctx context.Context, amount float64, source models.DepositSource) (models.OperationPreview, error)
+	PreviewWithdraw(ctx context.Context, amount float64) (models.OperationPreview, error)
+	PreviewTransfer(ctx context.Context, to *models.Account, amount float64) (models.OperationPreview, error)
+	GetReceipt(ctx context.Context, transactionID string) (models.Receipt, error)
+	SetStatementEmail(ctx context.Context, email string, monthly bool) error
+	EmailStatement(ctx context.Context, from, to time.Time, html bool) error
+	ExportChunk(ctx context.Context, format models.ExportFormat, checkpoint string, limit int, w io.Writer) (models.ExportChunk, error)
+}
+
+// Storage - интерфейс для работы с хранилищем данных
+type Storage interface {
+	SaveAccount(ctx context.Context, account *models.Account) error
+	LoadAccount(ctx context.Context, accountID string) (*models.Account, error)
+	GetAllAccounts(ctx context.Context) ([]*models.Account, error)
+	ListAccounts(ctx context.Context, offset, limit int) ([]*models.Account, int, error)
+	ListAccountsByScope(ctx context.Context, scope models.AccountScope, offset, limit int) ([]*models.Account, int, error)
+	SaveUser(ctx context.Context, user *models.User) error
+	LoadUser(ctx context.Context, userID string) (*models.User, error)
+	FindUserByUsername(ctx context.Context, username string) (*models.User, error)
+	GetAllUsers(ctx context.Context) ([]*models.User, error)
+}
+
+// ClosableStorage - хранилище, которому нужно записать буферы и освободить
+// ресурсы при завершении приложения
+type ClosableStorage interface {
+	Close(ctx context.Context) error
+}
+
+// BatchStorage - хранилище, умеющее атомарно сохранить несколько счетов
+type BatchStorage interface {
+	SaveAccounts(ctx context.Context, accounts ...*models.Account) error
+}
+
+// JournalStorage - хранилище, которое держит журнал событий, псевдонимы
+// и журнал аудита вместе со счетами, чтобы они переживали перезапуск
+type JournalStorage interface {
+	Ledger() LedgerStorage
+	Aliases() AliasStorage
+	AuditLog() AuditStorage
+}
+
+// LedgerService - административный интерфейс для прямых проводок по счетам
+type LedgerService interface {
+	PostEntries(ctx context.Context, entries []models.LedgerEntry) error
+}
+
+// SearchService - интерфейс поиска транзакций по всем счетам
+type SearchService interface {
+	SearchTransactions(ctx context.Context, filter models.TransactionFilter) ([]models.TransactionSearchResult, int, error)
+}
+
+// AnalyticsService - интерфейс аналитических сводок по счетам
+type AnalyticsService interface {
+	GetSummary(ctx context.Context, accountID string, period models.Period) (models.AccountSummary, error)
+}
+
+// LedgerStorage - журнал событий счетов, являющийся источником истины для балансов
+type LedgerStorage interface {
+	AppendEvent(ctx context.Context, event *models.AccountEvent) error
+	LoadEvents(ctx context.Context, accountID string, afterSequence uint64) ([]models.AccountEvent, error)
+	SaveSnapshot(ctx context.Context, snapshot models.BalanceSnapshot) error
+	LoadSnapshot(ctx context.Context, accountID string) (models.BalanceSnapshot, error)
+}
+
+// FeePolicy - политика расчета комиссии за операцию по счету
+type FeePolicy interface {
+	CalculateFee(ctx context.Context, account *models.Account, txType models.TransactionType, amount float64) (float64, error)
+}
+
+// BlobStore - хранилище содержимого вложений к транзакциям
+type BlobStore interface {
+	Put(ctx context.Context, name string, data []byte) (string, error)
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// FeatureFlags - флаги постепенного включения нового поведения по счетам
+type FeatureFlags interface {
+	Enabled(ctx context.Context, flag string, account *models.Account) bool
+}
+
+// StatementRenderer - оформление выписки за период для отправки клиенту
+type StatementRenderer interface {
+	Render(ctx context.Context, statement models.Statement, w io.Writer) error
+}
+
+// StatementSender - внешний канал доставки выписок клиенту
+type StatementSender interface {
+	SendStatement(ctx context.Context, delivery models.StatementDelivery) error
+}
+
+// InputSource - построчный ввод команд пользователя. *bufio.Scanner над
+// stdin подходит как есть; другие интерфейсы могут подавать строки
+// программно. Scan возвращает false в конце ввода или при ошибке, Err -
+// ошибку чтения (nil в конце ввода).
+type InputSource interface {
+	Scan() bool
+	Text() string
+	Err() error
+}
+
+// EmailSender - отправка писем клиентам, например через SMTP
+type EmailSender interface {
+	SendEmail(ctx context.Context, message models.EmailMessage) error
+}
+
+// RiskScorer - внешний сервис оценки риска операций.
+// Возвращает оценку от 0 (безопасно) до 1 (мошенничество).
+type RiskScorer interface {
+	Score(ctx context.Context, request models.RiskRequest) (float64, error)
+}
+
+// AuthService - интерфейс регистрации и входа пользователей
+type AuthService interface {
+	Register(ctx context.Context, username, password string) (*models.User, error)
+	Login(ctx context.Context, username, password string) (*models.User, error)
+	SavePreferences(ctx context.Context, userID string, prefs models.UserPreferences) error
+	SaveLastAccount(ctx context.Context, userID, accountID string) error
+}
+
+// Observer - подписчик на уведомления о событиях по счетам
+type Observer interface {
+	Notify(ctx context.Context, notification models.Notification) error
+}
+
+// OutboxStorage - журнал исходящих уведомлений для повторной доставки
+type OutboxStorage interface {
+	SaveOutboxRecord(ctx context.Context, record models.OutboxRecord) error
+	ListOutboxRecords(ctx context.Context, filter models.OutboxFilter) ([]models.OutboxRecord, error)
+}
+
+// AuditLogger - журнал аудита действий пользователей
+type AuditLogger interface {
+	Record(ctx context.Context, entry models.AuditEntry) error
+	Query(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, int, error)
+}
+
+// AuditStorage - хранилище журнала аудита: записи только добавляются
+type AuditStorage interface {
+	AppendAuditEntry(ctx context.Context, entry *models.AuditEntry) error
+	QueryAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, int, error)
+}
+
+// AliasStorage - индекс псевдонимов счетов и история их изменений
+type AliasStorage interface {
+	SaveAlias(ctx context.Context, alias models.AccountAlias) error
+	DeleteAlias(ctx context.Context, alias string) error
+	LoadAlias(ctx context.Context, alias string) (models.AccountAlias, error)
+	ListAliases(ctx context.Context, accountID string) ([]models.AccountAlias, error)
+	AppendAliasChange(ctx context.Context, change models.AliasChange) error
+	ListAliasChanges(ctx context.Context, accountID string) ([]models.AliasChange, error)
+}
+
+// AliasService - привязка псевдонимов и номеров телефонов к счетам
+type AliasService interface {
+	LinkAlias(ctx context.Context, accountID, alias string) (models.AccountAlias, error)
+	UnlinkAlias(ctx context.Context, accountID, alias string) error
+	RenameAlias(ctx context.Context, accountID, oldAlias, newAlias string) (models.AccountAlias, error)
+	Resolve(ctx context.Context, reference string) (*models.Account, error)
+	ListAliases(ctx context.Context, accountID string) ([]models.AccountAlias, error)
+	History(ctx context.Context, accountID string) ([]models.AliasChange, error)
+}
+
+// OperationSwitches - приостановка отдельных видов операций для всех счетов
+type OperationSwitches interface {
+	Disable(ctx context.Context, operation models.TransactionType, message string) error
+	Enable(ctx context.Context, operation models.TransactionType) error
+	Check(ctx context.Context, operation models.TransactionType) error
+	List(ctx context.Context) []models.OperationSwitch
+}
+
+// AdminService - административные операции над счетами
+type AdminService interface {
+	FreezeAccount(ctx context.Context, accountID string) error
+	UnfreezeAccount(ctx context.Context, accountID string) error
+	CloseAccount(ctx context.Context, accountID, transferTo string) error
+	SetOverdraftLimit(ctx context.Context, accountID string, limit float64) error
+	SetDailyLimits(ctx context.Context, accountID string, amountLimit float64, countLimit int) error
+	SetAccountNotes(ctx context.Context, accountID, notes string) error
+	SetRelationshipManager(ctx context.Context, accountID, manager string) error
+	SweepDormantBalances(ctx context.Context, policy models.SweepPolicy, dryRun bool) (models.SweepReport, error)
+	GetAccountTimeline(ctx context.Context, accountID string, kinds ...models.TimelineKind) ([]models.TimelineEntry, error)
+	ListFlaggedTransactions(ctx context.Context) ([]models.TransactionSearchResult, error)
+	ResolveFlaggedTransaction(ctx context.Context, accountID, transactionID string, approve bool) error
+	CreateLoan(ctx context.Context, repaymentAccountID string, principal, annualRate float64, termMonths int) (*models.Account, error)
+	PostLoanRepayments(ctx context.Context, asOf time.Time) (models.LoanRepaymentReport, error)
+	VerifyHistory(ctx context.Context, accountID string) (models.HistoryReport, error)
+	ArchiveDormantAccounts(ctx context.Context, policy models.ArchivePolicy, dryRun bool) (models.ArchiveReport, error)
+	ListArchivedAccounts(ctx context.Context) ([]*models.Account, error)
+	UnarchiveAccount(ctx context.Context, accountID string) error
+}
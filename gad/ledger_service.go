@@ -0,0 +1,155 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+type operatorKey struct{}
+
+// WithOperator возвращает контекст с именем оператора бэк-офиса
+func WithOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+// OperatorFromContext возвращает имя оператора из контекста
+func OperatorFromContext(ctx context.Context) (string, bool) {
+	operator, ok := ctx.Value(operatorKey{}).(string)
+	return operator, ok && operator != ""
+}
+
+// LedgerServiceImpl реализация LedgerService
+type LedgerServiceImpl struct {
+	storage   interfaces.Storage
+	ledger    interfaces.LedgerStorage
+	ids       models.IDGenerator
+	clock     models.Clock
+	operators map[string]bool
+	audit     interfaces.AuditLogger
+}
+
+// NewLedgerService создает сервис проводок, доступный только перечисленным операторам.
+// Каждая нога проводки, в том числе отклоненной, записывается в журнал аудита.
+func NewLedgerService(storage interfaces.Storage, ledger interfaces.LedgerStorage, ids models.IDGenerator, clock models.Clock, audit interfaces.AuditLogger, operators ...string) interfaces.LedgerService {
+	allowed := make(map[string]bool, len(operators))
+	for _, operator := range operators {
+		allowed[strings.TrimSpace(operator)] = true
+	}
+
+	return &LedgerServiceImpl{
+		storage:   storage,
+		ledger:    ledger,
+		ids:       ids,
+		clock:     clock,
+		operators: allowed,
+		audit:     audit,
+	}
+}
+
+// PostEntries проводит сбалансированную многоногую проводку.
+// Все счета загружаются и проверяются до изменения балансов, а затем
+// сохраняются одной пачкой.
+func (s *LedgerServiceImpl) PostEntries(ctx context.Context, entries []models.LedgerEntry) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	operator, ok := OperatorFromContext(ctx)
+	var postingID string
+	defer func() {
+		s.auditPosting(ctx, operator, postingID, entries, err)
+	}()
+
+	if !ok || !s.operators[operator] {
+		return errors.ErrUnauthorized
+	}
+
+	if len(entries) == 0 {
+		return errors.ErrEmptyEntries
+	}
+
+	var sum float64
+	accounts := make(map[string]*models.Account)
+	ordered := make([]*models.Account, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Amount == 0 || math.IsNaN(entry.Amount) || math.IsInf(entry.Amount, 0) {
+			return errors.ErrInvalidAmount
+		}
+
+		if strings.TrimSpace(entry.ReasonCode) == "" {
+			return errors.ErrMissingReasonCode
+		}
+
+		if _, loaded := accounts[entry.AccountID]; !loaded {
+			account, err := s.storage.LoadAccount(ctx, entry.AccountID)
+			if err != nil {
+				return err
+			}
+			accounts[entry.AccountID] = account
+			ordered = append(ordered, account)
+		}
+
+		sum += entry.Amount
+	}
+
+	// Суммы сравниваются в копейках, чтобы не зависеть от ошибок округления
+	if math.Round(sum*100) != 0 {
+		return errors.ErrUnbalancedEntries
+	}
+
+	postingID = s.ids.NewID("LP")
+	now := s.clock.Now()
+	events := make([]models.AccountEvent, 0, len(entries))
+	for _, entry := range entries {
+		account := accounts[entry.AccountID]
+
+		transaction := models.Transaction{
+			ID:        s.ids.NewID("TX"),
+			Type:      models.LedgerTransaction,
+			Amount:    entry.Amount,
+			Timestamp: now,
+			Message:   strings.TrimSpace(fmt.Sprintf("Проводка %s [%s] %s", postingID, entry.ReasonCode, entry.Memo)),
+			Direction: models.CreditEntry,
+		}
+		if entry.Amount < 0 {
+			transaction.Direction = models.DebitEntry
+		}
+
+		events = append(events, newEvent(account.ID, models.AdjustmentEvent, entry.Amount, transaction.ID, transaction.Timestamp))
+		account.Balance += entry.Amount
+		account.Transactions = append(account.Transactions, transaction)
+	}
+
+	if err := saveAccounts(ctx, s.storage, ordered...); err != nil {
+		return err
+	}
+
+	return recordEvents(ctx, s.ledger, events)
+}
+
+// auditPosting записывает в журнал аудита каждую ногу проводки с ее результатом.
+// Отклоненная проводка без ног оставляет одну запись без счета.
+func (s *LedgerServiceImpl) auditPosting(ctx context.Context, operator, postingID string, entries []models.LedgerEntry, err error) {
+	if len(entries) == 0 {
+		recordAudit(ctx, s.audit, models.AuditEntry{
+			Actor:  operator,
+			Action: "ledger_posting",
+		}, err)
+		return
+	}
+
+	for _, entry := range entries {
+		recordAudit(ctx, s.audit, models.AuditEntry{
+			Actor:     operator,
+			Action:    "ledger_posting",
+			AccountID: entry.AccountID,
+			Amount:    entry.Amount,
+			Details:   strings.TrimSpace(fmt.Sprintf("%s [%s] %s", postingID, entry.ReasonCode, entry.Memo)),
+		}, err)
+	}
+}
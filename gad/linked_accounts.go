@@ -0,0 +1,182 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// LinkChild привязывает счет childID как дочерний. PIN-код дочернего счета
+// подтверждает, что владелец родительского счета распоряжается им.
+func (s *AccountServiceImpl) LinkChild(ctx context.Context, childID, childPIN string) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "link_child", 0, "дочерний счет "+childID, err)
+	}()
+
+	if childID == s.account.ID || s.account.ParentID != "" {
+		return errors.ErrInvalidLink
+	}
+
+	child, err := s.storage.LoadAccount(ctx, childID)
+	if err != nil {
+		return err
+	}
+
+	if child.ParentID != "" || child.Status == models.ClosedStatus {
+		return errors.ErrInvalidLink
+	}
+
+	if err := Authenticate(ctx, s.storage, s.clock, child, childPIN); err != nil {
+		return err
+	}
+
+	// Дочерний счет не может сам быть родителем
+	children, err := s.loadChildren(ctx, childID)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return errors.ErrInvalidLink
+	}
+
+	child.ParentID = s.account.ID
+	return s.storage.SaveAccount(ctx, child)
+}
+
+// UnlinkChild снимает привязку дочернего счета вместе с ограничениями
+func (s *AccountServiceImpl) UnlinkChild(ctx context.Context, childID string) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "unlink_child", 0, "дочерний счет "+childID, err)
+	}()
+
+	child, err := s.loadChild(ctx, childID)
+	if err != nil {
+		return err
+	}
+
+	child.ParentID = ""
+	child.SpendingControls = models.SpendingControls{}
+	return s.storage.SaveAccount(ctx, child)
+}
+
+// SetChildControls задает дочернему счету дневной потолок списаний и запрещенные категории
+func (s *AccountServiceImpl) SetChildControls(ctx context.Context, childID string, controls models.SpendingControls) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "set_child_controls", controls.DailyCap, "дочерний счет "+childID, err)
+	}()
+
+	if controls.DailyCap < 0 {
+		return errors.ErrInvalidAmount
+	}
+
+	var blocked []string
+	for _, category := range controls.BlockedCategories {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if category == "" || slices.Contains(blocked, category) {
+			continue
+		}
+		if utf8.RuneCountInString(category) > maxCategoryLength {
+			return errors.ErrInvalidCategory
+		}
+		blocked = append(blocked, category)
+	}
+	controls.BlockedCategories = blocked
+
+	child, err := s.loadChild(ctx, childID)
+	if err != nil {
+		return err
+	}
+
+	child.SpendingControls = controls
+	return s.storage.SaveAccount(ctx, child)
+}
+
+// ListChildren возвращает дочерние счета с ограничениями и тратами за сегодня
+func (s *AccountServiceImpl) ListChildren(ctx context.Context) ([]models.ChildAccount, error) {
+	children, err := s.loadChildren(ctx, s.account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.ChildAccount, 0, len(children))
+	for _, child := range children {
+		spent, _ := outgoingToday(child, s.now())
+		result = append(result, models.ChildAccount{
+			AccountID:  child.ID,
+			OwnerName:  child.OwnerName,
+			Controls:   child.SpendingControls,
+			SpentToday: spent,
+		})
+	}
+
+	return result, nil
+}
+
+// checkSpendingControls проверяет списание дочернего счета по ограничениям
+// родителя; о нарушении уведомляется родительский счет
+func (s *AccountServiceImpl) checkSpendingControls(ctx context.Context, amount float64, details models.TransactionDetails) error {
+	err := s.spendingControlsError(amount, details)
+	if err != nil && s.events != nil {
+		s.events.Publish(ctx, models.Notification{
+			ID:             s.newID("EV"),
+			Type:           models.SpendingBlockedNotification,
+			AccountID:      s.account.ParentID,
+			CounterpartyID: s.account.ID,
+			Amount:         amount,
+			Reason:         err.Error(),
+		})
+	}
+
+	return err
+}
+
+// spendingControlsError проверяет ограничения родителя без уведомления
+func (s *AccountServiceImpl) spendingControlsError(amount float64, details models.TransactionDetails) error {
+	if s.account.ParentID == "" {
+		return nil
+	}
+
+	controls := s.account.SpendingControls
+	if details.Category != "" && slices.Contains(controls.BlockedCategories, details.Category) {
+		return errors.ErrCategoryBlocked
+	}
+	if used, _ := outgoingToday(s.account, s.now()); controls.DailyCap > 0 && used+amount > controls.DailyCap {
+		return errors.ErrParentLimitExceeded
+	}
+
+	return nil
+}
+
+// loadChild загружает счет и проверяет, что он привязан к текущему как дочерний
+func (s *AccountServiceImpl) loadChild(ctx context.Context, childID string) (*models.Account, error) {
+	child, err := s.storage.LoadAccount(ctx, childID)
+	if err != nil {
+		return nil, err
+	}
+
+	if child.ParentID != s.account.ID {
+		return nil, errors.ErrNotLinkedChild
+	}
+
+	return child, nil
+}
+
+// loadChildren возвращает счета, привязанные к parentID как дочерние
+func (s *AccountServiceImpl) loadChildren(ctx context.Context, parentID string) ([]*models.Account, error) {
+	accounts, err := s.storage.GetAllAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var children []*models.Account
+	for _, account := range accounts {
+		if account.ParentID == parentID {
+			children = append(children, account)
+		}
+	}
+
+	return children, nil
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"bankapp/models"
+)
+
+// manageChildren показывает дочерние счета и позволяет привязать счет,
+// задать ему ограничения трат или снять привязку
+func (app *BankApp) manageChildren(ctx context.Context) {
+	children, err := app.currentAccount.ListChildren(ctx)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printHeader("Детские счета")
+	if len(children) == 0 {
+		app.println("Дочерних счетов нет")
+	}
+	for _, child := range children {
+		app.printf("%s | %s | потрачено сегодня: %.2f\n", child.AccountID, child.OwnerName, child.SpentToday)
+		if child.Controls.DailyCap > 0 {
+			app.printf("    дневной лимит: %.2f\n", child.Controls.DailyCap)
+		}
+		if len(child.Controls.BlockedCategories) > 0 {
+			app.printf("    запрещенные категории: %s\n", strings.Join(child.Controls.BlockedCategories, ", "))
+		}
+	}
+
+	app.println("1. Привязать дочерний счет")
+	app.println("2. Ограничения дочернего счета")
+	app.println("3. Отвязать дочерний счет")
+	app.println("4. Назад")
+	app.print("Выберите опцию: ")
+	app.scanner.Scan()
+
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "1":
+		childID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+		if err := app.currentAccount.LinkChild(ctx, childID, app.readLine("PIN-код дочернего счета: ")); err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+		app.printf("Счет %s привязан как дочерний\n", childID)
+	case "2":
+		childID := app.readAccountID(ctx, "Введите ID счета или псевдоним: ")
+
+		var controls models.SpendingControls
+		if controls.DailyCap, err = app.readOptionalAmount("Дневной лимит трат (Enter - без ограничения): "); err != nil {
+			return
+		}
+		controls.BlockedCategories = strings.Split(app.readLine("Запрещенные категории через запятую (Enter - нет): "), ",")
+
+		if err := app.currentAccount.SetChildControls(ctx, childID, controls); err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+		app.println("Готово")
+	case "3":
+		if err := app.currentAccount.UnlinkChild(ctx, app.readAccountID(ctx, "Введите ID счета или псевдоним: ")); err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+		app.println("Готово")
+	}
+}
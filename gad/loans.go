@@ -0,0 +1,241 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// maxLoanTermMonths наибольший срок кредита
+const maxLoanTermMonths = 360
+
+// AmortizationSchedule строит график аннуитетных платежей: ежемесячный платеж
+// одинаков, последний платеж выравнивается так, чтобы долг погашался до копейки
+func AmortizationSchedule(loan models.Loan) []models.LoanPayment {
+	rate := loan.AnnualRate / 100 / 12
+	payment := loan.Principal / float64(loan.TermMonths)
+	if rate > 0 {
+		payment = loan.Principal * rate / (1 - math.Pow(1+rate, -float64(loan.TermMonths)))
+	}
+	payment = roundCents(payment)
+
+	schedule := make([]models.LoanPayment, 0, loan.TermMonths)
+	remaining := loan.Principal
+	for number := 1; number <= loan.TermMonths; number++ {
+		interest := roundCents(remaining * rate)
+		principal := payment - interest
+		if number == loan.TermMonths || principal > remaining {
+			principal = remaining
+		}
+		remaining = roundCents(remaining - principal)
+
+		schedule = append(schedule, models.LoanPayment{
+			Number:    number,
+			DueDate:   loan.StartDate.AddDate(0, number, 0),
+			Payment:   roundCents(principal + interest),
+			Interest:  interest,
+			Principal: roundCents(principal),
+			Remaining: remaining,
+		})
+	}
+
+	return schedule
+}
+
+// roundCents округляет сумму до копеек
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// CreateLoan открывает кредитный счет владельцу текущего счета repaymentAccountID
+// и зачисляет на текущий счет сумму кредита
+func (s *AdminServiceImpl) CreateLoan(ctx context.Context, repaymentAccountID string, principal, annualRate float64, termMonths int) (loanAccount *models.Account, err error) {
+	defer func() {
+		s.auditAdmin(ctx, "create_loan", repaymentAccountID, principal,
+			fmt.Sprintf("%.2f%% на %d мес.", annualRate, termMonths), err)
+	}()
+
+	if principal <= 0 || annualRate < 0 || termMonths <= 0 || termMonths > maxLoanTermMonths {
+		return nil, errors.ErrInvalidLoan
+	}
+
+	checking, err := s.storage.LoadAccount(ctx, repaymentAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkOperable(checking); err != nil {
+		return nil, err
+	}
+
+	if checking.Loan != nil {
+		return nil, errors.ErrInvalidLoan
+	}
+
+	loanAccount = models.NewAccount(checking.OwnerName, s.ids, s.clock)
+	loanAccount.OwnerID = checking.OwnerID
+	loanAccount.Loan = &models.Loan{
+		Principal:          principal,
+		AnnualRate:         annualRate,
+		TermMonths:         termMonths,
+		StartDate:          loanAccount.CreatedAt,
+		RepaymentAccountID: checking.ID,
+	}
+
+	message := fmt.Sprintf("Выдача кредита %s на %.2f", loanAccount.ID, principal)
+	events := []models.AccountEvent{
+		s.postLoanEntry(loanAccount, models.DebitEntry, principal, message),
+		s.postLoanEntry(checking, models.CreditEntry, principal, message),
+	}
+
+	if err := saveAccounts(ctx, s.storage, loanAccount, checking); err != nil {
+		return nil, err
+	}
+
+	return loanAccount, recordEvents(ctx, s.ledger, events)
+}
+
+// PostLoanRepayments проводит все платежи по кредитам со сроком не позже asOf,
+// списывая их с текущих счетов. Если средств на платеж не хватает, платеж и
+// следующие за ним по этому кредиту пропускаются до следующего запуска.
+// Погашенный кредитный счет закрывается.
+func (s *AdminServiceImpl) PostLoanRepayments(ctx context.Context, asOf time.Time) (report models.LoanRepaymentReport, err error) {
+	defer func() {
+		s.auditAdmin(ctx, "post_loan_repayments", "", report.Total,
+			fmt.Sprintf("проведено %d, пропущено %d", report.Posted, report.Skipped), err)
+	}()
+
+	report.AsOf = asOf
+
+	accounts, err := s.storage.GetAllAccounts(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, account := range accounts {
+		if account.Loan == nil || account.Status == models.ClosedStatus {
+			continue
+		}
+
+		schedule := AmortizationSchedule(*account.Loan)
+		for _, payment := range schedule[account.Loan.PaymentsMade:] {
+			if payment.DueDate.After(asOf) {
+				break
+			}
+
+			result, err := s.postLoanRepayment(ctx, account, payment)
+			if err != nil {
+				return report, err
+			}
+
+			report.Results = append(report.Results, result)
+			if !result.Posted {
+				report.Skipped++
+				break
+			}
+
+			report.Posted++
+			report.Total += payment.Payment
+		}
+	}
+
+	return report, nil
+}
+
+// postLoanRepayment проводит один платеж графика: текущий счет оплачивает
+// проценты и основной долг, кредитный счет уменьшает задолженность на основной долг
+func (s *AdminServiceImpl) postLoanRepayment(ctx context.Context, loanAccount *models.Account, payment models.LoanPayment) (models.LoanRepaymentResult, error) {
+	result := models.LoanRepaymentResult{
+		LoanAccountID: loanAccount.ID,
+		Payment:       payment,
+	}
+
+	checking, err := s.storage.LoadAccount(ctx, loanAccount.Loan.RepaymentAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	switch {
+	case checkOperable(checking) != nil:
+		result.SkipReason = "текущий счет недоступен"
+		return result, nil
+	case payment.Payment > checking.AvailableFundsAt(s.clock.Now()):
+		result.SkipReason = "недостаточно средств"
+		return result, nil
+	}
+
+	message := fmt.Sprintf("Платеж %d по кредиту %s: основной долг %.2f, проценты %.2f",
+		payment.Number, loanAccount.ID, payment.Principal, payment.Interest)
+	events := []models.AccountEvent{
+		s.postLoanEntry(checking, models.DebitEntry, payment.Payment, message),
+		s.postLoanEntry(loanAccount, models.CreditEntry, payment.Principal, message),
+	}
+
+	loanAccount.Loan.PaymentsMade = payment.Number
+	if payment.Number == loanAccount.Loan.TermMonths {
+		changeStatus(s.ids, loanAccount, models.ClosedStatus, "кредит погашен", s.clock.Now())
+	}
+
+	if err := saveAccounts(ctx, s.storage, loanAccount, checking); err != nil {
+		return result, err
+	}
+
+	result.Posted = true
+	return result, recordEvents(ctx, s.ledger, events)
+}
+
+// postLoanEntry проводит по счету транзакцию LOAN и возвращает событие
+// журнала для записи после сохранения счета
+func (s *AdminServiceImpl) postLoanEntry(account *models.Account, direction models.EntryDirection, amount float64, message string) models.AccountEvent {
+	transaction := models.Transaction{
+		ID:        s.ids.NewID("TX"),
+		Type:      models.LoanTransaction,
+		Amount:    amount,
+		Timestamp: s.clock.Now(),
+		Message:   message,
+		Direction: direction,
+	}
+
+	account.Balance += transaction.SignedAmount()
+	account.Transactions = append(account.Transactions, transaction)
+
+	return newEvent(account.ID, models.AdjustmentEvent, transaction.SignedAmount(), transaction.ID, transaction.Timestamp)
+}
+
+// GetLoanSummary возвращает остаток основного долга и ближайший платеж по кредитному счету
+func (s *AccountServiceImpl) GetLoanSummary(ctx context.Context) (models.LoanSummary, error) {
+	loan := s.account.Loan
+	if loan == nil {
+		return models.LoanSummary{}, errors.ErrNotLoanAccount
+	}
+
+	summary := models.LoanSummary{
+		AccountID:          s.account.ID,
+		RemainingPrincipal: loan.Principal,
+		PaymentsMade:       loan.PaymentsMade,
+		TermMonths:         loan.TermMonths,
+	}
+
+	schedule := AmortizationSchedule(*loan)
+	if loan.PaymentsMade > 0 {
+		summary.RemainingPrincipal = schedule[loan.PaymentsMade-1].Remaining
+	}
+	if loan.PaymentsMade < len(schedule) {
+		next := schedule[loan.PaymentsMade]
+		summary.NextPayment = &next
+	}
+
+	return summary, nil
+}
+
+// GetLoanSchedule возвращает полный график платежей по кредитному счету
+func (s *AccountServiceImpl) GetLoanSchedule(ctx context.Context) ([]models.LoanPayment, error) {
+	if s.account.Loan == nil {
+		return nil, errors.ErrNotLoanAccount
+	}
+
+	return AmortizationSchedule(*s.account.Loan), nil
+}
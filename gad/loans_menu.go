@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"bankapp/models"
+	"bankapp/services"
+)
+
+// manageLoans показывает меню кредитов администратора
+func (app *BankApp) manageLoans(ctx context.Context) {
+	app.println("1. Выдать кредит")
+	app.println("2. Провести платежи по графику")
+	app.print("Выберите опцию: ")
+	app.scanner.Scan()
+
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "1":
+		app.createLoan(ctx)
+	case "2":
+		app.postLoanRepayments(ctx)
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+	}
+}
+
+// createLoan выдает кредит на текущий счет клиента
+func (app *BankApp) createLoan(ctx context.Context) {
+	accountID := app.readAccountID(ctx, "Текущий счет для выдачи и погашения: ")
+
+	principal, err := app.readAmount("Сумма кредита: ")
+	if err != nil {
+		return
+	}
+
+	rate, err := strconv.ParseFloat(app.readLine("Годовая ставка, %: "), 64)
+	if err != nil {
+		app.println("Некорректная ставка")
+		return
+	}
+
+	term, err := strconv.Atoi(app.readLine("Срок в месяцах: "))
+	if err != nil {
+		app.println("Некорректный срок")
+		return
+	}
+
+	loanAccount, err := app.admin.CreateLoan(ctx, accountID, principal, rate, term)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Кредит выдан, кредитный счет %s\n", loanAccount.ID)
+	app.printLoanSchedule(services.AmortizationSchedule(*loanAccount.Loan))
+}
+
+// postLoanRepayments проводит платежи по кредитам, срок которых наступил
+func (app *BankApp) postLoanRepayments(ctx context.Context) {
+	report, err := app.admin.PostLoanRepayments(ctx, app.clock.Now())
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printHeader("Платежи по кредитам")
+	for _, result := range report.Results {
+		if result.Posted {
+			app.printf("%s  платеж %d  %.2f\n", result.LoanAccountID, result.Payment.Number, result.Payment.Payment)
+			continue
+		}
+		app.printf("%s  платеж %d  пропущен: %s\n", result.LoanAccountID, result.Payment.Number, app.tr.T(result.SkipReason))
+	}
+	app.printf("Проведено: %d, пропущено: %d, сумма: %.2f\n", report.Posted, report.Skipped, report.Total)
+}
+
+// showLoan показывает остаток долга, ближайший платеж и график по кредитному счету
+func (app *BankApp) showLoan(ctx context.Context) {
+	summary, err := app.currentAccount.GetLoanSummary(ctx)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printf("Остаток основного долга: %.2f %s\n", summary.RemainingPrincipal, app.currency)
+	app.printf("Внесено платежей: %d из %d\n", summary.PaymentsMade, summary.TermMonths)
+	if summary.NextPayment == nil {
+		app.println("Кредит погашен")
+	} else {
+		app.printf("Следующий платеж: %.2f %s, %s\n", summary.NextPayment.Payment, app.currency, app.formatTime(summary.NextPayment.DueDate))
+	}
+
+	schedule, err := app.currentAccount.GetLoanSchedule(ctx)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+	app.printLoanSchedule(schedule)
+}
+
+// printLoanSchedule выводит график платежей по кредиту
+func (app *BankApp) printLoanSchedule(schedule []models.LoanPayment) {
+	app.printHeader("График платежей")
+	for _, payment := range schedule {
+		app.printf("%3d  %s  платеж %.2f  проценты %.2f  долг %.2f  остаток %.2f\n",
+			payment.Number, app.formatTime(payment.DueDate), payment.Payment, payment.Interest, payment.Principal, payment.Remaining)
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+)
+
+type correlationKey struct{}
+
+// WithCorrelationID возвращает контекст с идентификатором запроса для сквозного логирования
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationIDFromContext возвращает идентификатор запроса из контекста
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationKey{}).(string)
+	return id, ok && id != ""
+}
+
+// correlationHandler добавляет к записям лога идентификатор запроса из контекста
+type correlationHandler struct {
+	slog.Handler
+}
+
+// WithCorrelation оборачивает обработчик логов так, чтобы каждая запись
+// содержала correlation_id из контекста, если он задан
+func WithCorrelation(handler slog.Handler) slog.Handler {
+	return correlationHandler{Handler: handler}
+}
+
+// Handle добавляет correlation_id и передает запись исходному обработчику
+func (h correlationHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		record.AddAttrs(slog.String("correlation_id", id))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs сохраняет обертку при добавлении атрибутов
+func (h correlationHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return correlationHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup сохраняет обертку при открытии группы
+func (h correlationHandler) WithGroup(name string) slog.Handler {
+	return correlationHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// WithLogger подключает логирование операций счета
+func WithLogger(logger *slog.Logger) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.logger = logger
+	}
+}
+
+// logOperation пишет в лог результат денежной операции по счету
+func (s *AccountServiceImpl) logOperation(ctx context.Context, operation string, amount float64, err error, attrs ...slog.Attr) {
+	if s.logger == nil {
+		return
+	}
+
+	attrs = append(attrs,
+		slog.String("operation", operation),
+		slog.String("account_id", s.account.ID),
+		slog.Float64("amount", amount))
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		s.logger.LogAttrs(ctx, slog.LevelWarn, "операция отклонена", attrs...)
+		return
+	}
+
+	s.logger.LogAttrs(ctx, slog.LevelInfo, "операция выполнена", attrs...)
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// LoggingStorage обертка над хранилищем, записывающая ошибки в лог
+type LoggingStorage struct {
+	storage interfaces.Storage
+	logger  *slog.Logger
+}
+
+// NewLoggingStorage оборачивает хранилище логированием ошибок
+func NewLoggingStorage(storage interfaces.Storage, logger *slog.Logger) interfaces.Storage {
+	return &LoggingStorage{
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+// SaveAccount сохраняет счет
+func (s *LoggingStorage) SaveAccount(ctx context.Context, account *models.Account) error {
+	err := s.storage.SaveAccount(ctx, account)
+	s.logError(ctx, "SaveAccount", err, slog.String("account_id", account.ID))
+	return err
+}
+
+// SaveAccounts сохраняет счета одной записью, если обернутое хранилище это
+// умеет, иначе по одному
+func (s *LoggingStorage) SaveAccounts(ctx context.Context, accounts ...*models.Account) error {
+	batch, ok := s.storage.(interfaces.BatchStorage)
+	if !ok {
+		for _, account := range accounts {
+			if err := s.SaveAccount(ctx, account); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := batch.SaveAccounts(ctx, accounts...)
+	s.logError(ctx, "SaveAccounts", err, slog.Int("accounts", len(accounts)))
+	return err
+}
+
+// Close закрывает обернутое хранилище, если ему это нужно
+func (s *LoggingStorage) Close(ctx context.Context) error {
+	closable, ok := s.storage.(interfaces.ClosableStorage)
+	if !ok {
+		return nil
+	}
+
+	err := closable.Close(ctx)
+	s.logError(ctx, "Close", err)
+	return err
+}
+
+// LoadAccount загружает счет по ID
+func (s *LoggingStorage) LoadAccount(ctx context.Context, accountID string) (*models.Account, error) {
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	s.logError(ctx, "LoadAccount", err, slog.String("account_id", accountID))
+	return account, err
+}
+
+// GetAllAccounts возвращает все счета
+func (s *LoggingStorage) GetAllAccounts(ctx context.Context) ([]*models.Account, error) {
+	accounts, err := s.storage.GetAllAccounts(ctx)
+	s.logError(ctx, "GetAllAccounts", err)
+	return accounts, err
+}
+
+// ListAccounts возвращает страницу счетов и их общее количество
+func (s *LoggingStorage) ListAccounts(ctx context.Context, offset, limit int) ([]*models.Account, int, error) {
+	accounts, total, err := s.storage.ListAccounts(ctx, offset, limit)
+	s.logError(ctx, "ListAccounts", err)
+	return accounts, total, err
+}
+
+// ListAccountsByScope возвращает страницу счетов из выборки scope и их количество
+func (s *LoggingStorage) ListAccountsByScope(ctx context.Context, scope models.AccountScope, offset, limit int) ([]*models.Account, int, error) {
+	accounts, total, err := s.storage.ListAccountsByScope(ctx, scope, offset, limit)
+	s.logError(ctx, "ListAccountsByScope", err)
+	return accounts, total, err
+}
+
+// SaveUser сохраняет пользователя
+func (s *LoggingStorage) SaveUser(ctx context.Context, user *models.User) error {
+	err := s.storage.SaveUser(ctx, user)
+	s.logError(ctx, "SaveUser", err, slog.String("user_id", user.ID))
+	return err
+}
+
+// LoadUser загружает пользователя по ID
+func (s *LoggingStorage) LoadUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.storage.LoadUser(ctx, userID)
+	s.logError(ctx, "LoadUser", err, slog.String("user_id", userID))
+	return user, err
+}
+
+// FindUserByUsername ищет пользователя по имени
+func (s *LoggingStorage) FindUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	user, err := s.storage.FindUserByUsername(ctx, username)
+	s.logError(ctx, "FindUserByUsername", err, slog.String("username", username))
+	return user, err
+}
+
+// GetAllUsers возвращает всех пользователей
+func (s *LoggingStorage) GetAllUsers(ctx context.Context) ([]*models.User, error) {
+	users, err := s.storage.GetAllUsers(ctx)
+	s.logError(ctx, "GetAllUsers", err)
+	return users, err
+}
+
+// logError пишет ошибку хранилища в лог. Отсутствие записи - штатная
+// ситуация при поиске, поэтому пишется с уровнем Debug.
+func (s *LoggingStorage) logError(ctx context.Context, method string, err error, attrs ...slog.Attr) {
+	if err == nil {
+		return
+	}
+
+	level := slog.LevelError
+	if stderrors.Is(err, errors.ErrAccountNotFound) || stderrors.Is(err, errors.ErrUserNotFound) {
+		level = slog.LevelDebug
+	}
+
+	attrs = append(attrs, slog.String("method", method), slog.String("error", err.Error()))
+	s.logger.LogAttrs(ctx, level, "ошибка хранилища", attrs...)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"bankapp/app"
+	"bankapp/config"
+	"bankapp/services"
+	"bankapp/storage"
+)
+
+const (
+	// webhookTimeout время ожидания ответа на одну отправку уведомления
+	webhookTimeout = 5 * time.Second
+	// webhookRetries количество повторных отправок уведомления
+	webhookRetries = 3
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "bench-storage" {
+		os.Exit(runBenchStorage(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "compare-storage" {
+		os.Exit(runCompareStorage(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "fuzz" {
+		os.Exit(runFuzz(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "roundtrip" {
+		os.Exit(runRoundTrip(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export-audit" {
+		os.Exit(runExportAudit(os.Args[2:]))
+	}
+
+	logLevel := flag.String("log-level", "warn", "уровень логирования: debug, info, warn, error")
+	logFormat := flag.String("log-format", "text", "формат логов: text или json")
+	notifyOver := flag.Float64("notify-over", 0, "печатать уведомления об операциях от этой суммы (0 - отключено)")
+	webhookURL := flag.String("webhook-url", "", "URL для отправки уведомлений о событиях по счетам")
+	statementURL := flag.String("statement-webhook-url", "", "URL для доставки выписок клиентам (шифруются ключом клиента, если он загружен)")
+	lang := flag.String("lang", "", "язык интерфейса: ru или en (по умолчанию из конфигурации)")
+	migratePlaintext := flag.Bool("migrate-plaintext", false, "однократно зашифровать незашифрованный файл хранилища текущим ключом")
+	startupCheck := flag.String("startup-check", "off", "проверка согласованности счетов при запуске: off, check или repair (карантин несогласованных счетов)")
+	ledgerOperators := flag.String("ledger-operators", "", "администраторы через запятую, которым разрешены прямые проводки по счетам")
+	configPath := flag.String("config", os.Getenv("BANKAPP_CONFIG"), "путь к JSON-файлу конфигурации (переменные BANKAPP_* имеют приоритет)")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err == nil && *lang != "" {
+		cfg.Locale = *lang
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка конфигурации: %v\n", err)
+		os.Exit(2)
+	}
+
+	checkOpts, err := startupCheckOptions(*startupCheck)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		os.Exit(2)
+	}
+
+	logger, err := newLogger(*logLevel, *logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Ключи уже проверены в cfg.Validate
+	keys, _ := cfg.Storage.Keys()
+	storeOpts := []storage.OpenOption{storage.WithEncryptionKeys(keys...)}
+	if *migratePlaintext {
+		storeOpts = append(storeOpts, storage.WithPlaintextMigration())
+	}
+	store, err := storage.Open(cfg.Storage.URI(), storeOpts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка хранилища: %v\n", err)
+		os.Exit(2)
+	}
+
+	opts := []app.Option{app.WithConfig(cfg), app.WithLogger(logger), app.WithStorage(store)}
+	opts = append(opts, checkOpts...)
+	if *ledgerOperators != "" {
+		opts = append(opts, app.WithLedgerOperators(strings.Split(*ledgerOperators, ",")...))
+	}
+	if *notifyOver > 0 {
+		opts = append(opts, app.WithObserver(services.NewConsoleNotifier(os.Stdout, *notifyOver)))
+	}
+	if *webhookURL != "" {
+		client := &http.Client{Timeout: webhookTimeout}
+		opts = append(opts, app.WithWebhook(services.NewWebhookNotifier(*webhookURL, client, webhookRetries)))
+	}
+	if *statementURL != "" {
+		client := &http.Client{Timeout: webhookTimeout}
+		opts = append(opts, app.WithStatementSender(services.NewWebhookStatementSender(*statementURL, client, webhookRetries)))
+	}
+	if cfg.Email.SMTPAddr != "" {
+		opts = append(opts, app.WithEmailSender(services.NewSMTPEmailSender(cfg.Email.SMTPAddr, cfg.Email.From, smtpAuth(cfg.Email))))
+	}
+
+	// По SIGINT и SIGTERM приложение записывает отложенные данные и закрывает хранилище
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.NewBankApp(opts...).Run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// smtpAuth возвращает вход на SMTP-сервер или nil, если имя пользователя не задано
+func smtpAuth(cfg config.EmailConfig) smtp.Auth {
+	if cfg.Username == "" {
+		return nil
+	}
+
+	// Адрес уже проверен в cfg.Validate
+	host, _, _ := net.SplitHostPort(cfg.SMTPAddr)
+	return smtp.PlainAuth("", cfg.Username, cfg.Password, host)
+}
+
+// startupCheckOptions разбирает режим проверки согласованности при запуске
+func startupCheckOptions(mode string) ([]app.Option, error) {
+	switch strings.ToLower(mode) {
+	case "", "off":
+		return nil, nil
+	case "check":
+		return []app.Option{app.WithStartupCheck(false)}, nil
+	case "repair":
+		return []app.Option{app.WithStartupCheck(true)}, nil
+	default:
+		return nil, fmt.Errorf("неизвестный режим проверки при запуске %q", mode)
+	}
+}
+
+// newLogger создает логгер, пишущий в stderr, чтобы не смешивать логи с меню
+func newLogger(level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("неизвестный уровень логирования %q", level)
+	}
+
+	options := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, options)), nil
+	default:
+		return nil, fmt.Errorf("неизвестный формат логов %q", format)
+	}
+}
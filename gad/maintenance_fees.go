@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// AssessMaintenanceFees начисляет ежемесячную плату за обслуживание по всем
+// счетам за месяц period. Плата рассчитывается политикой комиссий по правилу
+// MAINTENANCE. Замороженные и закрытые счета, счета, уже оплатившие этот месяц, и счета
+// без достаточных средств (с учетом овердрафта) пропускаются с указанием причины.
+// В режиме dryRun счета не изменяются, отчет показывает, что было бы списано.
+func AssessMaintenanceFees(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage,
+	policy interfaces.FeePolicy, period time.Time, dryRun bool) (models.MaintenanceFeeReport, error) {
+	report := models.MaintenanceFeeReport{
+		Period: period.Format("2006-01"),
+		DryRun: dryRun,
+	}
+
+	accounts, _, err := storage.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return report, err
+	}
+
+	for _, account := range accounts {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		result, err := assessMaintenanceFee(ctx, storage, ledger, policy, account, period, dryRun)
+		if err != nil {
+			return report, err
+		}
+
+		report.Results = append(report.Results, result)
+		if result.SkipReason != "" {
+			report.Skipped++
+			continue
+		}
+
+		report.Charged++
+		report.Total += result.Fee
+	}
+
+	return report, nil
+}
+
+// assessMaintenanceFee начисляет плату за обслуживание по одному счету
+func assessMaintenanceFee(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage,
+	policy interfaces.FeePolicy, account *models.Account, at time.Time, dryRun bool) (models.MaintenanceFeeResult, error) {
+	result := models.MaintenanceFeeResult{AccountID: account.ID}
+	period := at.Format("2006-01")
+
+	switch {
+	case account.Archived():
+		result.SkipReason = "счет в архиве"
+		return result, nil
+	case account.Status == models.ClosedStatus:
+		result.SkipReason = "счет закрыт"
+		return result, nil
+	case account.Status == models.FrozenStatus:
+		result.SkipReason = "счет заморожен"
+		return result, nil
+	case account.MaintenanceFeePeriod == period:
+		result.SkipReason = "плата за период уже списана"
+		return result, nil
+	}
+
+	fee, err := policy.CalculateFee(ctx, account, models.MaintenanceFee, account.Balance)
+	if err != nil {
+		return result, err
+	}
+
+	result.Fee = fee
+	switch {
+	case fee <= 0:
+		result.SkipReason = "плата не предусмотрена"
+		return result, nil
+	case fee > account.AvailableFundsAt(at):
+		result.SkipReason = "недостаточно средств"
+		return result, nil
+	}
+
+	if dryRun {
+		result.Charged = true
+		return result, nil
+	}
+
+	service := &AccountServiceImpl{
+		account: account,
+		storage: storage,
+		ledger:  ledger,
+		clock:   assessmentClock(at),
+	}
+
+	service.applyFee(fee, "", fmt.Sprintf("Плата за обслуживание счета за %s", period))
+	account.MaintenanceFeePeriod = period
+	if err := service.saveAccount(ctx); err != nil {
+		return result, err
+	}
+
+	result.Charged = true
+	return result, nil
+}
+
+// assessmentClock часы, всегда возвращающие момент начисления, чтобы плата
+// за обслуживание получала время от часов вызывающего, а не системное
+type assessmentClock time.Time
+
+// Now возвращает момент начисления
+func (c assessmentClock) Now() time.Time {
+	return time.Time(c)
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// MemoryAliasStorage индекс псевдонимов счетов в памяти.
+// Безопасно для одновременного использования из нескольких горутин.
+type MemoryAliasStorage struct {
+	mu      sync.RWMutex
+	aliases map[string]models.AccountAlias
+	changes []models.AliasChange
+}
+
+// NewMemoryAliasStorage создает индекс псевдонимов в памяти
+func NewMemoryAliasStorage() interfaces.AliasStorage {
+	return &MemoryAliasStorage{
+		aliases: make(map[string]models.AccountAlias),
+	}
+}
+
+// SaveAlias сохраняет псевдоним; псевдоним другого счета не перезаписывается
+func (s *MemoryAliasStorage) SaveAlias(ctx context.Context, alias models.AccountAlias) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, exists := s.aliases[alias.Alias]; exists && existing.AccountID != alias.AccountID {
+		return errors.ErrAliasTaken
+	}
+
+	s.aliases[alias.Alias] = alias
+	return nil
+}
+
+// DeleteAlias удаляет псевдоним
+func (s *MemoryAliasStorage) DeleteAlias(ctx context.Context, alias string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.aliases[alias]; !exists {
+		return errors.ErrAliasNotFound
+	}
+
+	delete(s.aliases, alias)
+	return nil
+}
+
+// LoadAlias загружает псевдоним
+func (s *MemoryAliasStorage) LoadAlias(ctx context.Context, alias string) (models.AccountAlias, error) {
+	if err := ctx.Err(); err != nil {
+		return models.AccountAlias{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	found, exists := s.aliases[alias]
+	if !exists {
+		return models.AccountAlias{}, errors.ErrAliasNotFound
+	}
+
+	return found, nil
+}
+
+// ListAliases возвращает псевдонимы счета в алфавитном порядке
+func (s *MemoryAliasStorage) ListAliases(ctx context.Context, accountID string) ([]models.AccountAlias, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var aliases []models.AccountAlias
+	for _, alias := range s.aliases {
+		if alias.AccountID == accountID {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Alias < aliases[j].Alias
+	})
+
+	return aliases, nil
+}
+
+// AppendAliasChange добавляет запись в историю изменений псевдонимов
+func (s *MemoryAliasStorage) AppendAliasChange(ctx context.Context, change models.AliasChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.changes = append(s.changes, change)
+	return nil
+}
+
+// ListAliasChanges возвращает историю изменений псевдонимов счета в порядке записи
+func (s *MemoryAliasStorage) ListAliasChanges(ctx context.Context, accountID string) ([]models.AliasChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var changes []models.AliasChange
+	for _, change := range s.changes {
+		if change.AccountID == accountID {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
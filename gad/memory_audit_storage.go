@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// MemoryAuditStorage реализация журнала аудита в памяти.
+// Безопасно для одновременного использования из нескольких горутин.
+type MemoryAuditStorage struct {
+	mu      sync.RWMutex
+	entries []models.AuditEntry
+}
+
+// NewMemoryAuditStorage создает журнал аудита в памяти
+func NewMemoryAuditStorage() interfaces.AuditStorage {
+	return &MemoryAuditStorage{}
+}
+
+// AppendAuditEntry добавляет запись в конец журнала и присваивает ей номер
+func (s *MemoryAuditStorage) AppendAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.Sequence = uint64(len(s.entries)) + 1
+	s.entries = append(s.entries, *entry)
+	return nil
+}
+
+// QueryAuditEntries возвращает страницу подходящих записей и их общее количество
+func (s *MemoryAuditStorage) QueryAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matches []models.AuditEntry
+	for _, entry := range s.entries {
+		if matchesAuditFilter(entry, filter) {
+			matches = append(matches, entry)
+		}
+	}
+
+	start, end := models.PageBounds(len(matches), filter.Offset, filter.Limit)
+	return matches[start:end], len(matches), nil
+}
+
+// matchesAuditFilter проверяет запись журнала на соответствие критериям
+func matchesAuditFilter(entry models.AuditEntry, filter models.AuditFilter) bool {
+	switch {
+	case filter.Actor != "" && entry.Actor != filter.Actor:
+		return false
+	case filter.AccountID != "" && entry.AccountID != filter.AccountID:
+		return false
+	case filter.Action != "" && entry.Action != filter.Action:
+		return false
+	case filter.FailuresOnly && entry.Success:
+		return false
+	case !filter.From.IsZero() && entry.Timestamp.Before(filter.From):
+		return false
+	case !filter.To.IsZero() && !entry.Timestamp.Before(filter.To):
+		return false
+	}
+
+	return true
+}
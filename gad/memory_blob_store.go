@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// MemoryBlobStore реализация хранилища вложений в памяти
+type MemoryBlobStore struct {
+	blobs map[string][]byte
+}
+
+// NewMemoryBlobStore создает хранилище вложений в памяти
+func NewMemoryBlobStore() interfaces.BlobStore {
+	return &MemoryBlobStore{
+		blobs: make(map[string][]byte),
+	}
+}
+
+// Put сохраняет содержимое и возвращает ключ для последующего чтения
+func (s *MemoryBlobStore) Put(ctx context.Context, name string, data []byte) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	key := models.DefaultIDGenerator.NewID("BLOB") + "/" + name
+	s.blobs[key] = append([]byte(nil), data...)
+	return key, nil
+}
+
+// Get возвращает содержимое по ключу
+func (s *MemoryBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, exists := s.blobs[key]
+	if !exists {
+		return nil, errors.ErrBlobNotFound
+	}
+
+	return append([]byte(nil), data...), nil
+}
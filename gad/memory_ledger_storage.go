@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// MemoryLedgerStorage реализация журнала событий в памяти.
+// Безопасно для одновременного использования из нескольких горутин.
+type MemoryLedgerStorage struct {
+	mu        sync.RWMutex
+	events    map[string][]models.AccountEvent
+	snapshots map[string]models.BalanceSnapshot
+}
+
+// NewMemoryLedgerStorage создает журнал событий в памяти
+func NewMemoryLedgerStorage() interfaces.LedgerStorage {
+	return &MemoryLedgerStorage{
+		events:    make(map[string][]models.AccountEvent),
+		snapshots: make(map[string]models.BalanceSnapshot),
+	}
+}
+
+// AppendEvent добавляет событие в конец журнала счета и присваивает ему номер
+func (s *MemoryLedgerStorage) AppendEvent(ctx context.Context, event *models.AccountEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event.Sequence = uint64(len(s.events[event.AccountID])) + 1
+	s.events[event.AccountID] = append(s.events[event.AccountID], *event)
+	return nil
+}
+
+// LoadEvents возвращает события счета с номером больше afterSequence
+func (s *MemoryLedgerStorage) LoadEvents(ctx context.Context, accountID string, afterSequence uint64) ([]models.AccountEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := s.events[accountID]
+	if afterSequence >= uint64(len(events)) {
+		return nil, nil
+	}
+
+	return append([]models.AccountEvent(nil), events[afterSequence:]...), nil
+}
+
+// SaveSnapshot сохраняет снимок баланса счета
+func (s *MemoryLedgerStorage) SaveSnapshot(ctx context.Context, snapshot models.BalanceSnapshot) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snapshots[snapshot.AccountID] = snapshot
+	return nil
+}
+
+// LoadSnapshot возвращает последний снимок баланса счета
+func (s *MemoryLedgerStorage) LoadSnapshot(ctx context.Context, accountID string) (models.BalanceSnapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return models.BalanceSnapshot{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot, exists := s.snapshots[accountID]
+	if !exists {
+		return models.BalanceSnapshot{}, errors.ErrSnapshotNotFound
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"slices"
+
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// MemoryOutboxStorage журнал исходящих уведомлений в памяти
+type MemoryOutboxStorage struct {
+	records map[string]models.OutboxRecord
+	order   []string
+}
+
+// NewMemoryOutboxStorage создает журнал исходящих уведомлений в памяти
+func NewMemoryOutboxStorage() interfaces.OutboxStorage {
+	return &MemoryOutboxStorage{
+		records: make(map[string]models.OutboxRecord),
+	}
+}
+
+// SaveOutboxRecord добавляет запись или обновляет запись с тем же ID уведомления
+func (s *MemoryOutboxStorage) SaveOutboxRecord(ctx context.Context, record models.OutboxRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	id := record.Notification.ID
+	if _, exists := s.records[id]; !exists {
+		s.order = append(s.order, id)
+	}
+	s.records[id] = record
+	return nil
+}
+
+// ListOutboxRecords возвращает записи, подходящие под фильтр, в порядке отправки
+func (s *MemoryOutboxStorage) ListOutboxRecords(ctx context.Context, filter models.OutboxFilter) ([]models.OutboxRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var records []models.OutboxRecord
+	for _, id := range s.order {
+		record := s.records[id]
+		notification := record.Notification
+
+		if !filter.From.IsZero() && notification.Timestamp.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !notification.Timestamp.Before(filter.To) {
+			continue
+		}
+		if len(filter.TransactionIDs) > 0 && !slices.Contains(filter.TransactionIDs, notification.TransactionID) {
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
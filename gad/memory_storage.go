@@ -1,45 +1,211 @@
-package storage
-
-import (
-	"bankapp/errors"
-	"bankapp/interfaces"
-	"bankapp/models"
-)
-
-// MemoryStorage реализация хранилища в памяти
-type MemoryStorage struct {
-	accounts map[string]*models.Account
-}
-
-// NewMemoryStorage создает новое хранилище в памяти
-func NewMemoryStorage() interfaces.Storage {
-	return &MemoryStorage{
-		accounts: make(map[string]*models.Account),
-	}
-}
-
-// SaveAccount сохраняет счет
-func (s *MemoryStorage) SaveAccount(account *models.Account) error {
-	s.accounts[account.ID] = account
-	return nil
-}
-
-// LoadAccount загружает счет по ID
-func (s *MemoryStorage) LoadAccount(accountID string) (*models.Account, error) {
-	account, exists := s.accounts[accountID]
-	if !exists {
-		return nil, errors.ErrAccountNotFound
-	}
-
-	return account, nil
-}
-
-// GetAllAccounts возвращает все счета
-func (s *MemoryStorage) GetAllAccounts() ([]*models.Account, error) {
-	accounts := make([]*models.Account, 0, len(s.accounts))
-	for _, account := range s.accounts {
-		accounts = append(accounts, account)
-	}
-
-	return accounts, nil
-}
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// MemoryStorage реализация хранилища в памяти. Счета и пользователи
+// хранятся и возвращаются копиями: изменения, не прошедшие SaveAccount,
+// не видны другим, а сохранение устаревшей копии обнаруживается по версии.
+// Безопасно для одновременного использования из нескольких горутин.
+type MemoryStorage struct {
+	mu       sync.RWMutex
+	accounts map[string]*models.Account
+	users    map[string]*models.User
+}
+
+// NewMemoryStorage создает новое хранилище в памяти
+func NewMemoryStorage() interfaces.Storage {
+	return &MemoryStorage{
+		accounts: make(map[string]*models.Account),
+		users:    make(map[string]*models.User),
+	}
+}
+
+// SaveAccount сохраняет счет, если его версия совпадает с сохраненной,
+// включает новые транзакции в цепочку хешей и увеличивает версию
+func (s *MemoryStorage) SaveAccount(ctx context.Context, account *models.Account) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, exists := s.accounts[account.ID]; exists && stored.Version != account.Version {
+		return errors.ErrConcurrentModification
+	}
+
+	account.SealHistory()
+	account.Version++
+	s.accounts[account.ID] = account.Clone()
+	return nil
+}
+
+// SaveAccounts сохраняет счета под одной блокировкой: если версия хотя бы
+// одного счета устарела, не сохраняется ни один
+func (s *MemoryStorage) SaveAccounts(ctx context.Context, accounts ...*models.Account) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, account := range accounts {
+		if stored, exists := s.accounts[account.ID]; exists && stored.Version != account.Version {
+			return errors.ErrConcurrentModification
+		}
+	}
+
+	for _, account := range accounts {
+		account.SealHistory()
+		account.Version++
+		s.accounts[account.ID] = account.Clone()
+	}
+	return nil
+}
+
+// LoadAccount загружает счет по ID
+func (s *MemoryStorage) LoadAccount(ctx context.Context, accountID string) (*models.Account, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	account, exists := s.accounts[accountID]
+	if !exists {
+		return nil, errors.ErrAccountNotFound
+	}
+
+	return account.Clone(), nil
+}
+
+// GetAllAccounts возвращает все счета
+func (s *MemoryStorage) GetAllAccounts(ctx context.Context) ([]*models.Account, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	accounts := make([]*models.Account, 0, len(s.accounts))
+	for _, account := range s.accounts {
+		accounts = append(accounts, account.Clone())
+	}
+
+	return accounts, nil
+}
+
+// ListAccounts возвращает страницу счетов, упорядоченных по дате создания,
+// и общее количество счетов
+func (s *MemoryStorage) ListAccounts(ctx context.Context, offset, limit int) ([]*models.Account, int, error) {
+	return s.ListAccountsByScope(ctx, models.AllAccounts, offset, limit)
+}
+
+// ListAccountsByScope возвращает страницу счетов из выборки scope, упорядоченных
+// по дате создания, и общее количество счетов в выборке
+func (s *MemoryStorage) ListAccountsByScope(ctx context.Context, scope models.AccountScope, offset, limit int) ([]*models.Account, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := make([]*models.Account, 0, len(s.accounts))
+	for _, account := range s.accounts {
+		if scope.Match(account) {
+			matched = append(matched, account)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].ID < matched[j].ID
+		}
+		return matched[i].CreatedAt.Before(matched[j].CreatedAt)
+	})
+
+	start, end := models.PageBounds(len(matched), offset, limit)
+	page := make([]*models.Account, 0, end-start)
+	for _, account := range matched[start:end] {
+		page = append(page, account.Clone())
+	}
+
+	return page, len(matched), nil
+}
+
+// SaveUser сохраняет пользователя
+func (s *MemoryStorage) SaveUser(ctx context.Context, user *models.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[user.ID] = user.Clone()
+	return nil
+}
+
+// LoadUser загружает пользователя по ID
+func (s *MemoryStorage) LoadUser(ctx context.Context, userID string) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, exists := s.users[userID]
+	if !exists {
+		return nil, errors.ErrUserNotFound
+	}
+
+	return user.Clone(), nil
+}
+
+// FindUserByUsername ищет пользователя по имени
+func (s *MemoryStorage) FindUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if user.Username == username {
+			return user.Clone(), nil
+		}
+	}
+
+	return nil, errors.ErrUserNotFound
+}
+
+// GetAllUsers возвращает всех пользователей
+func (s *MemoryStorage) GetAllUsers(ctx context.Context) ([]*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]*models.User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user.Clone())
+	}
+
+	return users, nil
+}
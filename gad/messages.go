@@ -0,0 +1,33 @@
+package app
+
+import (
+	"fmt"
+
+	"bankapp/i18n"
+)
+
+// setLanguage переключает язык интерфейса; пустой язык - язык приложения
+func (app *BankApp) setLanguage(language string) {
+	if language == "" {
+		language = app.locale
+	}
+
+	if tr, err := i18n.New(language); err == nil {
+		app.tr = tr
+	}
+}
+
+// print выводит переведенное сообщение
+func (app *BankApp) print(message string) {
+	fmt.Print(app.tr.T(message))
+}
+
+// println выводит переведенное сообщение с переводом строки
+func (app *BankApp) println(message string) {
+	fmt.Println(app.tr.T(message))
+}
+
+// printf выводит сообщение по переведенному шаблону
+func (app *BankApp) printf(format string, args ...any) {
+	fmt.Print(app.tr.Sprintf(format, args...))
+}
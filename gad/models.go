@@ -1,48 +1,1414 @@
-package models
-
-import (
-	"fmt"
-	"time"
-)
-
-// TransactionType тип транзакции
-type TransactionType string
-
-const (
-	DepositTransaction  TransactionType = "DEPOSIT"
-	WithdrawTransaction TransactionType = "WITHDRAW"
-	TransferTransaction TransactionType = "TRANSFER"
-)
-
-// Transaction структура транзакции
-type Transaction struct {
-	ID        string
-	Type      TransactionType
-	Amount    float64
-	Timestamp time.Time
-	Message   string
-}
-
-// Account структура счета
-type Account struct {
-	ID           string
-	OwnerName    string
-	Balance      float64
-	Transactions []Transaction
-	CreatedAt    time.Time
-}
-
-// NewAccount создает новый счет
-func NewAccount(ownerName string) *Account {
-	return &Account{
-		ID:        generateID(),
-		OwnerName: ownerName,
-		Balance:   0,
-		CreatedAt: time.Now(),
-	}
-}
-
-// generateID генерирует уникальный ID для счета
-func generateID() string {
-	return fmt.Sprintf("ACC%d", time.Now().UnixNano())
-}
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// TransactionType тип транзакции
+type TransactionType string
+
+const (
+	DepositTransaction  TransactionType = "DEPOSIT"
+	WithdrawTransaction TransactionType = "WITHDRAW"
+	TransferTransaction TransactionType = "TRANSFER"
+	LedgerTransaction   TransactionType = "LEDGER"
+	FeeTransaction      TransactionType = "FEE"
+
+	// StatusTransaction служебная запись о смене статуса счета, не влияющая на баланс
+	StatusTransaction TransactionType = "STATUS"
+
+	// ReversalTransaction сторно: компенсирующая запись, отменяющая исходную транзакцию
+	ReversalTransaction TransactionType = "REVERSAL"
+
+	// FeeCorrectionTransaction корректировка ранее списанной комиссии после
+	// пересчета: возврат (CREDIT) или доначисление (DEBIT)
+	FeeCorrectionTransaction TransactionType = "FEE_CORRECTION"
+
+	// BalanceCorrectionTransaction корректировка при пересборке баланса из журнала
+	// событий: приводит историю транзакций в соответствие с журналом
+	BalanceCorrectionTransaction TransactionType = "BALANCE_CORRECTION"
+
+	// MaintenanceFee тип правила комиссии за ежемесячное обслуживание счета.
+	// Сама плата списывается транзакцией FEE.
+	MaintenanceFee TransactionType = "MAINTENANCE"
+
+	// OverdraftLimitTransaction служебная запись об изменении лимита овердрафта,
+	// не влияющая на баланс
+	OverdraftLimitTransaction TransactionType = "OVERDRAFT_LIMIT"
+
+	// LoanTransaction выдача кредита и платежи по нему: на кредитном счете
+	// выдача - DEBIT, погашение основного долга - CREDIT; на текущем наоборот
+	LoanTransaction TransactionType = "LOAN"
+)
+
+// TransactionTypeInfo тип транзакции, зарегистрированный оператором:
+// отображаемое имя, направление проводки (знак суммы) и группа в выписке
+type TransactionTypeInfo struct {
+	Type      TransactionType `json:"type"`
+	Name      string          `json:"name"`
+	Direction EntryDirection  `json:"direction"`
+	Group     string          `json:"group"`
+}
+
+// EntryDirection направление проводки по счету
+type EntryDirection string
+
+const (
+	DebitEntry  EntryDirection = "DEBIT"
+	CreditEntry EntryDirection = "CREDIT"
+)
+
+// DepositSource источник поступления средств при пополнении
+type DepositSource string
+
+const (
+	CashSource       DepositSource = "CASH"
+	TransferInSource DepositSource = "TRANSFER_IN"
+	ChequeSource     DepositSource = "CHEQUE"
+	CorrectionSource DepositSource = "CORRECTION"
+)
+
+// Valid проверяет, что источник пополнения известен
+func (s DepositSource) Valid() bool {
+	switch s {
+	case CashSource, TransferInSource, ChequeSource, CorrectionSource:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExportFormat формат экспорта выписки
+type ExportFormat string
+
+const (
+	CSVFormat  ExportFormat = "csv"
+	JSONFormat ExportFormat = "json"
+
+	// Форматы выгрузки журнала аудита
+	JSONLinesFormat ExportFormat = "jsonl"
+	CEFFormat       ExportFormat = "cef"
+)
+
+// Transaction структура транзакции
+type Transaction struct {
+	ID        string
+	Type      TransactionType
+	Amount    float64
+	Timestamp time.Time
+	Message   string
+
+	// Двойная запись: направление проводки, общий ID ног перевода и счет контрагента
+	Direction      EntryDirection
+	TransferID     string
+	CounterpartyID string
+
+	// Source источник средств пополнения
+	Source DepositSource
+
+	// Denominations разбивка взноса наличными по купюрам
+	Denominations []CashNote
+
+	// RiskScore оценка риска операции от внешнего RiskScorer (0, если оценки нет)
+	RiskScore   float64
+	UnderReview bool
+
+	// Сторно: ID компенсирующей транзакции у исходной и ID исходной у компенсирующей
+	ReversedBy string
+	ReversalOf string
+
+	// RelatedID связанная транзакция: операция, за которую списана комиссия,
+	// или исходная комиссия для корректировки
+	RelatedID string
+	// CorrectedBy корректировки комиссии, проведенные после пересчета
+	CorrectedBy []string
+
+	Attachments []Attachment
+
+	// Пользовательская разметка: категория расходов, теги и заметка
+	Category string
+	Tags     []string
+	Note     string
+
+	// Цепочка хешей: хеш предыдущей транзакции счета и хеш этой транзакции,
+	// проставляются хранилищем при сохранении счета
+	PrevHash string
+	Hash     string
+}
+
+// TransactionDetails категория, теги и заметка, которые пользователь
+// указывает при проведении операции или позже
+type TransactionDetails struct {
+	Category string
+	Tags     []string
+	Note     string
+}
+
+// CategorySpending сумма и количество списаний по одной категории
+type CategorySpending struct {
+	Category string
+	Amount   float64
+	Count    int
+}
+
+// OperationResult квитанция денежной операции: позволяет показать результат
+// без повторного чтения счета
+type OperationResult struct {
+	TransactionID string
+	// TransferID общий ID ног перевода, пустой для пополнений и снятий
+	TransferID  string
+	Fee         float64
+	Balance     float64
+	ValueDate   time.Time
+	UnderReview bool
+
+	// HeldAmount сумма подозрительных купюр, отложенная до проверки
+	HeldAmount float64
+	HoldID     string
+
+	// ReceiptCode код проверки квитанции, пустой, если квитанции не выдаются
+	ReceiptCode string
+}
+
+// Receipt квитанция по операции: данные транзакции, баланс счета после нее
+// и код проверки (HMAC), по которому банк подтверждает подлинность
+type Receipt struct {
+	TransactionID  string          `json:"transaction_id"`
+	AccountID      string          `json:"account_id"`
+	Type           TransactionType `json:"type"`
+	Direction      EntryDirection  `json:"direction"`
+	Amount         float64         `json:"amount"`
+	Fee            float64         `json:"fee"`
+	CounterpartyID string          `json:"counterparty_id,omitempty"`
+	Timestamp      time.Time       `json:"timestamp"`
+	Balance        float64         `json:"balance"`
+	Code           string          `json:"code"`
+}
+
+// OperationPreview расчет денежной операции без ее проведения: комиссия
+// и баланс счета до и после операции
+type OperationPreview struct {
+	Type           TransactionType
+	Amount         float64
+	Fee            float64
+	CounterpartyID string
+	BalanceBefore  float64
+	BalanceAfter   float64
+	AvailableAfter float64
+}
+
+// Attachment вложение к транзакции: файл в хранилище вложений
+// (BlobKey) или внешняя ссылка (Reference) на документ
+type Attachment struct {
+	ID        string
+	Name      string
+	BlobKey   string
+	Reference string
+	Size      int
+	CreatedAt time.Time
+}
+
+// SignedAmount возвращает сумму транзакции со знаком: поступления
+// положительные, списания отрицательные
+func (t Transaction) SignedAmount() float64 {
+	switch t.Type {
+	case LedgerTransaction:
+		// Сумма проводки хранится со знаком
+		return t.Amount
+	case OverdraftLimitTransaction, StatusTransaction:
+		return 0
+	}
+
+	if t.Direction == DebitEntry {
+		return -t.Amount
+	}
+
+	return t.Amount
+}
+
+// FeeRule правило начисления комиссии за операции одного типа.
+// Процент начисляется только на операции с суммой больше Threshold.
+type FeeRule struct {
+	Type      TransactionType `json:"type"`
+	Fixed     float64         `json:"fixed"`
+	Percent   float64         `json:"percent"`
+	Threshold float64         `json:"threshold"`
+	// When условие применения правила на языке выражений; пустое - всегда
+	When string `json:"when,omitempty"`
+}
+
+// LimitRule ограничение, заданное выражением: операция отклоняется,
+// если условие When выполняется
+type LimitRule struct {
+	Name string `json:"name"`
+	When string `json:"when"`
+}
+
+// RiskRule правило оценки риска: операции, для которой выполняется
+// условие When, присваивается оценка Score от 0 до 1
+type RiskRule struct {
+	Name  string  `json:"name"`
+	When  string  `json:"when"`
+	Score float64 `json:"score"`
+}
+
+// RiskRequest данные операции, передаваемые на оценку риска
+type RiskRequest struct {
+	AccountID    string
+	Type         TransactionType
+	Amount       float64
+	Counterparty string
+	At           time.Time
+}
+
+// LedgerEntry нога проводки, передаваемая в PostEntries.
+// Положительная сумма зачисляется на счет, отрицательная списывается.
+type LedgerEntry struct {
+	AccountID  string
+	Amount     float64
+	ReasonCode string
+	Memo       string
+}
+
+// TransactionFilter критерии поиска транзакций по всем счетам.
+// Нулевые значения полей означают отсутствие ограничения.
+type TransactionFilter struct {
+	MinAmount    float64
+	MaxAmount    float64
+	Counterparty string
+	From         time.Time
+	To           time.Time
+	Text         string
+	Category     string
+	Tag          string
+	Offset       int
+	Limit        int
+}
+
+// Period интервал времени [From, To)
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// MonthlySummary обороты счета за календарный месяц (Month в формате ГГГГ-ММ)
+// по типам операций и категориям расходов
+type MonthlySummary struct {
+	Month      string
+	In         float64
+	Out        float64
+	ByType     map[TransactionType]float64
+	ByCategory map[string]float64
+}
+
+// AccountSummary аналитическая сводка по счету за период
+type AccountSummary struct {
+	AccountID      string
+	Period         Period
+	Months         []MonthlySummary
+	AverageBalance float64
+	NetFlow        float64
+	Largest        []Transaction
+}
+
+// TransactionSearchResult найденная транзакция вместе со счетом, к которому она относится
+type TransactionSearchResult struct {
+	AccountID   string
+	OwnerName   string
+	Transaction Transaction
+}
+
+// Account структура счета
+type Account struct {
+	ID           string
+	OwnerID      string
+	OwnerName    string
+	Balance      float64
+	Transactions []Transaction
+	CreatedAt    time.Time
+	Status       AccountStatus
+
+	// Карантин: счет заморожен автоматически из-за несогласованных данных
+	Quarantined      bool
+	QuarantineReason string
+
+	// OverdraftLimit сумма, на которую баланс может уйти в минус
+	OverdraftLimit float64
+
+	// Дневные лимиты на снятия и исходящие переводы (0 - без ограничения)
+	DailyAmountLimit float64
+	DailyCountLimit  int
+
+	// Служебные поля администратора, в клиентскую выписку не попадают
+	Notes               string
+	RelationshipManager string
+
+	// Входящие внешние платежи, ожидающие решения владельца, и режим автоприема
+	PendingCredits    []PendingCredit
+	AutoAcceptCredits bool
+
+	// CashHolds подозрительные купюры из взносов наличными, ожидающие проверки
+	CashHolds []CashHold
+
+	// PendingTransfers переводы, ожидающие подтверждения; сумма активных
+	// переводов удерживается и недоступна для других списаний
+	PendingTransfers []PendingTransfer
+
+	// AccessGrants доверенности: доступ других пользователей к счету
+	AccessGrants []AccessGrant
+
+	// Loan условия кредита, если это кредитный счет; задолженность по
+	// основному долгу хранится отрицательным балансом
+	Loan *Loan
+
+	// ParentID родительский счет, который ограничивает траты этого счета
+	ParentID         string
+	SpendingControls SpendingControls
+
+	// MaintenanceFeePeriod месяц (ГГГГ-ММ), за который последний раз списана плата за обслуживание
+	MaintenanceFeePeriod string
+
+	// StatementKey открытый ключ X25519 клиента, которым шифруются
+	// отправляемые выписки (пусто - выписки отправляются без шифрования)
+	StatementKey []byte
+
+	// Выписки по электронной почте: адрес, подписка на ежемесячную рассылку
+	// и месяц (ГГГГ-ММ) последней отправленной ежемесячной выписки
+	StatementEmail       string
+	MonthlyStatements    bool
+	StatementEmailPeriod string
+
+	// HistoryHash хеш последней транзакции в цепочке; по нему видно
+	// удаление транзакций с конца истории
+	HistoryHash string
+
+	// ArchivedAt время переноса неактивного счета в архив (нулевое - не в
+	// архиве). Счет в архиве скрыт из списков и недоступен для операций.
+	ArchivedAt time.Time
+
+	// Учетные данные: хеш PIN-кода и состояние блокировки после неудачных попыток
+	PINHash           []byte
+	PINSalt           []byte
+	FailedPINAttempts int
+	LockedUntil       time.Time
+
+	// Version номер версии, увеличивается хранилищем при каждом сохранении;
+	// сохранение копии с устаревшей версией отклоняется
+	Version int64
+}
+
+// AccountStatus статус жизненного цикла счета
+type AccountStatus string
+
+const (
+	ActiveStatus AccountStatus = "ACTIVE"
+	FrozenStatus AccountStatus = "FROZEN"
+	ClosedStatus AccountStatus = "CLOSED"
+)
+
+// EventType тип события в журнале счета
+type EventType string
+
+const (
+	DepositEvent     EventType = "DEPOSIT"
+	WithdrawEvent    EventType = "WITHDRAW"
+	TransferInEvent  EventType = "TRANSFER_IN"
+	TransferOutEvent EventType = "TRANSFER_OUT"
+	AdjustmentEvent  EventType = "ADJUSTMENT"
+	FeeEvent         EventType = "FEE"
+	ReversalEvent    EventType = "REVERSAL"
+)
+
+// AccountEvent событие журнала счета. Журнал только дополняется,
+// баланс счета выводится из последовательности событий.
+type AccountEvent struct {
+	AccountID     string
+	Sequence      uint64
+	Type          EventType
+	Amount        float64
+	TransactionID string
+	Timestamp     time.Time
+}
+
+// BalancePoint баланс счета на конец дня Date
+type BalancePoint struct {
+	Date    time.Time
+	Balance float64
+}
+
+// Delta возвращает изменение баланса, вносимое событием
+func (e AccountEvent) Delta() float64 {
+	switch e.Type {
+	case DepositEvent, TransferInEvent:
+		return e.Amount
+	case WithdrawEvent, TransferOutEvent, FeeEvent:
+		return -e.Amount
+	case AdjustmentEvent, ReversalEvent:
+		// Сумма корректировки и сторно хранится со знаком
+		return e.Amount
+	default:
+		return 0
+	}
+}
+
+// BalanceSnapshot снимок баланса счета после события с номером Sequence
+type BalanceSnapshot struct {
+	AccountID string
+	Sequence  uint64
+	Balance   float64
+	Timestamp time.Time
+}
+
+// RebuildResult результат пересборки баланса счета из журнала событий
+type RebuildResult struct {
+	AccountID      string
+	StoredBalance  float64
+	RebuiltBalance float64
+	Events         int
+	// Mismatch баланс или история транзакций расходятся с журналом
+	Mismatch bool
+	// Correction сумма корректирующей транзакции, выравнивающей историю
+	// с журналом; ноль, если история уже совпадает с журналом
+	Correction float64
+}
+
+// ReconciliationReport результат сверки проводок по всему банку
+type ReconciliationReport struct {
+	// Entries количество проверенных проводок
+	Entries int
+	// InternalTotal сумма всех переводов и корректировок, должна быть равна нулю
+	InternalTotal float64
+	// UnbalancedTransfers переводы, ноги которых не сходятся
+	UnbalancedTransfers []string
+	// BalanceMismatches счета, баланс которых не равен сумме проводок
+	BalanceMismatches []string
+}
+
+// Balanced проверяет, что сверка не выявила расхождений
+func (r ReconciliationReport) Balanced() bool {
+	return len(r.UnbalancedTransfers) == 0 && len(r.BalanceMismatches) == 0 && math.Round(r.InternalTotal*100) == 0
+}
+
+// Archived сообщает, перенесен ли счет в архив
+func (a *Account) Archived() bool {
+	return !a.ArchivedAt.IsZero()
+}
+
+// AccountScope отбор счетов в выборке из хранилища по признаку архива
+type AccountScope int
+
+const (
+	// AllAccounts все счета, включая архивные
+	AllAccounts AccountScope = iota
+	// ActiveAccounts счета вне архива
+	ActiveAccounts
+	// ArchivedAccounts только архивные счета
+	ArchivedAccounts
+)
+
+// Match сообщает, попадает ли счет в выборку
+func (scope AccountScope) Match(account *Account) bool {
+	switch scope {
+	case ActiveAccounts:
+		return !account.Archived()
+	case ArchivedAccounts:
+		return account.Archived()
+	default:
+		return true
+	}
+}
+
+// AvailableFundsAt возвращает сумму, доступную для списания на момент at
+// с учетом овердрафта и удержаний по неподтвержденным переводам
+func (a *Account) AvailableFundsAt(at time.Time) float64 {
+	return a.Balance + a.OverdraftLimit - a.HeldFunds(at)
+}
+
+// HeldFunds сумма, удерживаемая на момент at по неподтвержденным переводам;
+// просроченные удержания не учитываются, даже если еще не помечены
+func (a *Account) HeldFunds(at time.Time) float64 {
+	var held float64
+	for _, transfer := range a.PendingTransfers {
+		if transfer.Active(at) {
+			held += transfer.Amount + transfer.Fee
+		}
+	}
+	return held
+}
+
+// Clone возвращает глубокую копию счета вместе с историей транзакций
+func (a *Account) Clone() *Account {
+	clone := *a
+	clone.PINHash = append([]byte(nil), a.PINHash...)
+	clone.PINSalt = append([]byte(nil), a.PINSalt...)
+	clone.StatementKey = append([]byte(nil), a.StatementKey...)
+
+	clone.PendingCredits = append([]PendingCredit(nil), a.PendingCredits...)
+	clone.CashHolds = append([]CashHold(nil), a.CashHolds...)
+	for i := range clone.CashHolds {
+		clone.CashHolds[i].Notes = append([]CashNote(nil), a.CashHolds[i].Notes...)
+	}
+	clone.PendingTransfers = append([]PendingTransfer(nil), a.PendingTransfers...)
+	clone.AccessGrants = append([]AccessGrant(nil), a.AccessGrants...)
+	if a.Loan != nil {
+		loan := *a.Loan
+		clone.Loan = &loan
+	}
+	clone.SpendingControls.BlockedCategories = append([]string(nil), a.SpendingControls.BlockedCategories...)
+
+	clone.Transactions = make([]Transaction, len(a.Transactions))
+	for i, tx := range a.Transactions {
+		tx.Attachments = append([]Attachment(nil), tx.Attachments...)
+		tx.Denominations = append([]CashNote(nil), tx.Denominations...)
+		tx.Tags = append([]string(nil), tx.Tags...)
+		tx.CorrectedBy = append([]string(nil), tx.CorrectedBy...)
+		clone.Transactions[i] = tx
+	}
+
+	return &clone
+}
+
+// Clone возвращает копию пользователя
+func (u *User) Clone() *User {
+	clone := *u
+	clone.PasswordHash = append([]byte(nil), u.PasswordHash...)
+	clone.PasswordSalt = append([]byte(nil), u.PasswordSalt...)
+
+	return &clone
+}
+
+// NotificationType тип уведомления о событии по счету
+type NotificationType string
+
+const (
+	AccountCreatedNotification    NotificationType = "ACCOUNT_CREATED"
+	DepositedNotification         NotificationType = "DEPOSITED"
+	WithdrawnNotification         NotificationType = "WITHDRAWN"
+	TransferCompletedNotification NotificationType = "TRANSFER_COMPLETED"
+	SpendingBlockedNotification   NotificationType = "SPENDING_BLOCKED"
+	PendingExpiredNotification    NotificationType = "PENDING_EXPIRED"
+)
+
+// Notification уведомление о событии по счету для подписчиков шины событий.
+// ID не меняется при повторной доставке и служит ключом идемпотентности.
+type Notification struct {
+	ID             string           `json:"id"`
+	Type           NotificationType `json:"type"`
+	AccountID      string           `json:"account_id"`
+	TransactionID  string           `json:"transaction_id,omitempty"`
+	CounterpartyID string           `json:"counterparty_id,omitempty"`
+	Amount         float64          `json:"amount,omitempty"`
+	Reason         string           `json:"reason,omitempty"`
+	Timestamp      time.Time        `json:"timestamp"`
+}
+
+// OutboxRecord уведомление, отправленное на вебхук, и результат доставки
+type OutboxRecord struct {
+	Notification Notification
+	Attempts     int
+	DeliveredAt  time.Time
+	LastError    string
+}
+
+// OutboxFilter отбор записей исходящих уведомлений для повторной доставки:
+// период [From, To) по времени события и/или конкретные транзакции
+type OutboxFilter struct {
+	From           time.Time
+	To             time.Time
+	TransactionIDs []string
+}
+
+// ReplayReport результат повторной доставки уведомлений
+type ReplayReport struct {
+	Matched   int
+	Delivered int
+	Failed    []string
+}
+
+// AuditEntry запись журнала аудита: кто, когда и какое действие выполнил,
+// включая неуспешные попытки
+type AuditEntry struct {
+	Sequence      uint64
+	Timestamp     time.Time
+	Actor         string
+	Action        string
+	AccountID     string
+	Amount        float64
+	Details       string
+	Success       bool
+	Error         string
+	CorrelationID string
+	// GrantID доверенность, по которой действовал Actor (пусто - действовал владелец)
+	GrantID string
+}
+
+// AliasKind вид псевдонима счета
+type AliasKind string
+
+const (
+	// PhoneAlias номер телефона в формате +<код страны><номер>
+	PhoneAlias AliasKind = "PHONE"
+	// NameAlias короткое имя из латинских букв, цифр и дефисов
+	NameAlias AliasKind = "NAME"
+)
+
+// AccountAlias псевдоним, по которому можно найти счет вместо его ID
+type AccountAlias struct {
+	Alias     string
+	Kind      AliasKind
+	AccountID string
+	CreatedAt time.Time
+}
+
+// AliasAction вид изменения псевдонима
+type AliasAction string
+
+const (
+	LinkAliasAction   AliasAction = "LINK"
+	UnlinkAliasAction AliasAction = "UNLINK"
+	RenameAliasAction AliasAction = "RENAME"
+)
+
+// AliasChange запись истории привязки псевдонимов для аудита.
+// При переименовании PreviousAlias содержит прежний псевдоним.
+type AliasChange struct {
+	Alias         string
+	PreviousAlias string
+	Kind          AliasKind
+	AccountID     string
+	Action        AliasAction
+	Actor         string
+	Timestamp     time.Time
+}
+
+// AuditFilter критерии выборки из журнала аудита; пустые поля не ограничивают выборку
+type AuditFilter struct {
+	Actor        string
+	AccountID    string
+	Action       string
+	FailuresOnly bool
+	From         time.Time
+	To           time.Time
+	Offset       int
+	Limit        int
+}
+
+// CreditStatus состояние входящего внешнего платежа
+type CreditStatus string
+
+const (
+	PendingCreditStatus  CreditStatus = "PENDING"
+	AcceptedCreditStatus CreditStatus = "ACCEPTED"
+	RejectedCreditStatus CreditStatus = "REJECTED"
+	ExpiredCreditStatus  CreditStatus = "EXPIRED"
+)
+
+// PendingCredit входящий внешний платеж, ожидающий приема или возврата отправителю.
+// Нулевой ExpiresAt у платежей, полученных до появления срока приема.
+type PendingCredit struct {
+	ID            string
+	Amount        float64
+	Sender        string
+	Reference     string
+	Status        CreditStatus
+	ReceivedAt    time.Time
+	ExpiresAt     time.Time
+	ResolvedAt    time.Time
+	TransactionID string
+}
+
+// Active сообщает, ждет ли платеж решения владельца на момент at
+func (c PendingCredit) Active(at time.Time) bool {
+	return c.Status == PendingCreditStatus && (c.ExpiresAt.IsZero() || at.Before(c.ExpiresAt))
+}
+
+// PendingTransferStatus состояние перевода с подтверждением
+type PendingTransferStatus string
+
+const (
+	PendingTransferHeld      PendingTransferStatus = "HELD"
+	PendingTransferCaptured  PendingTransferStatus = "CAPTURED"
+	PendingTransferCancelled PendingTransferStatus = "CANCELLED"
+	PendingTransferExpired   PendingTransferStatus = "EXPIRED"
+)
+
+// PendingTransfer перевод в два шага: при создании сумма с комиссией
+// удерживается на счете, а списывается только после подтверждения
+type PendingTransfer struct {
+	ID          string
+	ToAccountID string
+	Amount      float64
+	Fee         float64
+	Status      PendingTransferStatus
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	ResolvedAt  time.Time
+	// TransactionID транзакция перевода, проведенная при подтверждении
+	TransactionID string
+}
+
+// Active сообщает, удерживаются ли средства по переводу на момент at
+func (t PendingTransfer) Active(at time.Time) bool {
+	return t.Status == PendingTransferHeld && at.Before(t.ExpiresAt)
+}
+
+// ExpiredItem ожидающая операция, отмененная по истечении срока. Kind -
+// "transfer" для перевода с подтверждением или "credit" для входящего платежа.
+type ExpiredItem struct {
+	AccountID string
+	ItemID    string
+	Kind      string
+	Amount    float64
+}
+
+// HistoryIssue нарушение цепочки хешей на транзакции с индексом Index
+type HistoryIssue struct {
+	Index         int
+	TransactionID string
+	Problem       string
+}
+
+// HistoryReport результат проверки целостности истории счета: сколько
+// транзакций включено в цепочку хешей и какие нарушения найдены
+type HistoryReport struct {
+	AccountID    string
+	Transactions int
+	Sealed       int
+	Issues       []HistoryIssue
+}
+
+// Intact сообщает, что нарушений не найдено
+func (r HistoryReport) Intact() bool {
+	return len(r.Issues) == 0
+}
+
+// GrantScope объем доступа по доверенности
+type GrantScope string
+
+const (
+	// ViewGrant только просмотр счета и выписок
+	ViewGrant GrantScope = "VIEW"
+	// TransferGrant просмотр и переводы в пределах лимита доверенности
+	TransferGrant GrantScope = "TRANSFER"
+)
+
+// Valid сообщает, известен ли объем доступа
+func (s GrantScope) Valid() bool {
+	return s == ViewGrant || s == TransferGrant
+}
+
+// AccessGrant доверенность: владелец счета разрешает другому пользователю
+// доступ к счету на срок до ExpiresAt
+type AccessGrant struct {
+	ID          string
+	GranteeID   string
+	GranteeName string
+	Scope       GrantScope
+	// TransferLimit сколько всего можно перевести по доверенности, Transferred - сколько уже переведено
+	TransferLimit float64
+	Transferred   float64
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+	RevokedAt     time.Time
+}
+
+// Active сообщает, действует ли доверенность на момент at
+func (g AccessGrant) Active(at time.Time) bool {
+	return g.RevokedAt.IsZero() && at.Before(g.ExpiresAt)
+}
+
+// SpendingControls ограничения, которые родительский счет задает дочернему:
+// дневной потолок списаний (0 - без ограничения) и запрещенные категории
+type SpendingControls struct {
+	DailyCap          float64
+	BlockedCategories []string
+}
+
+// ChildAccount дочерний счет в списке родителя вместе с тратами за сегодня
+type ChildAccount struct {
+	AccountID  string
+	OwnerName  string
+	Controls   SpendingControls
+	SpentToday float64
+}
+
+// BenchOptions параметры нагрузочного прогона хранилища: число счетов,
+// длительность одной ступени, предельное число воркеров и целевой p99
+type BenchOptions struct {
+	Accounts   int
+	Duration   time.Duration
+	MaxWorkers int
+	P99Target  time.Duration
+}
+
+// BenchStep результат ступени нагрузочного прогона с фиксированным числом воркеров
+type BenchStep struct {
+	Workers int
+	Ops     int
+	Errors  int
+	TPS     float64
+	P50     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+}
+
+// Sustainable проверяет, что ступень прошла без ошибок и уложилась в целевой p99
+func (s BenchStep) Sustainable(target time.Duration) bool {
+	return s.Errors == 0 && s.P99 <= target
+}
+
+// BenchReport результат нагрузочного прогона: ступени и максимальная
+// устойчивая пропускная способность
+type BenchReport struct {
+	Steps  []BenchStep
+	MaxTPS float64
+}
+
+// CacheStats счетчики кэша счетов: попадания, промахи, вытеснения
+// по размеру и текущее число записей
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
+
+// HitRate доля попаданий среди всех обращений к кэшу
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// CashNote купюры одного номинала во взносе наличными;
+// Suspect - сколько из них отложено на проверку подлинности
+type CashNote struct {
+	Value   float64
+	Count   int
+	Suspect int
+}
+
+// CashHoldStatus состояние проверки подозрительных купюр
+type CashHoldStatus string
+
+const (
+	PendingHoldStatus     CashHoldStatus = "PENDING"
+	GenuineHoldStatus     CashHoldStatus = "GENUINE"
+	CounterfeitHoldStatus CashHoldStatus = "COUNTERFEIT"
+)
+
+// CashHold подозрительные купюры из взноса наличными: сумма не зачисляется
+// до проверки и проводится, только если купюры признаны подлинными
+type CashHold struct {
+	ID        string
+	AccountID string
+	Amount    float64
+	Notes     []CashNote
+	Status    CashHoldStatus
+	// DepositID транзакция взноса, из которого отложены купюры (пусто, если подозрительным оказался весь взнос)
+	DepositID     string
+	CreatedAt     time.Time
+	ResolvedAt    time.Time
+	TransactionID string
+}
+
+// OperationSwitch приостановка операций одного типа для всех счетов,
+// например исходящих переводов на время инцидента
+type OperationSwitch struct {
+	Operation TransactionType
+	// Message сообщение оператора, которое получает клиент вместе с ошибкой
+	Message    string
+	DisabledBy string
+	DisabledAt time.Time
+}
+
+// CashReport кассовый отчет за день: наличные поступления и выдачи
+type CashReport struct {
+	Date        time.Time
+	CashIn      float64
+	CashOut     float64
+	Deposits    int
+	Withdrawals int
+}
+
+// Net чистое изменение наличных в кассе за день
+func (r CashReport) Net() float64 {
+	return r.CashIn - r.CashOut
+}
+
+// MaintenanceFeeResult результат начисления платы за обслуживание по одному счету
+type MaintenanceFeeResult struct {
+	AccountID  string
+	Fee        float64
+	Charged    bool
+	SkipReason string
+}
+
+// ExportChunk результат выгрузки очередной части транзакций: число строк,
+// точка продолжения для следующего вызова и признак конца выгрузки
+type ExportChunk struct {
+	Rows       int
+	Checkpoint string
+	Done       bool
+}
+
+// EmailMessage письмо клиенту: текст и/или HTML
+type EmailMessage struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// StatementMailResult результат ежемесячной рассылки выписки по одному счету
+type StatementMailResult struct {
+	AccountID string
+	Email     string
+	Error     string
+}
+
+// StatementMailReport итог ежемесячной рассылки выписок за месяц Period (ГГГГ-ММ)
+type StatementMailReport struct {
+	Period  string
+	Results []StatementMailResult
+	Sent    int
+	Failed  int
+}
+
+// MaintenanceFeeReport итог пакетного начисления платы за обслуживание
+type MaintenanceFeeReport struct {
+	Period  string
+	DryRun  bool
+	Results []MaintenanceFeeResult
+	Charged int
+	Skipped int
+	Total   float64
+}
+
+// Loan условия кредита с ежемесячными аннуитетными платежами
+type Loan struct {
+	Principal float64
+	// AnnualRate годовая ставка в процентах
+	AnnualRate float64
+	TermMonths int
+	StartDate  time.Time
+	// RepaymentAccountID текущий счет, на который выдан кредит и с которого списываются платежи
+	RepaymentAccountID string
+	// PaymentsMade сколько платежей графика уже проведено
+	PaymentsMade int
+}
+
+// LoanPayment строка графика платежей по кредиту
+type LoanPayment struct {
+	Number    int
+	DueDate   time.Time
+	Payment   float64
+	Interest  float64
+	Principal float64
+	// Remaining остаток основного долга после платежа
+	Remaining float64
+}
+
+// LoanSummary состояние кредита: остаток основного долга и ближайший платеж
+// (NextPayment пуст, если кредит погашен)
+type LoanSummary struct {
+	AccountID          string
+	RemainingPrincipal float64
+	PaymentsMade       int
+	TermMonths         int
+	NextPayment        *LoanPayment
+}
+
+// LoanRepaymentResult результат платежа по графику одного кредита
+type LoanRepaymentResult struct {
+	LoanAccountID string
+	Payment       LoanPayment
+	Posted        bool
+	SkipReason    string
+}
+
+// LoanRepaymentReport итог пакетного проведения платежей по кредитам
+type LoanRepaymentReport struct {
+	AsOf    time.Time
+	Results []LoanRepaymentResult
+	Posted  int
+	Skipped int
+	Total   float64
+}
+
+// SweepPolicy правила перевода пыли - малых остатков закрытых и неактивных
+// счетов - на внутренний пул-счет. Пустой PoolAccountID отключает перевод.
+type SweepPolicy struct {
+	PoolAccountID string  `json:"pool_account"`
+	DustThreshold float64 `json:"dust_threshold"`
+	DormantDays   int     `json:"dormant_days"`
+}
+
+// ArchivePolicy правила архивации счетов без клиентских операций дольше
+// InactiveDays дней. Нулевой InactiveDays отключает архивацию.
+type ArchivePolicy struct {
+	InactiveDays int `json:"inactive_days"`
+}
+
+// ArchiveResult результат архивации одного неактивного счета
+type ArchiveResult struct {
+	AccountID    string
+	OwnerName    string
+	LastActivity time.Time
+	Archived     bool
+	SkipReason   string
+}
+
+// ArchiveReport отчет об архивации неактивных счетов
+type ArchiveReport struct {
+	InactiveDays int
+	DryRun       bool
+	Results      []ArchiveResult
+	Archived     int
+	Skipped      int
+}
+
+// SweepResult результат перевода остатка одного счета на пул-счет
+type SweepResult struct {
+	AccountID     string
+	Amount        float64
+	Swept         bool
+	SkipReason    string
+	TransactionID string
+}
+
+// SweepReport итог перевода остатков на пул-счет
+type SweepReport struct {
+	PoolAccountID string
+	DryRun        bool
+	Results       []SweepResult
+	Swept         int
+	Skipped       int
+	Total         float64
+}
+
+// TimelineKind вид события в хронологии счета
+type TimelineKind string
+
+const (
+	TransactionTimeline TimelineKind = "TRANSACTION"
+	StatusTimeline      TimelineKind = "STATUS"
+	LimitTimeline       TimelineKind = "LIMIT"
+	DisputeTimeline     TimelineKind = "DISPUTE"
+	AuditTimeline       TimelineKind = "AUDIT"
+)
+
+// Valid проверяет, что вид события известен
+func (k TimelineKind) Valid() bool {
+	switch k {
+	case TransactionTimeline, StatusTimeline, LimitTimeline, DisputeTimeline, AuditTimeline:
+		return true
+	default:
+		return false
+	}
+}
+
+// TimelineEntry событие в хронологии счета для службы поддержки.
+// Для транзакций Reference - ID транзакции, для записей аудита - ID корреляции.
+type TimelineEntry struct {
+	Timestamp time.Time
+	Kind      TimelineKind
+	Actor     string
+	Action    string
+	Amount    float64
+	Details   string
+	Error     string
+	Reference string
+}
+
+// FeeRecalculationResult пересчет одной списанной комиссии
+type FeeRecalculationResult struct {
+	AccountID    string
+	OwnerName    string
+	FeeID        string
+	OperationID  string
+	Charged      float64
+	Expected     float64
+	CorrectionID string
+	SkipReason   string
+}
+
+// Difference сумма корректировки: положительная - возврат клиенту,
+// отрицательная - доначисление
+func (r FeeRecalculationResult) Difference() float64 {
+	return r.Charged - r.Expected
+}
+
+// FeeRecalculationReport отчет о пересчете комиссий за период для оценки
+// влияния на клиентов
+type FeeRecalculationReport struct {
+	From      time.Time
+	To        time.Time
+	DryRun    bool
+	Results   []FeeRecalculationResult
+	Refunded  float64
+	Collected float64
+	Accounts  int
+}
+
+// Statement выписка за период: остатки на начало и конец и операции периода
+type Statement struct {
+	AccountID      string
+	OwnerName      string
+	From           time.Time
+	To             time.Time
+	OpeningBalance float64
+	ClosingBalance float64
+	Transactions   []Transaction
+	GeneratedAt    time.Time
+}
+
+// StatementLayout оформление выписки в одном формате: колонки таблицы
+// операций (date, type, message, note, amount, balance), ширина колонки
+// текстовой выписки, знаки после запятой в суммах (0 - два) и шаблон.
+// Шаблон задается текстом Template или файлом TemplateFile; без них
+// используется шаблон формата по умолчанию.
+type StatementLayout struct {
+	Columns      []string `json:"columns,omitempty"`
+	Width        int      `json:"width,omitempty"`
+	Precision    int      `json:"precision,omitempty"`
+	Template     string   `json:"template,omitempty"`
+	TemplateFile string   `json:"template_file,omitempty"`
+}
+
+// NewFeeEngineFlag флаг, включающий для счета правила комиссий fees_next
+const NewFeeEngineFlag = "new_fee_engine"
+
+// FeatureFlag настройка постепенного включения нового поведения: для всех
+// (Percent 100), для доли счетов или для перечисленных счетов и владельцев
+type FeatureFlag struct {
+	Enabled  bool     `json:"enabled"`
+	Percent  int      `json:"percent"`
+	Accounts []string `json:"accounts"`
+	Owners   []string `json:"owners"`
+}
+
+// ImportRowError ошибка в строке импортируемого CSV-файла
+type ImportRowError struct {
+	File string
+	Line int
+	Err  error
+}
+
+// Error возвращает ошибку с указанием файла и строки
+func (e ImportRowError) Error() string {
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+// Unwrap возвращает исходную ошибку строки
+func (e ImportRowError) Unwrap() error {
+	return e.Err
+}
+
+// ImportReport результат импорта счетов и транзакций из CSV
+type ImportReport struct {
+	Accounts     int
+	Transactions int
+	Errors       []ImportRowError
+	Committed    bool
+}
+
+// StatementDelivery выписка, отправляемая клиенту по внешнему каналу.
+// Если клиент загрузил ключ шифрования, Content пуст, а выписка лежит в Envelope.
+type StatementDelivery struct {
+	AccountID string             `json:"account_id"`
+	Format    ExportFormat       `json:"format"`
+	CreatedAt time.Time          `json:"created_at"`
+	Content   []byte             `json:"content,omitempty"`
+	Envelope  *EncryptedEnvelope `json:"envelope,omitempty"`
+}
+
+// EncryptedEnvelope данные, зашифрованные открытым ключом получателя:
+// эфемерный ключ X25519, из общего секрета выводится ключ AES-256-GCM
+type EncryptedEnvelope struct {
+	Algorithm    string `json:"algorithm"`
+	EphemeralKey []byte `json:"ephemeral_key"`
+	Nonce        []byte `json:"nonce"`
+	Ciphertext   []byte `json:"ciphertext"`
+}
+
+// RestoreReport результат восстановления из резервной копии. Пользователи,
+// уже существующие в хранилище под тем же именем, не перезаписываются: счета
+// из копии переходят к существующему пользователю. Счета с уже занятыми ID
+// пропускаются.
+type RestoreReport struct {
+	SchemaVersion   int
+	CreatedAt       time.Time
+	DryRun          bool
+	Users           int
+	ExistingUsers   []string
+	UserConflicts   []string
+	Accounts        int
+	SkippedAccounts []string
+	Events          int
+}
+
+// ConsistencyIssue несогласованность данных счета, найденная при проверке
+type ConsistencyIssue struct {
+	AccountID   string
+	Problem     string
+	Quarantined bool
+}
+
+// StorageDrift расхождение счета между основным хранилищем и репликой
+type StorageDrift struct {
+	AccountID        string
+	Problem          string
+	PrimaryBalance   float64
+	ReplicaBalance   float64
+	PrimaryTxCount   int
+	ReplicaTxCount   int
+	MissingInPrimary bool
+	MissingInReplica bool
+}
+
+// DriftReport результат сверки двух хранилищ
+type DriftReport struct {
+	CheckedAt       time.Time
+	PrimaryAccounts int
+	ReplicaAccounts int
+	Drifts          []StorageDrift
+}
+
+// Clean сообщает, что хранилища совпадают
+func (r DriftReport) Clean() bool {
+	return len(r.Drifts) == 0
+}
+
+// Role роль пользователя
+type Role string
+
+const (
+	CustomerRole Role = "CUSTOMER"
+	AdminRole    Role = "ADMIN"
+)
+
+// User структура пользователя
+type User struct {
+	ID           string
+	Username     string
+	Role         Role
+	PasswordHash []byte
+	PasswordSalt []byte
+	CreatedAt    time.Time
+	Preferences  UserPreferences
+	// LastAccountID счет, выбранный в прошлом сеансе; при входе предлагается снова
+	LastAccountID string
+}
+
+// DateFormat формат вывода дат в интерфейсе
+type DateFormat string
+
+const (
+	ISODateFormat     DateFormat = "iso"
+	RussianDateFormat DateFormat = "ru"
+	USDateFormat      DateFormat = "us"
+)
+
+// Layout возвращает шаблон форматирования времени; неизвестный формат выводится как ISO
+func (f DateFormat) Layout() string {
+	switch f {
+	case RussianDateFormat:
+		return "02.01.2006 15:04:05"
+	case USDateFormat:
+		return "01/02/2006 03:04:05 PM"
+	default:
+		return "2006-01-02 15:04:05"
+	}
+}
+
+// Valid проверяет, что формат даты известен
+func (f DateFormat) Valid() bool {
+	return f == ISODateFormat || f == RussianDateFormat || f == USDateFormat
+}
+
+// UserPreferences настройки отображения пользователя, применяемые при входе
+type UserPreferences struct {
+	// Language язык интерфейса; пустой - язык приложения из конфигурации
+	Language         string
+	DateFormat       DateFormat
+	DefaultAccountID string
+	Color            bool
+	StatementFormat  ExportFormat
+}
+
+// DefaultPreferences настройки отображения нового пользователя
+func DefaultPreferences() UserPreferences {
+	return UserPreferences{
+		DateFormat:      ISODateFormat,
+		StatementFormat: CSVFormat,
+	}
+}
+
+// NewUser создает нового пользователя с указанной ролью
+func NewUser(username string, role Role, ids IDGenerator, clock Clock) *User {
+	return &User{
+		ID:          ids.NewID("USR"),
+		Username:    username,
+		Role:        role,
+		CreatedAt:   clock.Now(),
+		Preferences: DefaultPreferences(),
+	}
+}
+
+// IsAdmin проверяет, является ли пользователь администратором
+func (u *User) IsAdmin() bool {
+	return u.Role == AdminRole
+}
+
+// Дневные лимиты новых счетов по умолчанию
+const (
+	DefaultDailyAmountLimit = 100000
+	DefaultDailyCountLimit  = 20
+)
+
+// DailyAllowance использование дневных лимитов счета
+type DailyAllowance struct {
+	AmountLimit     float64
+	AmountUsed      float64
+	AmountRemaining float64
+	CountLimit      int
+	CountUsed       int
+	CountRemaining  int
+}
+
+// NewAccount создает новый счет, временем открытия служит время clock
+func NewAccount(ownerName string, ids IDGenerator, clock Clock) *Account {
+	return &Account{
+		ID:               ids.NewID("ACC"),
+		OwnerName:        ownerName,
+		Balance:          0,
+		CreatedAt:        clock.Now(),
+		Status:           ActiveStatus,
+		DailyAmountLimit: DefaultDailyAmountLimit,
+		DailyCountLimit:  DefaultDailyCountLimit,
+	}
+}
+
+// PageBounds возвращает границы страницы [start, end) для набора из total элементов.
+// Нулевой или отрицательный limit означает "до конца набора".
+func PageBounds(total, offset, limit int) (int, int) {
+	start := offset
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+
+	end := total
+	if limit > 0 && start+limit < total {
+		end = start + limit
+	}
+
+	return start, end
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// EventBus шина уведомлений о событиях по счетам. Уведомления доставляются
+// подписчикам синхронно в порядке подписки; ошибка одного подписчика
+// не мешает доставке остальным.
+type EventBus struct {
+	observers []interfaces.Observer
+	logger    *slog.Logger
+	clock     models.Clock
+}
+
+// NewEventBus создает шину уведомлений; ошибки доставки пишутся в logger,
+// время уведомлений без метки берется из clock
+func NewEventBus(logger *slog.Logger, clock models.Clock) *EventBus {
+	return &EventBus{
+		logger: logger,
+		clock:  clock,
+	}
+}
+
+// Subscribe добавляет подписчика
+func (b *EventBus) Subscribe(observer interfaces.Observer) {
+	b.observers = append(b.observers, observer)
+}
+
+// Publish рассылает уведомление всем подписчикам
+func (b *EventBus) Publish(ctx context.Context, notification models.Notification) {
+	if notification.ID == "" {
+		notification.ID = models.DefaultIDGenerator.NewID("EV")
+	}
+	if notification.Timestamp.IsZero() {
+		notification.Timestamp = b.clock.Now()
+	}
+
+	for _, observer := range b.observers {
+		if err := observer.Notify(ctx, notification); err != nil && b.logger != nil {
+			b.logger.WarnContext(ctx, "ошибка доставки уведомления",
+				"type", string(notification.Type),
+				"account_id", notification.AccountID,
+				"error", err.Error())
+		}
+	}
+}
+
+// WithEventBus подключает публикацию уведомлений об операциях счета
+func WithEventBus(bus *EventBus) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.events = bus
+	}
+}
+
+// publish отправляет уведомление об успешной операции счета, если шина подключена
+func (s *AccountServiceImpl) publish(ctx context.Context, notificationType models.NotificationType, transactionID string, amount float64, counterpartyID string) {
+	if s.events == nil {
+		return
+	}
+
+	s.events.Publish(ctx, models.Notification{
+		ID:             s.newID("EV"),
+		Type:           notificationType,
+		AccountID:      s.account.ID,
+		TransactionID:  transactionID,
+		CounterpartyID: counterpartyID,
+		Amount:         amount,
+	})
+}
+
+// ConsoleNotifier выводит уведомления об операциях на сумму от порога
+type ConsoleNotifier struct {
+	w         io.Writer
+	threshold float64
+}
+
+// NewConsoleNotifier создает подписчика, печатающего уведомления об
+// операциях на сумму не меньше threshold
+func NewConsoleNotifier(w io.Writer, threshold float64) interfaces.Observer {
+	return &ConsoleNotifier{
+		w:         w,
+		threshold: threshold,
+	}
+}
+
+// Notify печатает уведомление о крупной операции
+func (n *ConsoleNotifier) Notify(ctx context.Context, notification models.Notification) error {
+	if notification.Type == models.AccountCreatedNotification || notification.Amount < n.threshold {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(n.w, "[уведомление] %s по счету %s на %.2f\n",
+		notification.Type, notification.AccountID, notification.Amount)
+	return err
+}
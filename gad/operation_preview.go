@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+)
+
+// PreviewDeposit рассчитывает пополнение с теми же проверками, что и
+// Deposit, не изменяя счет. Оценка риска не выполняется.
+func (s *AccountServiceImpl) PreviewDeposit(ctx context.Context, amount float64, source models.DepositSource) (models.OperationPreview, error) {
+	if err := s.checkPreviewable(ctx, models.DepositTransaction, amount); err != nil {
+		return models.OperationPreview{}, err
+	}
+
+	if !source.Valid() {
+		return models.OperationPreview{}, errors.ErrInvalidDepositSource
+	}
+
+	if _, err := transactionDetails(ctx); err != nil {
+		return models.OperationPreview{}, err
+	}
+
+	fee, err := s.calculateFee(ctx, models.DepositTransaction, amount)
+	if err != nil {
+		return models.OperationPreview{}, err
+	}
+
+	if err := s.checkLimitRules(models.DepositTransaction, amount, ""); err != nil {
+		return models.OperationPreview{}, err
+	}
+
+	return s.preview(models.DepositTransaction, amount, fee, amount-fee, ""), nil
+}
+
+// PreviewWithdraw рассчитывает снятие с теми же проверками, что и Withdraw,
+// не изменяя счет
+func (s *AccountServiceImpl) PreviewWithdraw(ctx context.Context, amount float64) (models.OperationPreview, error) {
+	if err := s.checkPreviewable(ctx, models.WithdrawTransaction, amount); err != nil {
+		return models.OperationPreview{}, err
+	}
+
+	fee, err := s.previewSpending(ctx, models.WithdrawTransaction, amount, "")
+	if err != nil {
+		return models.OperationPreview{}, err
+	}
+
+	return s.preview(models.WithdrawTransaction, amount, fee, -amount-fee, ""), nil
+}
+
+// PreviewTransfer рассчитывает перевод с теми же проверками, что и Transfer,
+// не изменяя ни один из счетов
+func (s *AccountServiceImpl) PreviewTransfer(ctx context.Context, to *models.Account, amount float64) (models.OperationPreview, error) {
+	if err := s.checkPreviewable(ctx, models.TransferTransaction, amount); err != nil {
+		return models.OperationPreview{}, err
+	}
+
+	to, err := s.storage.LoadAccount(ctx, to.ID)
+	if err != nil {
+		return models.OperationPreview{}, err
+	}
+
+	if s.account.ID == to.ID {
+		return models.OperationPreview{}, errors.ErrSameAccountTransfer
+	}
+
+	if err := checkOperable(to); err != nil {
+		return models.OperationPreview{}, err
+	}
+
+	fee, err := s.previewSpending(ctx, models.TransferTransaction, amount, to.ID)
+	if err != nil {
+		return models.OperationPreview{}, err
+	}
+
+	return s.preview(models.TransferTransaction, amount, fee, -amount-fee, to.ID), nil
+}
+
+// checkPreviewable выполняет общие для всех операций проверки
+func (s *AccountServiceImpl) checkPreviewable(ctx context.Context, txType models.TransactionType, amount float64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := checkOperable(s.account); err != nil {
+		return err
+	}
+
+	if err := s.checkSwitch(ctx, txType); err != nil {
+		return err
+	}
+
+	if amount <= 0 {
+		return errors.ErrInvalidAmount
+	}
+
+	return nil
+}
+
+// previewSpending проверяет списание: комиссию, средства и лимиты. В отличие
+// от проведения операции, родитель дочернего счета не уведомляется.
+func (s *AccountServiceImpl) previewSpending(ctx context.Context, txType models.TransactionType, amount float64, counterparty string) (float64, error) {
+	details, err := transactionDetails(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	fee, err := s.calculateFee(ctx, txType, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.checkFunds(amount + fee); err != nil {
+		return 0, err
+	}
+
+	if err := s.checkDailyLimits(amount); err != nil {
+		return 0, err
+	}
+
+	if err := s.spendingControlsError(amount, details); err != nil {
+		return 0, err
+	}
+
+	if err := s.checkLimitRules(txType, amount, counterparty); err != nil {
+		return 0, err
+	}
+
+	return fee, nil
+}
+
+// preview формирует расчет операции, меняющей баланс на delta
+func (s *AccountServiceImpl) preview(txType models.TransactionType, amount, fee, delta float64, counterparty string) models.OperationPreview {
+	return models.OperationPreview{
+		Type:           txType,
+		Amount:         amount,
+		Fee:            fee,
+		CounterpartyID: counterparty,
+		BalanceBefore:  s.account.Balance,
+		BalanceAfter:   s.account.Balance + delta,
+		AvailableAfter: s.account.AvailableFundsAt(s.now()) + delta,
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// suspendableOperations операции, которые администратор может приостановить
+var suspendableOperations = map[models.TransactionType]bool{
+	models.DepositTransaction:  true,
+	models.WithdrawTransaction: true,
+	models.TransferTransaction: true,
+}
+
+// OperationSwitchesImpl реализация OperationSwitches в памяти: приостановка
+// действует для всех сессий приложения до явного возобновления
+type OperationSwitchesImpl struct {
+	mu       sync.RWMutex
+	disabled map[models.TransactionType]models.OperationSwitch
+	audit    interfaces.AuditLogger
+	clock    models.Clock
+}
+
+// NewOperationSwitches создает переключатели операций
+func NewOperationSwitches(audit interfaces.AuditLogger, clock models.Clock) interfaces.OperationSwitches {
+	return &OperationSwitchesImpl{
+		disabled: make(map[models.TransactionType]models.OperationSwitch),
+		audit:    audit,
+		clock:    clock,
+	}
+}
+
+// Disable приостанавливает операции типа operation. Сообщение оператора
+// возвращается клиентам вместе с ErrOperationDisabled.
+func (s *OperationSwitchesImpl) Disable(ctx context.Context, operation models.TransactionType, message string) (err error) {
+	defer func() {
+		s.auditSwitch(ctx, "disable_operation", operation, message, err)
+	}()
+
+	if !suspendableOperations[operation] {
+		return errors.ErrNotSuspendable
+	}
+
+	actor, _ := ActorFromContext(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.disabled[operation] = models.OperationSwitch{
+		Operation:  operation,
+		Message:    message,
+		DisabledBy: actor,
+		DisabledAt: s.clock.Now(),
+	}
+
+	return nil
+}
+
+// Enable возобновляет операции типа operation
+func (s *OperationSwitchesImpl) Enable(ctx context.Context, operation models.TransactionType) (err error) {
+	defer func() {
+		s.auditSwitch(ctx, "enable_operation", operation, "", err)
+	}()
+
+	if !suspendableOperations[operation] {
+		return errors.ErrNotSuspendable
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.disabled, operation)
+
+	return nil
+}
+
+// Check возвращает ErrOperationDisabled с сообщением оператора, если
+// операции типа operation приостановлены
+func (s *OperationSwitchesImpl) Check(ctx context.Context, operation models.TransactionType) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	switched, ok := s.disabled[operation]
+	if !ok {
+		return nil
+	}
+
+	if switched.Message == "" {
+		return errors.ErrOperationDisabled
+	}
+
+	return fmt.Errorf("%w: %s", errors.ErrOperationDisabled, switched.Message)
+}
+
+// List возвращает приостановленные операции в порядке приостановки
+func (s *OperationSwitchesImpl) List(ctx context.Context) []models.OperationSwitch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	switches := make([]models.OperationSwitch, 0, len(s.disabled))
+	for _, switched := range s.disabled {
+		switches = append(switches, switched)
+	}
+
+	sort.Slice(switches, func(i, j int) bool {
+		return switches[i].DisabledAt.Before(switches[j].DisabledAt)
+	})
+
+	return switches
+}
+
+// auditSwitch записывает изменение переключателя в журнал аудита
+func (s *OperationSwitchesImpl) auditSwitch(ctx context.Context, action string, operation models.TransactionType, message string, err error) {
+	details := string(operation)
+	if message != "" {
+		details += ": " + message
+	}
+
+	recordAudit(ctx, s.audit, models.AuditEntry{
+		Action:  action,
+		Details: details,
+	}, err)
+}
+
+// WithOperationSwitches подключает приостановку операций администратором
+func WithOperationSwitches(switches interfaces.OperationSwitches) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.switches = switches
+	}
+}
+
+// checkSwitch проверяет, не приостановлены ли операции типа operation
+func (s *AccountServiceImpl) checkSwitch(ctx context.Context, operation models.TransactionType) error {
+	if s.switches == nil {
+		return nil
+	}
+
+	return s.switches.Check(ctx, operation)
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"bankapp/models"
+)
+
+// manageOperationSwitches показывает приостановленные операции и позволяет
+// приостановить или возобновить операции выбранного типа для всех счетов
+func (app *BankApp) manageOperationSwitches(ctx context.Context) {
+	app.printHeader("Приостановка операций")
+	switches := app.switches.List(ctx)
+	if len(switches) == 0 {
+		app.println("Все операции доступны")
+	}
+	for _, switched := range switches {
+		app.printf("%s  с %s  (%s)  %s\n", switched.Operation, app.formatTime(switched.DisabledAt), switched.DisabledBy, switched.Message)
+	}
+
+	app.println("1. Приостановить операции")
+	app.println("2. Возобновить операции")
+	app.print("Выберите опцию: ")
+	app.scanner.Scan()
+	choice := strings.TrimSpace(app.scanner.Text())
+	if choice != "1" && choice != "2" {
+		app.println("Неверный выбор. Попробуйте снова.")
+		return
+	}
+
+	app.print("Тип операции (DEPOSIT, WITHDRAW, TRANSFER): ")
+	app.scanner.Scan()
+	operation := models.TransactionType(strings.ToUpper(strings.TrimSpace(app.scanner.Text())))
+
+	if choice == "2" {
+		if err := app.switches.Enable(ctx, operation); err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+		app.printf("Операции %s возобновлены\n", operation)
+		return
+	}
+
+	app.print("Сообщение для клиентов: ")
+	app.scanner.Scan()
+	message := strings.TrimSpace(app.scanner.Text())
+
+	if err := app.switches.Disable(ctx, operation, message); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+	app.printf("Операции %s приостановлены\n", operation)
+}
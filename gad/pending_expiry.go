@@ -0,0 +1,107 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"time"
+)
+
+// ExpirePendingItems отменяет на всех счетах ожидающие операции с истекшим
+// сроком: снимает удержания по неподтвержденным переводам и возвращает
+// отправителям непринятые входящие платежи. Обе стороны перевода и владелец
+// счета с входящим платежом получают уведомление через events, если она задана.
+func ExpirePendingItems(ctx context.Context, storage interfaces.Storage, events *EventBus, now time.Time) ([]models.ExpiredItem, error) {
+	accounts, _, err := storage.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []models.ExpiredItem
+	for _, account := range accounts {
+		if err := ctx.Err(); err != nil {
+			return expired, err
+		}
+
+		service := &AccountServiceImpl{
+			account: account,
+			storage: storage,
+			events:  events,
+		}
+
+		items := service.expirePending(ctx, now)
+		if len(items) == 0 {
+			continue
+		}
+
+		if err := storage.SaveAccount(ctx, account); err != nil {
+			return expired, err
+		}
+		expired = append(expired, items...)
+	}
+
+	return expired, nil
+}
+
+// expirePending помечает просроченные удержания и входящие платежи счета
+// и уведомляет о них; возвращает отмененные операции
+func (s *AccountServiceImpl) expirePending(ctx context.Context, now time.Time) []models.ExpiredItem {
+	var expired []models.ExpiredItem
+
+	for i := range s.account.PendingTransfers {
+		transfer := &s.account.PendingTransfers[i]
+		if transfer.Status != models.PendingTransferHeld || transfer.Active(now) {
+			continue
+		}
+
+		transfer.Status = models.PendingTransferExpired
+		transfer.ResolvedAt = transfer.ExpiresAt
+		expired = append(expired, models.ExpiredItem{
+			AccountID: s.account.ID,
+			ItemID:    transfer.ID,
+			Kind:      "transfer",
+			Amount:    transfer.Amount,
+		})
+
+		reason := "перевод " + transfer.ID + " не подтвержден в срок, удержание снято"
+		s.publishExpired(ctx, s.account.ID, transfer.ToAccountID, transfer.Amount, reason)
+		s.publishExpired(ctx, transfer.ToAccountID, s.account.ID, transfer.Amount, reason)
+	}
+
+	for i := range s.account.PendingCredits {
+		credit := &s.account.PendingCredits[i]
+		if credit.Status != models.PendingCreditStatus || credit.Active(now) {
+			continue
+		}
+
+		credit.Status = models.ExpiredCreditStatus
+		credit.ResolvedAt = credit.ExpiresAt
+		expired = append(expired, models.ExpiredItem{
+			AccountID: s.account.ID,
+			ItemID:    credit.ID,
+			Kind:      "credit",
+			Amount:    credit.Amount,
+		})
+
+		reason := "входящий платеж " + credit.ID + " не принят в срок и возвращен отправителю " + credit.Sender
+		s.publishExpired(ctx, s.account.ID, "", credit.Amount, reason)
+	}
+
+	return expired
+}
+
+// publishExpired уведомляет счет accountID об отмене просроченной операции
+func (s *AccountServiceImpl) publishExpired(ctx context.Context, accountID, counterpartyID string, amount float64, reason string) {
+	if s.events == nil {
+		return
+	}
+
+	s.events.Publish(ctx, models.Notification{
+		ID:             s.newID("EV"),
+		Type:           models.PendingExpiredNotification,
+		AccountID:      accountID,
+		CounterpartyID: counterpartyID,
+		Amount:         amount,
+		Reason:         reason,
+	})
+}
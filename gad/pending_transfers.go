@@ -0,0 +1,180 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// pendingTransferTTL срок, в течение которого перевод можно подтвердить;
+// после него удержание снимается
+const pendingTransferTTL = 24 * time.Hour
+
+// GetAvailableBalance баланс за вычетом удержаний по неподтвержденным переводам
+func (s *AccountServiceImpl) GetAvailableBalance(ctx context.Context) float64 {
+	return s.account.Balance - s.account.HeldFunds(s.now())
+}
+
+// InitiateTransfer создает перевод с подтверждением: сумма с комиссией
+// удерживается на счете и списывается только при ConfirmTransfer
+func (s *AccountServiceImpl) InitiateTransfer(ctx context.Context, to *models.Account, amount float64) (transfer models.PendingTransfer, err error) {
+	defer func() {
+		s.auditOperation(ctx, "initiate_transfer", amount, "перевод "+transfer.ID+" на "+to.ID, err)
+	}()
+
+	if err := checkOperable(s.account); err != nil {
+		return models.PendingTransfer{}, err
+	}
+
+	if err := s.checkSwitch(ctx, models.TransferTransaction); err != nil {
+		return models.PendingTransfer{}, err
+	}
+
+	if amount <= 0 {
+		return models.PendingTransfer{}, errors.ErrInvalidAmount
+	}
+
+	if s.account.ID == to.ID {
+		return models.PendingTransfer{}, errors.ErrSameAccountTransfer
+	}
+
+	recipient, err := s.storage.LoadAccount(ctx, to.ID)
+	if err != nil {
+		return models.PendingTransfer{}, err
+	}
+
+	if err := checkOperable(recipient); err != nil {
+		return models.PendingTransfer{}, err
+	}
+
+	fee, err := s.calculateFee(ctx, models.TransferTransaction, amount)
+	if err != nil {
+		return models.PendingTransfer{}, err
+	}
+
+	now := s.now()
+	s.expirePending(ctx, now)
+
+	if err := s.checkFunds(amount + fee); err != nil {
+		return models.PendingTransfer{}, err
+	}
+
+	transfer = models.PendingTransfer{
+		ID:          s.newID("PT"),
+		ToAccountID: recipient.ID,
+		Amount:      amount,
+		Fee:         fee,
+		Status:      models.PendingTransferHeld,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(pendingTransferTTL),
+	}
+	s.account.PendingTransfers = append(s.account.PendingTransfers, transfer)
+
+	return transfer, s.saveAccount(ctx)
+}
+
+// ConfirmTransfer снимает удержание и проводит перевод со всеми проверками
+// лимитов и риска на момент подтверждения. Перевод, отметка о подтверждении
+// и ссылка на транзакцию сохраняются одной записью.
+func (s *AccountServiceImpl) ConfirmTransfer(ctx context.Context, transferID string) (result models.OperationResult, err error) {
+	var toID string
+	var amount float64
+	defer func() {
+		s.logOperation(ctx, "confirm_transfer", amount, err, slog.String("to_account_id", toID))
+		s.auditOperation(ctx, "confirm_transfer", 0, "перевод "+transferID, err)
+		if err == nil {
+			s.publish(ctx, models.TransferCompletedNotification, result.TransactionID, amount, toID)
+		}
+	}()
+
+	err = s.retryOnConflict(ctx, func() error {
+		transfer, err := s.findPendingTransfer(ctx, transferID)
+		if err != nil {
+			return err
+		}
+		toID, amount = transfer.ToAccountID, transfer.Amount
+
+		to, err := s.storage.LoadAccount(ctx, toID)
+		if err != nil {
+			return err
+		}
+
+		// Удержание снимается до перевода, иначе удержанная сумма не считается доступной
+		transfer.Status = models.PendingTransferCaptured
+		transfer.ResolvedAt = s.now()
+
+		result, err = s.transfer(ctx, to, amount, func(transaction models.Transaction) {
+			transfer.TransactionID = transaction.ID
+		})
+		if err != nil {
+			// Если запись не удалась, счет уже перечитан; иначе возвращаем удержание
+			transfer.Status = models.PendingTransferHeld
+			transfer.ResolvedAt = time.Time{}
+			transfer.TransactionID = ""
+		}
+		return err
+	})
+
+	return result, err
+}
+
+// CancelTransfer отменяет перевод и снимает удержание
+func (s *AccountServiceImpl) CancelTransfer(ctx context.Context, transferID string) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "cancel_transfer", 0, "перевод "+transferID, err)
+	}()
+
+	transfer, err := s.findPendingTransfer(ctx, transferID)
+	if err != nil {
+		return err
+	}
+
+	transfer.Status = models.PendingTransferCancelled
+	transfer.ResolvedAt = s.now()
+
+	return s.saveAccount(ctx)
+}
+
+// ListPendingTransfers возвращает переводы, ожидающие подтверждения
+func (s *AccountServiceImpl) ListPendingTransfers(ctx context.Context) []models.PendingTransfer {
+	now := s.now()
+
+	var pending []models.PendingTransfer
+	for _, transfer := range s.account.PendingTransfers {
+		if transfer.Active(now) {
+			pending = append(pending, transfer)
+		}
+	}
+
+	return pending
+}
+
+// findPendingTransfer ищет перевод, ожидающий подтверждения. Просроченный
+// перевод помечается и сохраняется, вызывающий получает ErrTransferExpired.
+func (s *AccountServiceImpl) findPendingTransfer(ctx context.Context, transferID string) (*models.PendingTransfer, error) {
+	if len(s.expirePending(ctx, s.now())) > 0 {
+		if err := s.saveAccount(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range s.account.PendingTransfers {
+		transfer := &s.account.PendingTransfers[i]
+		if transfer.ID != transferID {
+			continue
+		}
+
+		switch transfer.Status {
+		case models.PendingTransferHeld:
+			return transfer, nil
+		case models.PendingTransferExpired:
+			return nil, errors.ErrTransferExpired
+		default:
+			return nil, errors.ErrTransferResolved
+		}
+	}
+
+	return nil, errors.ErrTransferNotFound
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"bankapp/services"
+	"context"
+	"strings"
+)
+
+// expirePendingItems при запуске отменяет ожидающие операции с истекшим
+// сроком; стороны получают уведомления через шину событий
+func (app *BankApp) expirePendingItems(ctx context.Context) {
+	expired, err := services.ExpirePendingItems(ctx, app.storage, app.events, app.clock.Now())
+	if err != nil {
+		app.printf("Ошибка при отмене просроченных операций: %v\n", err)
+	}
+
+	for _, item := range expired {
+		switch item.Kind {
+		case "transfer":
+			app.printf("Перевод %s по счету %s не подтвержден в срок, удержание %.2f снято\n", item.ItemID, item.AccountID, item.Amount)
+		case "credit":
+			app.printf("Платеж %s на счет %s не принят в срок, %.2f возвращено отправителю\n", item.ItemID, item.AccountID, item.Amount)
+		}
+	}
+}
+
+// managePendingTransfers показывает переводы, ожидающие подтверждения, и
+// позволяет создать, подтвердить или отменить перевод
+func (app *BankApp) managePendingTransfers(ctx context.Context) {
+	transfers := app.currentAccount.ListPendingTransfers(ctx)
+	if len(transfers) == 0 {
+		app.println("Переводов, ожидающих подтверждения, нет")
+	} else {
+		app.printHeader("Переводы с подтверждением")
+		for _, transfer := range transfers {
+			app.printf("%s | %s | %.2f (комиссия %.2f) | на: %s | до %s\n",
+				transfer.ID, app.formatTime(transfer.CreatedAt), transfer.Amount, transfer.Fee,
+				transfer.ToAccountID, app.formatTime(transfer.ExpiresAt))
+		}
+	}
+
+	app.println("1. Создать перевод")
+	app.println("2. Подтвердить перевод")
+	app.println("3. Отменить перевод")
+	app.println("4. Назад")
+	app.print("Выберите опцию: ")
+
+	app.scanner.Scan()
+	choice := strings.TrimSpace(app.scanner.Text())
+
+	switch choice {
+	case "1":
+		app.initiateTransfer(ctx)
+	case "2", "3":
+		app.print("Введите ID перевода: ")
+		app.scanner.Scan()
+		transferID := strings.TrimSpace(app.scanner.Text())
+		if choice == "3" {
+			if err := app.currentAccount.CancelTransfer(ctx, transferID); err != nil {
+				app.printf("Ошибка: %v\n", err)
+				return
+			}
+			app.println("Перевод отменен, удержание снято")
+			return
+		}
+
+		result, err := app.currentAccount.ConfirmTransfer(app.withTransactionDetails(ctx), transferID)
+		if err != nil {
+			app.printf("Ошибка при переводе: %v\n", err)
+			return
+		}
+		app.printf("Перевод %s подтвержден\n", transferID)
+		app.printReceipt(result)
+	case "4":
+		return
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+	}
+}
+
+// initiateTransfer создает перевод, сумма которого удерживается до подтверждения
+func (app *BankApp) initiateTransfer(ctx context.Context) {
+	amount, err := app.readAmount("Введите сумму для перевода: ")
+	if err != nil {
+		return
+	}
+
+	app.print("Введите ID, псевдоним или телефон получателя: ")
+	app.scanner.Scan()
+	toAccountID := strings.TrimSpace(app.scanner.Text())
+
+	toAccount, err := app.aliases.Resolve(ctx, toAccountID)
+	if err != nil {
+		app.auditAction(ctx, "lookup_account", toAccountID, err)
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	transfer, err := app.currentAccount.InitiateTransfer(ctx, toAccount, amount)
+	if err != nil {
+		app.printf("Ошибка при переводе: %v\n", err)
+		return
+	}
+
+	app.printf("Перевод %s создан, %.2f удержано до %s\n", transfer.ID, transfer.Amount+transfer.Fee, app.formatTime(transfer.ExpiresAt))
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"time"
+)
+
+const (
+	// MaxPINAttempts количество неверных попыток до блокировки счета
+	MaxPINAttempts = 3
+	// PINLockoutDuration длительность блокировки после исчерпания попыток
+	PINLockoutDuration = 15 * time.Minute
+
+	secretIterations = 600000
+	secretKeyLength  = 32
+	secretSaltLength = 16
+)
+
+// SetPIN проверяет формат PIN-кода и сохраняет на счете его хеш
+func SetPIN(account *models.Account, pin string) error {
+	if !isValidPIN(pin) {
+		return errors.ErrWeakPIN
+	}
+
+	salt, err := newSalt()
+	if err != nil {
+		return err
+	}
+
+	hash, err := hashSecret(pin, salt)
+	if err != nil {
+		return err
+	}
+
+	account.PINSalt = salt
+	account.PINHash = hash
+	account.FailedPINAttempts = 0
+	account.LockedUntil = time.Time{}
+
+	return nil
+}
+
+// Authenticate проверяет PIN-код счета. Неверные попытки учитываются
+// на счете, после MaxPINAttempts подряд счет блокируется на PINLockoutDuration
+// от текущего времени clock.
+func Authenticate(ctx context.Context, storage interfaces.Storage, clock models.Clock, account *models.Account, pin string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(account.PINHash) == 0 {
+		return errors.ErrPINNotSet
+	}
+
+	if clock.Now().Before(account.LockedUntil) {
+		return errors.ErrAccountLocked
+	}
+
+	hash, err := hashSecret(pin, account.PINSalt)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare(hash, account.PINHash) != 1 {
+		account.FailedPINAttempts++
+		if account.FailedPINAttempts >= MaxPINAttempts {
+			account.FailedPINAttempts = 0
+			account.LockedUntil = clock.Now().Add(PINLockoutDuration)
+		}
+
+		if err := storage.SaveAccount(ctx, account); err != nil {
+			return err
+		}
+
+		return errors.ErrInvalidPIN
+	}
+
+	if account.FailedPINAttempts > 0 {
+		account.FailedPINAttempts = 0
+		return storage.SaveAccount(ctx, account)
+	}
+
+	return nil
+}
+
+// ChangePIN смена PIN-кода после проверки текущего
+func (s *AccountServiceImpl) ChangePIN(ctx context.Context, oldPIN, newPIN string) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "change_pin", 0, "", err)
+	}()
+
+	if err := Authenticate(ctx, s.storage, s.clock, s.account, oldPIN); err != nil {
+		return err
+	}
+
+	if err := SetPIN(s.account, newPIN); err != nil {
+		return err
+	}
+
+	return s.saveAccount(ctx)
+}
+
+// hashSecret вычисляет PBKDF2-SHA256 от PIN-кода или пароля с солью
+func hashSecret(secret string, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, secret, salt, secretIterations, secretKeyLength)
+}
+
+// newSalt генерирует случайную соль для хеширования
+func newSalt() ([]byte, error) {
+	salt := make([]byte, secretSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return salt, nil
+}
+
+// isValidPIN проверяет, что PIN-код состоит из 4-6 цифр
+func isValidPIN(pin string) bool {
+	if len(pin) < 4 || len(pin) > 6 {
+		return false
+	}
+
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/i18n"
+	"bankapp/models"
+	"context"
+)
+
+// SavePreferences проверяет и сохраняет настройки отображения пользователя.
+// Счетом по умолчанию может быть только счет, принадлежащий пользователю.
+func (s *AuthServiceImpl) SavePreferences(ctx context.Context, userID string, prefs models.UserPreferences) (err error) {
+	defer func() {
+		recordAudit(ctx, s.audit, models.AuditEntry{Action: "save_preferences"}, err)
+	}()
+
+	user, err := s.storage.LoadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if (prefs.Language != "" && !i18n.Supported(prefs.Language)) || !prefs.DateFormat.Valid() {
+		return errors.ErrInvalidPreferences
+	}
+
+	if prefs.StatementFormat != models.CSVFormat && prefs.StatementFormat != models.JSONFormat {
+		return errors.ErrUnsupportedFormat
+	}
+
+	if prefs.DefaultAccountID != "" {
+		account, err := s.storage.LoadAccount(ctx, prefs.DefaultAccountID)
+		if err != nil {
+			return err
+		}
+
+		if account.OwnerID != user.ID {
+			return errors.ErrAccessDenied
+		}
+	}
+
+	user.Preferences = prefs
+
+	return s.storage.SaveUser(ctx, user)
+}
+
+// SaveLastAccount запоминает счет, выбранный пользователем, чтобы предложить
+// его при следующем входе
+func (s *AuthServiceImpl) SaveLastAccount(ctx context.Context, userID, accountID string) error {
+	user, err := s.storage.LoadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user.LastAccountID = accountID
+
+	return s.storage.SaveUser(ctx, user)
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"bankapp/i18n"
+	"bankapp/models"
+	"bankapp/services"
+)
+
+// Управляющие последовательности ANSI для цветных заголовков
+const (
+	colorHeader = "\033[1;36m"
+	colorReset  = "\033[0m"
+)
+
+// applyPreferences применяет настройки пользователя после входа. Если задан
+// счет по умолчанию или в прошлом сеансе был выбран счет, предлагается сразу открыть его.
+func (app *BankApp) applyPreferences(ctx context.Context, user *models.User) {
+	app.prefs = user.Preferences
+	app.setLanguage(app.prefs.Language)
+
+	// Счет по умолчанию важнее счета, выбранного в прошлом сеансе
+	prompt := "Счет по умолчанию %s. Введите PIN-код (Enter - пропустить): "
+	accountID := app.prefs.DefaultAccountID
+	if accountID == "" {
+		prompt = "Продолжить работу со счетом %s? Введите PIN-код (Enter - пропустить): "
+		accountID = user.LastAccountID
+	}
+	if accountID == "" {
+		return
+	}
+
+	account, err := app.storage.LoadAccount(ctx, accountID)
+	if err != nil || account.OwnerID != user.ID || account.Archived() {
+		return
+	}
+
+	app.printf(prompt, account.ID)
+	app.scanner.Scan()
+	pin := strings.TrimSpace(app.scanner.Text())
+	if pin == "" {
+		return
+	}
+
+	err = services.Authenticate(ctx, app.storage, app.clock, account, pin)
+	app.auditAction(ctx, "select_account", account.ID, err)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.currentAccount = app.newAccountService(account)
+	app.printf("Счет %s выбран для работы\n", account.ID)
+}
+
+// formatTime форматирует время в формате дат текущего пользователя
+func (app *BankApp) formatTime(t time.Time) string {
+	return t.Format(app.prefs.DateFormat.Layout())
+}
+
+// printHeader выводит переведенный заголовок раздела, при включенном цвете - выделенным
+func (app *BankApp) printHeader(title string) {
+	if app.prefs.Color {
+		fmt.Printf("\n%s--- %s ---%s\n", colorHeader, app.tr.T(title), colorReset)
+		return
+	}
+
+	fmt.Printf("\n--- %s ---\n", app.tr.T(title))
+}
+
+// editPreferences изменяет и сохраняет настройки отображения текущего пользователя.
+// Пустой ввод оставляет значение без изменений.
+func (app *BankApp) editPreferences(ctx context.Context) {
+	prefs := app.currentUser.Preferences
+
+	app.printHeader("Настройки")
+	app.printf("Язык: %s\n", app.tr.Locale())
+	app.printf("Формат дат: %s (%s)\n", prefs.DateFormat, app.formatTime(app.clock.Now()))
+	if prefs.DefaultAccountID != "" {
+		app.printf("Счет по умолчанию: %s\n", prefs.DefaultAccountID)
+	}
+	app.printf("Цвет: %s\n", app.onOff(prefs.Color))
+	app.printf("Формат выписки: %s\n", prefs.StatementFormat)
+
+	if value := app.readLine(app.tr.Sprintf("Язык (%s, \"-\" - язык приложения): ", strings.Join(i18n.Locales(), "/"))); value != "" {
+		if value == "-" {
+			value = ""
+		}
+		prefs.Language = strings.ToLower(value)
+	}
+	if value := app.readLine("Формат дат (iso/ru/us): "); value != "" {
+		prefs.DateFormat = models.DateFormat(strings.ToLower(value))
+	}
+	if value := app.readLine("Счет по умолчанию (ID, \"-\" - сбросить): "); value != "" {
+		if value == "-" {
+			value = ""
+		}
+		prefs.DefaultAccountID = value
+	}
+	if value := app.readLine("Цвет (вкл/выкл): "); value != "" {
+		prefs.Color = strings.EqualFold(value, app.tr.T("вкл"))
+	}
+	if value := app.readLine("Формат выписки (csv/json): "); value != "" {
+		prefs.StatementFormat = models.ExportFormat(strings.ToLower(value))
+	}
+
+	if err := app.auth.SavePreferences(ctx, app.currentUser.ID, prefs); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.currentUser.Preferences = prefs
+	app.prefs = prefs
+	app.setLanguage(prefs.Language)
+	app.println("Настройки сохранены")
+}
+
+// readLine выводит приглашение и читает строку без пробелов по краям
+func (app *BankApp) readLine(prompt string) string {
+	app.print(prompt)
+	app.scanner.Scan()
+	return strings.TrimSpace(app.scanner.Text())
+}
+
+// onOff возвращает подпись для флага настройки
+func (app *BankApp) onOff(enabled bool) string {
+	if enabled {
+		return app.tr.T("вкл")
+	}
+
+	return app.tr.T("выкл")
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// receiptCodeBytes длина кода проверки в байтах HMAC: 10 байт дают
+// 16 символов base32, которые удобно продиктовать или ввести вручную
+const receiptCodeBytes = 10
+
+// WithReceiptKey задает ключ HMAC для кодов проверки квитанций.
+// Без ключа квитанции не выдаются.
+func WithReceiptKey(key []byte) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.receiptKey = key
+	}
+}
+
+// NewReceiptKey создает случайный ключ подписи квитанций на время работы процесса
+func NewReceiptKey() []byte {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
+
+// GetReceipt возвращает квитанцию по транзакции счета: баланс после
+// операции вычисляется по истории с учетом комиссии за нее
+func (s *AccountServiceImpl) GetReceipt(ctx context.Context, transactionID string) (models.Receipt, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Receipt{}, err
+	}
+
+	return s.buildReceipt(transactionID)
+}
+
+// buildReceipt собирает и подписывает квитанцию по транзакции
+func (s *AccountServiceImpl) buildReceipt(transactionID string) (models.Receipt, error) {
+	if len(s.receiptKey) == 0 {
+		return models.Receipt{}, errors.ErrNoReceiptKey
+	}
+
+	transactions := s.account.Transactions
+	index := -1
+	var balance float64
+	for i, tx := range transactions {
+		balance += tx.SignedAmount()
+		if tx.ID == transactionID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return models.Receipt{}, errors.ErrTransactionNotFound
+	}
+
+	// Комиссия за операцию проводится сразу после нее
+	var fee float64
+	for _, tx := range transactions[index+1:] {
+		if tx.Type != models.FeeTransaction || tx.RelatedID != transactionID {
+			break
+		}
+		fee += tx.Amount
+		balance += tx.SignedAmount()
+	}
+
+	tx := transactions[index]
+	receipt := models.Receipt{
+		TransactionID:  tx.ID,
+		AccountID:      s.account.ID,
+		Type:           tx.Type,
+		Direction:      tx.Direction,
+		Amount:         tx.Amount,
+		Fee:            fee,
+		CounterpartyID: tx.CounterpartyID,
+		Timestamp:      tx.Timestamp,
+		Balance:        balance,
+	}
+	receipt.Code = receiptCode(s.receiptKey, receipt)
+
+	return receipt, nil
+}
+
+// VerifyReceipt проверяет, что квитанция выдана банком с ключом key
+// и ее поля не изменялись
+func VerifyReceipt(key []byte, receipt models.Receipt) error {
+	if len(key) == 0 {
+		return errors.ErrNoReceiptKey
+	}
+
+	expected := receiptCode(key, receipt)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToUpper(strings.TrimSpace(receipt.Code)))) {
+		return errors.ErrReceiptInvalid
+	}
+
+	return nil
+}
+
+// receiptCode вычисляет код проверки по каноническому представлению
+// квитанции; суммы округляются до копеек, время приводится к UTC
+func receiptCode(key []byte, receipt models.Receipt) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s|%s|%s|%s|%.2f|%.2f|%s|%s|%.2f",
+		receipt.TransactionID,
+		receipt.AccountID,
+		receipt.Type,
+		receipt.Direction,
+		receipt.Amount,
+		receipt.Fee,
+		receipt.CounterpartyID,
+		receipt.Timestamp.UTC().Format(time.RFC3339Nano),
+		receipt.Balance)
+
+	code := base32.StdEncoding.EncodeToString(mac.Sum(nil)[:receiptCodeBytes])
+	return code[:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:]
+}
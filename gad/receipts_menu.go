@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"bankapp/models"
+	"bankapp/services"
+)
+
+// showReceipt выводит квитанцию по транзакции текущего счета текстом или в JSON
+func (app *BankApp) showReceipt(ctx context.Context) {
+	app.print("Введите ID транзакции: ")
+	app.scanner.Scan()
+	transactionID := strings.TrimSpace(app.scanner.Text())
+
+	receipt, err := app.currentAccount.GetReceipt(ctx, transactionID)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.print("Формат (1 - текст, 2 - JSON, Enter - текст): ")
+	app.scanner.Scan()
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "", "1":
+		app.printReceiptDocument(receipt)
+	case "2":
+		// Одна строка JSON, чтобы квитанцию можно было целиком вставить при проверке
+		data, err := json.Marshal(receipt)
+		if err != nil {
+			app.printf("Ошибка: %v\n", err)
+			return
+		}
+		app.println(string(data))
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+	}
+}
+
+// printReceiptDocument выводит квитанцию текстом
+func (app *BankApp) printReceiptDocument(receipt models.Receipt) {
+	app.printHeader("Квитанция")
+	app.printf("Транзакция: %s от %s\n", receipt.TransactionID, app.formatTime(receipt.Timestamp))
+	app.printf("Счет: %s\n", receipt.AccountID)
+	app.printf("Операция: %s %s\n", receipt.Type, receipt.Direction)
+	if receipt.CounterpartyID != "" {
+		app.printf("Контрагент: %s\n", receipt.CounterpartyID)
+	}
+	app.printf("Сумма: %.2f %s\n", receipt.Amount, app.currency)
+	if receipt.Fee > 0 {
+		app.printf("Комиссия: %.2f\n", receipt.Fee)
+	}
+	app.printf("Баланс после операции: %.2f %s\n", receipt.Balance, app.currency)
+	app.printf("Код проверки квитанции: %s\n", receipt.Code)
+}
+
+// verifyReceipt проверяет подлинность квитанции, вставленной в формате JSON
+func (app *BankApp) verifyReceipt() {
+	app.print("Вставьте квитанцию в формате JSON: ")
+	app.scanner.Scan()
+
+	var receipt models.Receipt
+	if err := json.Unmarshal([]byte(strings.TrimSpace(app.scanner.Text())), &receipt); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	if err := services.VerifyReceipt(app.receiptKey, receipt); err != nil {
+		app.printf("Квитанция недействительна: %v\n", err)
+		return
+	}
+
+	app.printReceiptDocument(receipt)
+	app.println("Квитанция подлинная")
+}
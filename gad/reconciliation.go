@@ -0,0 +1,100 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// transferLegs ноги одного перевода, найденные при сверке
+type transferLegs struct {
+	debits  []models.Transaction
+	credits []models.Transaction
+	owners  map[string]string
+}
+
+// Reconcile сверяет проводки всех счетов банка: у каждого перевода должны
+// быть ровно одна дебетовая и одна кредитовая нога на одинаковую сумму,
+// сумма всех внутренних проводок (переводов, их сторно и корректировок) должна быть
+// равна нулю, а баланс каждого счета - сумме его проводок.
+func Reconcile(ctx context.Context, storage interfaces.Storage) (models.ReconciliationReport, error) {
+	var report models.ReconciliationReport
+
+	accounts, _, err := storage.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return report, err
+	}
+
+	transfers := make(map[string]*transferLegs)
+	for _, account := range accounts {
+		var accountTotal float64
+		for _, tx := range account.Transactions {
+			report.Entries++
+			accountTotal += tx.SignedAmount()
+
+			switch tx.Type {
+			case models.LedgerTransaction:
+				report.InternalTotal += tx.SignedAmount()
+			case models.TransferTransaction, models.ReversalTransaction:
+				if tx.TransferID == "" {
+					// Сторно внешней операции не относится к внутренним проводкам
+					break
+				}
+
+				report.InternalTotal += tx.SignedAmount()
+
+				legs, exists := transfers[tx.TransferID]
+				if !exists {
+					legs = &transferLegs{owners: make(map[string]string)}
+					transfers[tx.TransferID] = legs
+				}
+
+				legs.owners[tx.ID] = account.ID
+				if tx.Direction == models.DebitEntry {
+					legs.debits = append(legs.debits, tx)
+				} else {
+					legs.credits = append(legs.credits, tx)
+				}
+			}
+		}
+
+		if math.Round((accountTotal-account.Balance)*100) != 0 {
+			report.BalanceMismatches = append(report.BalanceMismatches,
+				fmt.Sprintf("%s: баланс %.2f, сумма проводок %.2f", account.ID, account.Balance, accountTotal))
+		}
+	}
+
+	for transferID, legs := range transfers {
+		if problem := checkTransferLegs(legs); problem != "" {
+			report.UnbalancedTransfers = append(report.UnbalancedTransfers,
+				fmt.Sprintf("%s: %s", transferID, problem))
+		}
+	}
+
+	sort.Strings(report.UnbalancedTransfers)
+	sort.Strings(report.BalanceMismatches)
+
+	return report, nil
+}
+
+// checkTransferLegs проверяет согласованность ног перевода и возвращает
+// описание проблемы или пустую строку
+func checkTransferLegs(legs *transferLegs) string {
+	if len(legs.debits) != 1 || len(legs.credits) != 1 {
+		return fmt.Sprintf("ожидались 1 дебет и 1 кредит, найдено %d и %d", len(legs.debits), len(legs.credits))
+	}
+
+	debit, credit := legs.debits[0], legs.credits[0]
+	if math.Round((debit.Amount-credit.Amount)*100) != 0 {
+		return fmt.Sprintf("сумма дебета %.2f не равна сумме кредита %.2f", debit.Amount, credit.Amount)
+	}
+
+	if debit.CounterpartyID != legs.owners[credit.ID] || credit.CounterpartyID != legs.owners[debit.ID] {
+		return "контрагенты ног перевода не совпадают со счетами"
+	}
+
+	return ""
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"fmt"
+)
+
+// Reverse сторнирует транзакцию счета компенсирующей записью REVERSAL
+// с противоположным направлением. У перевода сторнируются обе ноги:
+// оба счета и обе ноги проверяются до изменения счетов, а сохраняются
+// счета атомарно. Исходная транзакция помечается как сторнированная,
+// повторное сторно запрещено.
+func (s *AccountServiceImpl) Reverse(ctx context.Context, transactionID string) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "reverse", 0, "транзакция "+transactionID, err)
+	}()
+
+	return s.retryOnConflict(ctx, func() error {
+		return s.reverse(ctx, transactionID)
+	})
+}
+
+// reverse проводит сторно; Reverse повторяет его при конфликте версий
+func (s *AccountServiceImpl) reverse(ctx context.Context, transactionID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := s.checkVersion(ctx); err != nil {
+		return err
+	}
+
+	original, err := s.findTransaction(transactionID)
+	if err != nil {
+		return err
+	}
+
+	others, err := s.postReversal(ctx, original)
+	if err != nil {
+		return err
+	}
+
+	return s.saveAccount(ctx, others...)
+}
+
+// postReversal проверяет транзакцию и добавляет сторно на счет сервиса,
+// а для перевода - и на счет получателя. Счета изменяются только после
+// всех проверок. Возвращает счета, которые нужно сохранить вместе со
+// счетом сервиса.
+func (s *AccountServiceImpl) postReversal(ctx context.Context, original *models.Transaction) ([]*models.Account, error) {
+	if err := checkReversible(original); err != nil {
+		return nil, err
+	}
+
+	if original.Type != models.TransferTransaction {
+		s.reverseLeg(s.account, original, "")
+		return nil, nil
+	}
+
+	counterparty, err := s.storage.LoadAccount(ctx, original.CounterpartyID)
+	if err != nil {
+		return nil, err
+	}
+
+	counterLeg, err := findTransferLeg(counterparty, original.TransferID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkReversible(counterLeg); err != nil {
+		return nil, err
+	}
+
+	if err := checkOperable(s.account); err != nil {
+		return nil, err
+	}
+
+	if err := checkOperable(counterparty); err != nil {
+		return nil, err
+	}
+
+	transferID := s.newID("TR")
+	s.reverseLeg(s.account, original, transferID)
+	s.reverseLeg(counterparty, counterLeg, transferID)
+
+	return []*models.Account{counterparty}, nil
+}
+
+// checkReversible проверяет, что транзакцию можно сторнировать
+func checkReversible(tx *models.Transaction) error {
+	if tx.ReversedBy != "" {
+		return errors.ErrAlreadyReversed
+	}
+
+	switch tx.Type {
+	case models.DepositTransaction, models.WithdrawTransaction,
+		models.TransferTransaction, models.FeeTransaction:
+		return nil
+	default:
+		return errors.ErrNotReversible
+	}
+}
+
+// findTransferLeg ищет на счете ногу перевода с указанным TransferID
+func findTransferLeg(account *models.Account, transferID string) (*models.Transaction, error) {
+	for i := range account.Transactions {
+		tx := &account.Transactions[i]
+		if tx.Type == models.TransferTransaction && tx.TransferID == transferID {
+			return tx, nil
+		}
+	}
+
+	return nil, errors.ErrTransactionNotFound
+}
+
+// reverseLeg добавляет на счет компенсирующую запись для транзакции original
+func (s *AccountServiceImpl) reverseLeg(account *models.Account, original *models.Transaction, transferID string) {
+	delta := -original.SignedAmount()
+	direction := models.CreditEntry
+	if delta < 0 {
+		direction = models.DebitEntry
+	}
+
+	reversal := models.Transaction{
+		ID:             s.newID("TX"),
+		Type:           models.ReversalTransaction,
+		Amount:         original.Amount,
+		Timestamp:      s.now(),
+		Message:        fmt.Sprintf("Сторно операции %s на %.2f", original.ID, original.Amount),
+		Direction:      direction,
+		TransferID:     transferID,
+		CounterpartyID: original.CounterpartyID,
+		ReversalOf:     original.ID,
+	}
+
+	s.queueEvent(account.ID, models.ReversalEvent, delta, reversal.ID, reversal.Timestamp)
+	original.ReversedBy = reversal.ID
+	account.Balance += delta
+	account.Transactions = append(account.Transactions, reversal)
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"bankapp/errors"
+)
+
+// reviewFlaggedTransactions показывает операции, помеченные оценкой риска,
+// и принимает решение по выбранной: одобрить или отклонить со сторно
+func (app *BankApp) reviewFlaggedTransactions(ctx context.Context) {
+	queue, err := app.admin.ListFlaggedTransactions(ctx)
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	app.printHeader("Операции на проверке")
+	if len(queue) == 0 {
+		app.println("Нет операций, ожидающих проверки")
+		return
+	}
+
+	for _, item := range queue {
+		tx := item.Transaction
+		app.printf("%s  счет %s (%s)  %s  %.2f  риск %.2f  от %s\n",
+			tx.ID, item.AccountID, item.OwnerName, tx.Type, tx.Amount, tx.RiskScore, app.formatTime(tx.Timestamp))
+	}
+
+	app.print("ID транзакции (Enter - назад): ")
+	app.scanner.Scan()
+	transactionID := strings.TrimSpace(app.scanner.Text())
+	if transactionID == "" {
+		return
+	}
+
+	var accountID string
+	for _, item := range queue {
+		if item.Transaction.ID == transactionID {
+			accountID = item.AccountID
+		}
+	}
+	if accountID == "" {
+		app.printf("Ошибка: %v\n", errors.ErrNotUnderReview)
+		return
+	}
+
+	app.print("Одобрить операцию? (y - одобрить, n - отклонить и сторнировать): ")
+	app.scanner.Scan()
+	approve := strings.ToLower(strings.TrimSpace(app.scanner.Text())) == "y"
+
+	if err := app.admin.ResolveFlaggedTransaction(ctx, accountID, transactionID, approve); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	if approve {
+		app.printf("Операция %s одобрена\n", transactionID)
+	} else {
+		app.printf("Операция %s отклонена и сторнирована\n", transactionID)
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"sort"
+)
+
+// ListFlaggedTransactions возвращает очередь операций, помеченных оценкой
+// риска для проверки, от самых старых к новым
+func (s *AdminServiceImpl) ListFlaggedTransactions(ctx context.Context) ([]models.TransactionSearchResult, error) {
+	accounts, err := s.storage.GetAllAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var queue []models.TransactionSearchResult
+	for _, account := range accounts {
+		for _, tx := range account.Transactions {
+			if tx.UnderReview {
+				queue = append(queue, models.TransactionSearchResult{
+					AccountID:   account.ID,
+					OwnerName:   account.OwnerName,
+					Transaction: tx,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].Transaction.Timestamp.Before(queue[j].Transaction.Timestamp)
+	})
+
+	return queue, nil
+}
+
+// ResolveFlaggedTransaction снимает операцию с проверки. Одобренная
+// операция остается в силе, отклоненная сторнируется.
+func (s *AdminServiceImpl) ResolveFlaggedTransaction(ctx context.Context, accountID, transactionID string, approve bool) (err error) {
+	decision := "отклонена"
+	if approve {
+		decision = "одобрена"
+	}
+	defer func() {
+		s.auditAdmin(ctx, "resolve_review", accountID, 0, transactionID+" "+decision, err)
+	}()
+
+	account, err := s.storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	service := &AccountServiceImpl{
+		account: account,
+		storage: s.storage,
+		ledger:  s.ledger,
+		ids:     s.ids,
+		clock:   s.clock,
+	}
+
+	tx, err := service.findTransaction(transactionID)
+	if err != nil {
+		return err
+	}
+
+	if !tx.UnderReview {
+		return errors.ErrNotUnderReview
+	}
+
+	if !approve {
+		if err := service.Reverse(ctx, transactionID); err != nil {
+			return err
+		}
+
+		// Сторно могло добавить транзакции и сдвинуть историю
+		if tx, err = service.findTransaction(transactionID); err != nil {
+			return err
+		}
+	}
+
+	tx.UnderReview = false
+	return s.storage.SaveAccount(ctx, service.account)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"time"
+)
+
+// RiskPolicy пороги, по которым оценка риска превращается в решение
+type RiskPolicy struct {
+	// ReviewThreshold оценка, начиная с которой операция помечается для проверки
+	ReviewThreshold float64
+	// BlockThreshold оценка, начиная с которой операция отклоняется
+	BlockThreshold float64
+	// Timeout время ожидания ответа сервиса оценки
+	Timeout time.Duration
+}
+
+// DefaultRiskPolicy пороги оценки риска по умолчанию
+var DefaultRiskPolicy = RiskPolicy{
+	ReviewThreshold: 0.7,
+	BlockThreshold:  0.9,
+	Timeout:         2 * time.Second,
+}
+
+// WithRiskScorer подключает внешний сервис оценки риска операций
+func WithRiskScorer(scorer interfaces.RiskScorer, policy RiskPolicy) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.riskScorer = scorer
+		s.riskPolicy = policy
+	}
+}
+
+// riskResult ответ сервиса оценки риска
+type riskResult struct {
+	score float64
+	err   error
+}
+
+// assessRisk запрашивает оценку риска операции. Сервис вызывается асинхронно
+// и ожидается не дольше Timeout; при ошибке или таймауте операция
+// выполняется без оценки. Возвращает оценку и признак необходимости проверки.
+func (s *AccountServiceImpl) assessRisk(ctx context.Context, txType models.TransactionType, amount float64, counterparty string) (float64, bool, error) {
+	if s.riskScorer == nil {
+		return 0, false, nil
+	}
+
+	scoreCtx := ctx
+	if s.riskPolicy.Timeout > 0 {
+		var cancel context.CancelFunc
+		scoreCtx, cancel = context.WithTimeout(ctx, s.riskPolicy.Timeout)
+		defer cancel()
+	}
+
+	request := models.RiskRequest{
+		AccountID:    s.account.ID,
+		Type:         txType,
+		Amount:       amount,
+		Counterparty: counterparty,
+		At:           s.now(),
+	}
+
+	results := make(chan riskResult, 1)
+	go func() {
+		score, err := s.riskScorer.Score(scoreCtx, request)
+		results <- riskResult{score: score, err: err}
+	}()
+
+	var result riskResult
+	select {
+	case result = <-results:
+	case <-scoreCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return 0, false, err
+		}
+		return 0, false, nil
+	}
+
+	if result.err != nil {
+		return 0, false, nil
+	}
+
+	if s.riskPolicy.BlockThreshold > 0 && result.score >= s.riskPolicy.BlockThreshold {
+		return result.score, false, errors.ErrTransactionBlocked
+	}
+
+	review := s.riskPolicy.ReviewThreshold > 0 && result.score >= s.riskPolicy.ReviewThreshold
+	return result.score, review, nil
+}
+
+// velocityWindow окно, за которое считается частота исходящих операций
+const velocityWindow = time.Hour
+
+// velocity частота исходящих операций счета за последний час
+type velocity struct {
+	// count снятия и исходящие переводы за окно
+	count int
+	// newRecipients получатели переводов за окно, которым раньше счет не платил
+	newRecipients int
+	// newCounterparty текущему получателю счет еще никогда не переводил
+	newCounterparty bool
+}
+
+// outgoingLastHour считает исходящие операции за velocityWindow и новых
+// получателей среди них; для правил вида "не больше N переводов новым
+// получателям за час", на момент now
+func outgoingLastHour(account *models.Account, counterparty string, now time.Time) velocity {
+	windowStart := now.Add(-velocityWindow)
+
+	known := make(map[string]bool)
+	recent := make(map[string]bool)
+	var v velocity
+	for _, tx := range account.Transactions {
+		outgoing := tx.Type == models.WithdrawTransaction ||
+			(tx.Type == models.TransferTransaction && tx.Direction == models.DebitEntry)
+		if !outgoing {
+			continue
+		}
+
+		if tx.Timestamp.Before(windowStart) {
+			known[tx.CounterpartyID] = true
+			continue
+		}
+
+		v.count++
+		if tx.CounterpartyID != "" {
+			recent[tx.CounterpartyID] = true
+		}
+	}
+
+	for recipient := range recent {
+		if !known[recipient] {
+			v.newRecipients++
+		}
+	}
+
+	v.newCounterparty = counterparty != "" && !known[counterparty] && !recent[counterparty]
+	return v
+}
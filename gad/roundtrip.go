@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"bankapp/sim"
+)
+
+// runRoundTrip выполняет команду roundtrip: после случайного прогона
+// выгружает счета в резервную копию, CSV и JSON, загружает обратно и
+// печатает, какие поля каждый формат не переносит. Код выхода 1 - формат
+// потерял поле, которое должен сохранять.
+func runRoundTrip(args []string) int {
+	defaults := sim.DefaultOptions(0)
+	flags := flag.NewFlagSet("roundtrip", flag.ContinueOnError)
+	seed := flags.Int64("seed", 0, "зерно прогона (0 - от текущего времени)")
+	accounts := flags.Int("accounts", defaults.Accounts, "количество счетов")
+	steps := flags.Int("steps", defaults.Steps, "количество операций в прогоне")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	opts := sim.DefaultOptions(*seed)
+	opts.Accounts = *accounts
+	opts.Steps = *steps
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := sim.RunRoundTrips(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка проверки (seed %d): %v\n", opts.Seed, err)
+		return 2
+	}
+
+	for _, format := range report.Formats {
+		fmt.Printf("%s: счетов %d\n", format.Format, format.Accounts)
+		if len(format.Skipped) > 0 {
+			fmt.Printf("  не загружаются счета: %s\n", strings.Join(format.Skipped, ", "))
+		}
+		if len(format.Dropped) > 0 {
+			fmt.Printf("  не переносятся поля: %s\n", strings.Join(format.Dropped, ", "))
+		}
+	}
+
+	if err := report.Err(); err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
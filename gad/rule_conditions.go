@@ -0,0 +1,204 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/expr"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// conditionVariables переменные, доступные в условиях правил комиссий,
+// лимитов и оценки риска
+var conditionVariables = map[string]bool{
+	"amount":                  true,
+	"type":                    true,
+	"counterparty":            true,
+	"account.id":              true,
+	"account.owner":           true,
+	"account.status":          true,
+	"account.balance":         true,
+	"account.overdraft_limit": true,
+	"account.age_days":        true,
+	"account.manager":         true,
+	"today.amount":            true,
+	"today.count":             true,
+	"hour.count":              true,
+	"hour.new_recipients":     true,
+	"counterparty.new":        true,
+}
+
+// CompileCondition компилирует условие правила и проверяет, что в нем
+// используются только известные переменные
+func CompileCondition(source string) (*expr.Expr, error) {
+	condition, err := expr.Compile(source)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range condition.Variables() {
+		if !conditionVariables[name] {
+			return nil, fmt.Errorf("%w: неизвестная переменная %s", errors.ErrInvalidExpression, name)
+		}
+	}
+
+	return condition, nil
+}
+
+// conditionEnv значения переменных условия для операции над счетом на момент now
+func conditionEnv(now time.Time, account *models.Account, txType models.TransactionType, amount float64, counterparty string) expr.Env {
+	used, count := outgoingToday(account, now)
+	velocity := outgoingLastHour(account, counterparty, now)
+
+	return expr.Env{
+		"amount":                  amount,
+		"type":                    string(txType),
+		"counterparty":            counterparty,
+		"account.id":              account.ID,
+		"account.owner":           account.OwnerName,
+		"account.status":          string(account.Status),
+		"account.balance":         account.Balance,
+		"account.overdraft_limit": account.OverdraftLimit,
+		"account.age_days":        now.Sub(account.CreatedAt).Hours() / 24,
+		"account.manager":         account.RelationshipManager,
+		"today.amount":            used,
+		"today.count":             count,
+		"hour.count":              float64(velocity.count),
+		"hour.new_recipients":     float64(velocity.newRecipients),
+		"counterparty.new":        velocity.newCounterparty,
+	}
+}
+
+// conditionRule скомпилированное условие правила. Ошибка компиляции
+// сохраняется и возвращается при каждой проверке.
+type conditionRule struct {
+	name      string
+	condition *expr.Expr
+	err       error
+}
+
+// compileConditionRule компилирует условие; пустое условие выполняется всегда
+func compileConditionRule(name, source string) conditionRule {
+	if source == "" {
+		return conditionRule{name: name}
+	}
+
+	condition, err := CompileCondition(source)
+	return conditionRule{name: name, condition: condition, err: err}
+}
+
+// matches проверяет условие правила для операции на момент now
+func (r conditionRule) matches(now time.Time, account *models.Account, txType models.TransactionType, amount float64, counterparty string) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+
+	if r.condition == nil {
+		return true, nil
+	}
+
+	return r.condition.Bool(conditionEnv(now, account, txType, amount, counterparty))
+}
+
+// WithLimitRules подключает ограничения операций, заданные выражениями.
+// Операция отклоняется, если выполняется условие хотя бы одного правила.
+func WithLimitRules(rules []models.LimitRule) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.limitRules = make([]conditionRule, 0, len(rules))
+		for _, rule := range rules {
+			s.limitRules = append(s.limitRules, compileConditionRule(rule.Name, rule.When))
+		}
+	}
+}
+
+// checkLimitRules проверяет операцию по ограничениям-выражениям
+func (s *AccountServiceImpl) checkLimitRules(txType models.TransactionType, amount float64, counterparty string) error {
+	for _, rule := range s.limitRules {
+		restricted, err := rule.matches(s.now(), s.account, txType, amount, counterparty)
+		if err != nil {
+			return err
+		}
+
+		if restricted {
+			return fmt.Errorf("%w: %s", errors.ErrOperationRestricted, rule.name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateLimitRules проверяет условия ограничений
+func ValidateLimitRules(rules []models.LimitRule) error {
+	for _, rule := range rules {
+		if _, err := CompileCondition(rule.When); err != nil {
+			return fmt.Errorf("правило %s: %w", rule.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// RuleRiskScorer оценка риска по правилам-выражениям: оценка операции -
+// наибольшая Score среди правил, условие которых выполняется
+type RuleRiskScorer struct {
+	storage interfaces.Storage
+	clock   models.Clock
+	rules   []conditionRule
+	scores  []float64
+}
+
+// NewRuleRiskScorer создает оценку риска по правилам. Операция без
+// времени оценивается на текущее время clock.
+func NewRuleRiskScorer(storage interfaces.Storage, rules []models.RiskRule, clock models.Clock) interfaces.RiskScorer {
+	scorer := &RuleRiskScorer{storage: storage, clock: clock}
+	for _, rule := range rules {
+		scorer.rules = append(scorer.rules, compileConditionRule(rule.Name, rule.When))
+		scorer.scores = append(scorer.scores, rule.Score)
+	}
+
+	return scorer
+}
+
+// Score оценивает операцию по правилам
+func (r *RuleRiskScorer) Score(ctx context.Context, request models.RiskRequest) (float64, error) {
+	account, err := r.storage.LoadAccount(ctx, request.AccountID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := request.At
+	if now.IsZero() {
+		now = r.clock.Now()
+	}
+
+	var score float64
+	for i, rule := range r.rules {
+		matched, err := rule.matches(now, account, request.Type, request.Amount, request.Counterparty)
+		if err != nil {
+			return 0, err
+		}
+
+		if matched {
+			score = max(score, r.scores[i])
+		}
+	}
+
+	return score, nil
+}
+
+// ValidateRiskRules проверяет условия и оценки правил риска
+func ValidateRiskRules(rules []models.RiskRule) error {
+	for _, rule := range rules {
+		if rule.Score < 0 || rule.Score > 1 {
+			return fmt.Errorf("правило %s: %w: оценка вне диапазона 0..1", rule.Name, errors.ErrInvalidExpression)
+		}
+
+		if _, err := CompileCondition(rule.When); err != nil {
+			return fmt.Errorf("правило %s: %w", rule.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	stderrors "errors"
+)
+
+// CloneAccount копирует счет с историей транзакций и журналом событий
+// в отдельное хранилище-песочницу, где можно отрабатывать разрушающие
+// операции (оспаривания, корректировки), не затрагивая реальные данные.
+// Содержимое вложений не копируется: ключи указывают на исходное хранилище.
+func CloneAccount(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage,
+	accountID string, into interfaces.Storage, intoLedger interfaces.LedgerStorage) (*models.Account, error) {
+	account, err := storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := into.LoadAccount(ctx, accountID); err == nil {
+		return nil, errors.ErrAccountExists
+	} else if !stderrors.Is(err, errors.ErrAccountNotFound) {
+		return nil, err
+	}
+
+	events, err := ledger.LoadEvents(ctx, accountID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if err := intoLedger.AppendEvent(ctx, &event); err != nil {
+			return nil, err
+		}
+	}
+
+	clone := account.Clone()
+	if err := into.SaveAccount(ctx, clone); err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SearchServiceImpl реализация SearchService
+type SearchServiceImpl struct {
+	storage interfaces.Storage
+}
+
+// NewSearchService создает сервис поиска транзакций
+func NewSearchService(storage interfaces.Storage) interfaces.SearchService {
+	return &SearchServiceImpl{
+		storage: storage,
+	}
+}
+
+// SearchTransactions ищет транзакции по всем счетам и возвращает страницу
+// результатов вместе с общим количеством совпадений
+func (s *SearchServiceImpl) SearchTransactions(ctx context.Context, filter models.TransactionFilter) ([]models.TransactionSearchResult, int, error) {
+	accounts, err := s.storage.GetAllAccounts(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matches []models.TransactionSearchResult
+	for _, account := range accounts {
+		for _, tx := range account.Transactions {
+			if matchesFilter(tx, filter) {
+				matches = append(matches, models.TransactionSearchResult{
+					AccountID:   account.ID,
+					OwnerName:   account.OwnerName,
+					Transaction: tx,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Transaction.Timestamp.Before(matches[j].Transaction.Timestamp)
+	})
+
+	start, end := models.PageBounds(len(matches), filter.Offset, filter.Limit)
+	return matches[start:end], len(matches), nil
+}
+
+// matchesFilter проверяет транзакцию на соответствие критериям.
+// Контрагент сравнивается с CounterpartyID либо ищется в описании транзакции,
+// текст ищется в описании и заметке.
+func matchesFilter(tx models.Transaction, filter models.TransactionFilter) bool {
+	if filter.MinAmount > 0 && tx.Amount < filter.MinAmount {
+		return false
+	}
+
+	if filter.MaxAmount > 0 && tx.Amount > filter.MaxAmount {
+		return false
+	}
+
+	if !filter.From.IsZero() && tx.Timestamp.Before(filter.From) {
+		return false
+	}
+
+	if !filter.To.IsZero() && !tx.Timestamp.Before(filter.To) {
+		return false
+	}
+
+	if filter.Counterparty != "" && tx.CounterpartyID != filter.Counterparty &&
+		!strings.Contains(tx.Message, filter.Counterparty) {
+		return false
+	}
+
+	if filter.Text != "" && !strings.Contains(strings.ToLower(tx.Message), strings.ToLower(filter.Text)) &&
+		!strings.Contains(strings.ToLower(tx.Note), strings.ToLower(filter.Text)) {
+		return false
+	}
+
+	if filter.Category != "" && tx.Category != strings.ToLower(filter.Category) {
+		return false
+	}
+
+	if filter.Tag != "" && !slices.Contains(tx.Tags, strings.ToLower(strings.TrimPrefix(filter.Tag, "#"))) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,501 @@
+package sim
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"bankapp/services"
+	"bankapp/storage"
+	"bankapp/testkit"
+)
+
+// traceLength количество последних операций, сохраняемых в отчете
+const traceLength = 10
+
+// stepInterval время между операциями прогона по часам прогона
+const stepInterval = time.Minute
+
+// Инварианты, проверяемые после каждой операции
+const (
+	// ConservationInvariant сумма балансов меняется только на пополнения,
+	// снятия и комиссии за вычетом их сторно
+	ConservationInvariant = "conservation"
+	// OverdraftInvariant баланс счета не ниже лимита овердрафта
+	OverdraftInvariant = "overdraft"
+	// ConsistencyInvariant баланс совпадает с историей и журналом событий
+	ConsistencyInvariant = "consistency"
+)
+
+// OpKind вид случайной операции прогона
+type OpKind string
+
+// Виды операций прогона
+const (
+	DepositOp     OpKind = "deposit"
+	WithdrawOp    OpKind = "withdraw"
+	TransferOp    OpKind = "transfer"
+	InitiateOp    OpKind = "initiate"
+	ConfirmOp     OpKind = "confirm"
+	CancelOp      OpKind = "cancel"
+	ReverseOp     OpKind = "reverse"
+	MaintenanceOp OpKind = "maintenance"
+)
+
+// Options параметры прогона: зерно генератора, число счетов и операций,
+// предельная сумма операции и лимит овердрафта, правила комиссий
+type Options struct {
+	Seed      int64
+	Accounts  int
+	Steps     int
+	MaxAmount float64
+	Overdraft float64
+	FeeRules  []models.FeeRule
+}
+
+// DefaultOptions параметры прогона по умолчанию
+func DefaultOptions(seed int64) Options {
+	return Options{
+		Seed:      seed,
+		Accounts:  10,
+		Steps:     1000,
+		MaxAmount: 500,
+		Overdraft: 200,
+		FeeRules:  DefaultFeeRules(),
+	}
+}
+
+// DefaultFeeRules комиссии прогона: фиксированная за снятие, процент
+// с крупных переводов и плата за обслуживание, чтобы инвариант сохранения
+// учитывал комиссии
+func DefaultFeeRules() []models.FeeRule {
+	return []models.FeeRule{
+		{Type: models.WithdrawTransaction, Fixed: 1},
+		{Type: models.TransferTransaction, Percent: 0.5, Threshold: 100},
+		{Type: models.MaintenanceFee, Fixed: 2},
+	}
+}
+
+// Step операция прогона и ее результат; Ref - ID перевода с подтверждением
+// или сторнируемой транзакции. Error пустая для проведенной операции.
+type Step struct {
+	Number int
+	Kind   OpKind
+	From   string
+	To     string
+	Ref    string
+	Amount float64
+	Fee    float64
+	Error  string
+}
+
+// Violation нарушение инварианта на шаге Step
+type Violation struct {
+	Step      int
+	Invariant string
+	Detail    string
+}
+
+// Report результат прогона. Deposited, Withdrawn и Fees учитываются за
+// вычетом сторно. Trace - последние операции до нарушения или до конца
+// прогона.
+type Report struct {
+	Seed      int64
+	Steps     int
+	Applied   int
+	Rejected  int
+	Deposited float64
+	Withdrawn float64
+	Fees      float64
+	Total     float64
+	Violation *Violation
+	Trace     []Step
+}
+
+// Err возвращает нарушение инварианта как ошибку или nil
+func (r Report) Err() error {
+	if r.Violation == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s на шаге %d: %s (seed %d)",
+		errors.ErrInvariantViolated, r.Violation.Invariant, r.Violation.Step, r.Violation.Detail, r.Seed)
+}
+
+// simulation состояние прогона
+type simulation struct {
+	opts     Options
+	rng      *rand.Rand
+	storage  interfaces.Storage
+	ledger   interfaces.LedgerStorage
+	services []interfaces.AccountService
+	ids      []string
+	fees     interfaces.FeePolicy
+	kinds    []OpKind
+	clock    *testkit.FakeClock
+	report   Report
+}
+
+// Run выполняет случайную последовательность пополнений, снятий, переводов,
+// переводов с подтверждением, сторно и начислений платы за обслуживание
+// в хранилище в памяти и после каждой операции проверяет инварианты. Отклоненные операции допустимы, прогон останавливается на
+// первом нарушении. Ошибка возвращается только при сбое самого прогона.
+func Run(ctx context.Context, opts Options) (Report, error) {
+	s, err := run(ctx, opts)
+	return s.report, err
+}
+
+// run выполняет прогон и возвращает его состояние вместе с хранилищами
+func run(ctx context.Context, opts Options) (*simulation, error) {
+	if opts.Accounts < 2 || opts.Steps <= 0 || opts.MaxAmount <= 0 || opts.Overdraft < 0 {
+		return &simulation{report: Report{Seed: opts.Seed}}, errors.ErrInvalidSimulation
+	}
+	if err := services.ValidateFeeRules(opts.FeeRules); err != nil {
+		return &simulation{report: Report{Seed: opts.Seed}}, err
+	}
+
+	s := &simulation{
+		opts:    opts,
+		rng:     rand.New(rand.NewSource(opts.Seed)),
+		storage: storage.NewMemoryStorage(),
+		ledger:  storage.NewMemoryLedgerStorage(),
+		clock:   testkit.NewFakeClock(time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)),
+		report:  Report{Seed: opts.Seed},
+	}
+
+	if err := s.openAccounts(ctx); err != nil {
+		return s, err
+	}
+
+	s.kinds = []OpKind{DepositOp, WithdrawOp, TransferOp, InitiateOp, ConfirmOp, CancelOp, ReverseOp}
+	if s.fees != nil {
+		s.kinds = append(s.kinds, MaintenanceOp)
+	}
+
+	for step := 1; step <= opts.Steps; step++ {
+		if err := ctx.Err(); err != nil {
+			return s, err
+		}
+
+		s.clock.Advance(stepInterval)
+		s.apply(ctx, step)
+		s.report.Steps = step
+
+		violation, err := s.checkBalances(ctx, step)
+		if err != nil {
+			return s, err
+		}
+		if violation != nil {
+			s.report.Violation = violation
+			return s, nil
+		}
+	}
+
+	violation, err := s.checkConsistency(ctx, opts.Steps)
+	if err != nil {
+		return s, err
+	}
+	s.report.Violation = violation
+
+	return s, nil
+}
+
+// String описывает операцию для журнала прогона
+func (s Step) String() string {
+	line := fmt.Sprintf("#%d %s", s.Number, s.Kind)
+	if s.From != "" {
+		line += " " + s.From
+	}
+	if s.To != "" {
+		line += " -> " + s.To
+	}
+	if s.Ref != "" {
+		line += " [" + s.Ref + "]"
+	}
+	line += fmt.Sprintf(" %.2f", s.Amount)
+	if s.Fee > 0 {
+		line += fmt.Sprintf(" (комиссия %.2f)", s.Fee)
+	}
+	if s.Error != "" {
+		line += ": " + s.Error
+	}
+
+	return line
+}
+
+// openAccounts создает счета прогона со случайным лимитом овердрафта.
+// Дневные лимиты сняты, чтобы длинный прогон не упирался в них. ID
+// последовательные, а время задают часы прогона, чтобы история
+// повторялась для того же зерна.
+func (s *simulation) openAccounts(ctx context.Context) error {
+	ids := testkit.NewSequentialIDs()
+	if len(s.opts.FeeRules) > 0 {
+		s.fees = services.NewRuleFeePolicy(s.opts.FeeRules, s.clock)
+	}
+
+	for i := 0; i < s.opts.Accounts; i++ {
+		account := models.NewAccount(fmt.Sprintf("sim-%d", i+1), ids, s.clock)
+		account.DailyAmountLimit = 0
+		account.DailyCountLimit = 0
+		if s.opts.Overdraft > 0 && s.rng.Intn(2) == 0 {
+			account.OverdraftLimit = s.amount(s.opts.Overdraft)
+		}
+
+		if err := s.storage.SaveAccount(ctx, account); err != nil {
+			return err
+		}
+
+		opts := []services.AccountOption{services.WithIDGenerator(ids), services.WithClock(s.clock)}
+		if s.fees != nil {
+			opts = append(opts, services.WithFeePolicy(s.fees))
+		}
+		s.services = append(s.services, services.NewAccountService(account, s.storage, s.ledger, opts...))
+		s.ids = append(s.ids, account.ID)
+	}
+
+	return nil
+}
+
+// apply выполняет случайную операцию и учитывает ее в отчете
+func (s *simulation) apply(ctx context.Context, number int) {
+	from := s.rng.Intn(len(s.services))
+	step := Step{
+		Number: number,
+		Kind:   s.kinds[s.rng.Intn(len(s.kinds))],
+		From:   s.ids[from],
+		Amount: s.amount(s.opts.MaxAmount),
+	}
+
+	var result models.OperationResult
+	var err error
+	switch step.Kind {
+	case DepositOp:
+		result, err = s.services[from].Deposit(ctx, step.Amount, models.CashSource)
+	case WithdrawOp:
+		result, err = s.services[from].Withdraw(ctx, step.Amount)
+	case TransferOp:
+		step.To = s.recipient(from)
+		result, err = s.services[from].Transfer(ctx, &models.Account{ID: step.To}, step.Amount)
+	case InitiateOp:
+		step.To = s.recipient(from)
+		var transfer models.PendingTransfer
+		transfer, err = s.services[from].InitiateTransfer(ctx, &models.Account{ID: step.To}, step.Amount)
+		step.Ref = transfer.ID
+	case ConfirmOp, CancelOp:
+		result, err = s.resolvePending(ctx, &step)
+	case ReverseOp:
+		err = s.reverse(ctx, from, &step)
+	case MaintenanceOp:
+		err = s.assessMaintenance(ctx, &step)
+	}
+
+	if err != nil {
+		step.Error = err.Error()
+		s.report.Rejected++
+	} else {
+		step.Fee = result.Fee
+		s.report.Applied++
+		s.report.Fees += result.Fee
+		switch step.Kind {
+		case DepositOp:
+			s.report.Deposited += step.Amount
+		case WithdrawOp:
+			s.report.Withdrawn += step.Amount
+		}
+	}
+
+	s.report.Trace = append(s.report.Trace, step)
+	if len(s.report.Trace) > traceLength {
+		s.report.Trace = s.report.Trace[1:]
+	}
+}
+
+// recipient выбирает случайный счет получателя, отличный от счета from
+func (s *simulation) recipient(from int) string {
+	return s.ids[(from+1+s.rng.Intn(len(s.services)-1))%len(s.services)]
+}
+
+// resolvePending подтверждает или отменяет случайный перевод, ожидающий
+// подтверждения, на любом счете прогона
+func (s *simulation) resolvePending(ctx context.Context, step *Step) (models.OperationResult, error) {
+	type pending struct {
+		index    int
+		transfer models.PendingTransfer
+	}
+
+	var candidates []pending
+	for i, service := range s.services {
+		for _, transfer := range service.ListPendingTransfers(ctx) {
+			candidates = append(candidates, pending{index: i, transfer: transfer})
+		}
+	}
+	if len(candidates) == 0 {
+		step.From = ""
+		return models.OperationResult{}, errors.ErrTransferNotFound
+	}
+
+	picked := candidates[s.rng.Intn(len(candidates))]
+	step.From = s.ids[picked.index]
+	step.To = picked.transfer.ToAccountID
+	step.Ref = picked.transfer.ID
+	step.Amount = picked.transfer.Amount
+
+	if step.Kind == CancelOp {
+		return models.OperationResult{}, s.services[picked.index].CancelTransfer(ctx, step.Ref)
+	}
+
+	return s.services[picked.index].ConfirmTransfer(ctx, step.Ref)
+}
+
+// reverse сторнирует случайную транзакцию счета from. Сторно не проверяет
+// средства списываемого счета, поэтому выбираются только транзакции, сторно
+// которых покрыто доступными средствами: иначе выход за овердрафт был бы
+// ожидаемым, а не нарушением.
+func (s *simulation) reverse(ctx context.Context, from int, step *Step) error {
+	account, err := s.storage.LoadAccount(ctx, s.ids[from])
+	if err != nil {
+		return err
+	}
+
+	now := s.clock.Now()
+	var candidates []models.Transaction
+	for _, tx := range account.Transactions {
+		if tx.ReversedBy != "" {
+			continue
+		}
+
+		switch tx.Type {
+		case models.DepositTransaction, models.WithdrawTransaction,
+			models.TransferTransaction, models.FeeTransaction:
+		default:
+			continue
+		}
+
+		// Сторно списывает счет, на который транзакция зачисляла, а у
+		// исходящего перевода - счет получателя
+		var debited *models.Account
+		switch {
+		case tx.SignedAmount() > 0:
+			debited = account
+		case tx.Type == models.TransferTransaction:
+			if debited, err = s.storage.LoadAccount(ctx, tx.CounterpartyID); err != nil {
+				return err
+			}
+		}
+		if debited != nil && cents(debited.AvailableFundsAt(now)) < cents(tx.Amount) {
+			continue
+		}
+
+		candidates = append(candidates, tx)
+	}
+	if len(candidates) == 0 {
+		return errors.ErrTransactionNotFound
+	}
+
+	original := candidates[s.rng.Intn(len(candidates))]
+	step.Ref = original.ID
+	step.Amount = original.Amount
+	if original.Type == models.TransferTransaction {
+		step.To = original.CounterpartyID
+	}
+
+	if err := s.services[from].Reverse(ctx, original.ID); err != nil {
+		return err
+	}
+
+	switch original.Type {
+	case models.DepositTransaction:
+		s.report.Deposited -= original.Amount
+	case models.WithdrawTransaction:
+		s.report.Withdrawn -= original.Amount
+	case models.FeeTransaction:
+		s.report.Fees -= original.Amount
+	}
+
+	return nil
+}
+
+// assessMaintenance начисляет плату за обслуживание по всем счетам за
+// текущий месяц часов прогона. Счета изменяются в обход сервисов, и
+// следующая операция сервиса должна перечитать счет.
+func (s *simulation) assessMaintenance(ctx context.Context, step *Step) error {
+	report, err := services.AssessMaintenanceFees(ctx, s.storage, s.ledger, s.fees, s.clock.Now(), false)
+	// Уже списанная плата учитывается и при ошибке на одном из счетов
+	s.report.Fees += report.Total
+	step.From = ""
+	step.Amount = report.Total
+
+	return err
+}
+
+// amount возвращает случайную сумму от 0.01 до max с точностью до копейки
+func (s *simulation) amount(max float64) float64 {
+	cents := int64(math.Round(max * 100))
+	if cents < 1 {
+		cents = 1
+	}
+
+	return float64(1+s.rng.Int63n(cents)) / 100
+}
+
+// checkBalances проверяет сохранение суммы балансов и лимиты овердрафта
+// по сохраненным счетам
+func (s *simulation) checkBalances(ctx context.Context, step int) (*Violation, error) {
+	var total float64
+	for _, id := range s.ids {
+		account, err := s.storage.LoadAccount(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if cents(account.Balance) < -cents(account.OverdraftLimit) {
+			return &Violation{
+				Step:      step,
+				Invariant: OverdraftInvariant,
+				Detail: fmt.Sprintf("баланс счета %s %.2f ниже лимита овердрафта %.2f",
+					id, account.Balance, account.OverdraftLimit),
+			}, nil
+		}
+		total += account.Balance
+	}
+
+	s.report.Total = total
+	expected := s.report.Deposited - s.report.Withdrawn - s.report.Fees
+	if cents(total) != cents(expected) {
+		return &Violation{
+			Step:      step,
+			Invariant: ConservationInvariant,
+			Detail: fmt.Sprintf("сумма балансов %.2f, ожидалось %.2f (пополнено %.2f, снято %.2f, комиссии %.2f)",
+				total, expected, s.report.Deposited, s.report.Withdrawn, s.report.Fees),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// checkConsistency сверяет балансы с историей транзакций и журналом событий
+func (s *simulation) checkConsistency(ctx context.Context, step int) (*Violation, error) {
+	issues, err := services.CheckConsistency(ctx, s.storage, s.ledger, s.clock.Now(), false)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return &Violation{
+		Step:      step,
+		Invariant: ConsistencyInvariant,
+		Detail:    fmt.Sprintf("счет %s: %s", issues[0].AccountID, issues[0].Problem),
+	}, nil
+}
+
+// cents переводит сумму в копейки для точного сравнения
+func cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
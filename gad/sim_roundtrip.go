@@ -0,0 +1,393 @@
+package sim
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"bankapp/services"
+	"bankapp/storage"
+	"bankapp/testkit"
+)
+
+// Проверяемые выгрузки
+const (
+	// BackupRoundTrip резервная копия: ExportBackup и ImportBackup
+	BackupRoundTrip = "backup"
+	// CSVRoundTrip выписка в CSV, загруженная обратно через ImportCSV
+	CSVRoundTrip = "csv"
+	// JSONRoundTrip выписка в JSON; загрузчика нет, документ читается обратно
+	JSONRoundTrip = "json"
+)
+
+// keptFields поля, которые выгрузка обещает сохранить. Резервная копия
+// сохраняет все поля, у остальных выгрузок различие в других полях
+// считается документированной потерей, а не ошибкой.
+var keptFields = map[string][]string{
+	CSVRoundTrip: {
+		"Account.ID", "Account.OwnerName", "Account.Balance", "Account.CreatedAt",
+		"Transaction.ID", "Transaction.Type", "Transaction.Amount", "Transaction.Timestamp",
+		"Transaction.Message", "Transaction.Direction", "Transaction.CounterpartyID",
+	},
+	JSONRoundTrip: {
+		"Account.ID", "Account.OwnerName", "Account.Balance",
+		"Transaction.ID", "Transaction.Type", "Transaction.Amount", "Transaction.Timestamp",
+		"Transaction.Message", "Transaction.Direction", "Transaction.TransferID",
+		"Transaction.CounterpartyID", "Transaction.Source", "Transaction.ReversedBy",
+		"Transaction.ReversalOf", "Transaction.CorrectedBy", "Transaction.UnderReview",
+	},
+}
+
+// RoundTrip результат выгрузки и обратной загрузки в одном формате.
+// Dropped - поля, которые формат не переносит, Lost - поля, которые формат
+// должен сохранять, но загруженные значения отличаются. Skipped - счета,
+// которые формат не может загрузить.
+type RoundTrip struct {
+	Format   string
+	Accounts int
+	Skipped  []string
+	Dropped  []string
+	Lost     []string
+}
+
+// RoundTripReport результат проверки выгрузок после прогона
+type RoundTripReport struct {
+	Seed       int64
+	Formats    []RoundTrip
+	Simulation Report
+}
+
+// Err возвращает потерю данных или нарушение инварианта прогона как ошибку или nil
+func (r RoundTripReport) Err() error {
+	if err := r.Simulation.Err(); err != nil {
+		return err
+	}
+
+	for _, format := range r.Formats {
+		if len(format.Lost) > 0 {
+			return fmt.Errorf("%w: %s: %s (seed %d)",
+				errors.ErrRoundTripLoss, format.Format, strings.Join(format.Lost, ", "), r.Seed)
+		}
+	}
+
+	return nil
+}
+
+// RunRoundTrips выполняет прогон, затем выгружает получившиеся счета в
+// каждом формате, загружает обратно в пустое хранилище и сравнивает поля
+// счетов, транзакций и (для резервной копии) событий журнала.
+func RunRoundTrips(ctx context.Context, opts Options) (RoundTripReport, error) {
+	s, err := run(ctx, opts)
+	report := RoundTripReport{Seed: opts.Seed, Simulation: s.report}
+	if err != nil || s.report.Violation != nil {
+		return report, err
+	}
+
+	accounts, err := s.storage.GetAllAccounts(ctx)
+	if err != nil {
+		return report, err
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+
+	for _, check := range []func(context.Context, []*models.Account) (RoundTrip, error){
+		s.backupRoundTrip, s.csvRoundTrip, s.jsonRoundTrip,
+	} {
+		result, err := check(ctx, accounts)
+		if err != nil {
+			return report, fmt.Errorf("%s: %w", result.Format, err)
+		}
+		report.Formats = append(report.Formats, result)
+	}
+
+	return report, nil
+}
+
+// backupRoundTrip переносит счета и журнал событий через резервную копию
+func (s *simulation) backupRoundTrip(ctx context.Context, accounts []*models.Account) (RoundTrip, error) {
+	result := RoundTrip{Format: BackupRoundTrip}
+
+	var buf bytes.Buffer
+	if err := storage.ExportBackup(ctx, s.storage, s.ledger, s.clock, &buf); err != nil {
+		return result, err
+	}
+
+	restored, ledger := storage.NewMemoryStorage(), storage.NewMemoryLedgerStorage()
+	if _, err := storage.ImportBackup(ctx, restored, ledger, &buf, false); err != nil {
+		return result, err
+	}
+
+	diff := make(fieldDiff)
+	for _, account := range accounts {
+		got, err := restored.LoadAccount(ctx, account.ID)
+		if err != nil {
+			return result, err
+		}
+		diff.accounts(account, got)
+
+		want, err := s.ledger.LoadEvents(ctx, account.ID, 0)
+		if err != nil {
+			return result, err
+		}
+		events, err := ledger.LoadEvents(ctx, account.ID, 0)
+		if err != nil {
+			return result, err
+		}
+		diff.events(want, events)
+
+		result.Accounts++
+	}
+
+	diff.split(&result)
+	return result, nil
+}
+
+// csvRoundTrip выгружает выписку каждого счета в CSV и загружает ее через
+// ImportCSV. Файл счетов собирается из ID, владельца, баланса и даты
+// открытия; счета с отрицательным балансом импорт не принимает.
+func (s *simulation) csvRoundTrip(ctx context.Context, accounts []*models.Account) (RoundTrip, error) {
+	result := RoundTrip{Format: CSVRoundTrip}
+
+	var accountsCSV, transactionsCSV bytes.Buffer
+	accountsWriter, transactionsWriter := csv.NewWriter(&accountsCSV), csv.NewWriter(&transactionsCSV)
+	accountsWriter.Write([]string{"id", "owner_name", "balance", "created_at"})
+	transactionsWriter.Write([]string{"id", "account_id", "timestamp", "type", "direction", "amount", "message", "counterparty_id"})
+
+	var exported []*models.Account
+	for _, account := range accounts {
+		if account.Balance < 0 {
+			result.Skipped = append(result.Skipped, account.ID)
+			continue
+		}
+
+		var statement bytes.Buffer
+		if err := s.accountService(account).ExportStatement(ctx, models.CSVFormat, &statement); err != nil {
+			return result, err
+		}
+
+		rows, err := readStatementCSV(&statement)
+		if err != nil {
+			return result, err
+		}
+		for _, row := range rows {
+			transactionsWriter.Write([]string{
+				row["id"], account.ID, row["timestamp"], row["type"], row["direction"],
+				row["amount"], row["message"], row["counterparty_id"],
+			})
+		}
+
+		accountsWriter.Write([]string{
+			account.ID, account.OwnerName, fmt.Sprintf("%.2f", account.Balance), account.CreatedAt.Format(time.RFC3339),
+		})
+		exported = append(exported, account)
+	}
+
+	accountsWriter.Flush()
+	transactionsWriter.Flush()
+	if err := accountsWriter.Error(); err != nil {
+		return result, err
+	}
+	if err := transactionsWriter.Error(); err != nil {
+		return result, err
+	}
+
+	restored := storage.NewMemoryStorage()
+	if _, err := services.ImportCSV(ctx, restored, storage.NewMemoryLedgerStorage(), testkit.NewSequentialIDs(), s.clock, &accountsCSV, &transactionsCSV); err != nil {
+		return result, err
+	}
+
+	diff := make(fieldDiff)
+	for _, account := range exported {
+		got, err := restored.LoadAccount(ctx, account.ID)
+		if err != nil {
+			return result, err
+		}
+		diff.accounts(account, got)
+		result.Accounts++
+	}
+
+	diff.split(&result)
+	return result, nil
+}
+
+// statementDocument выписка в JSON в том виде, в каком ее читает получатель
+type statementDocument struct {
+	AccountID    string  `json:"account_id"`
+	OwnerName    string  `json:"owner_name"`
+	Balance      float64 `json:"balance"`
+	Transactions []struct {
+		ID             string                 `json:"id"`
+		Type           models.TransactionType `json:"type"`
+		Direction      models.EntryDirection  `json:"direction"`
+		Amount         float64                `json:"amount"`
+		Timestamp      time.Time              `json:"timestamp"`
+		Message        string                 `json:"message"`
+		TransferID     string                 `json:"transfer_id"`
+		CounterpartyID string                 `json:"counterparty_id"`
+		Source         models.DepositSource   `json:"source"`
+		ReversedBy     string                 `json:"reversed_by"`
+		ReversalOf     string                 `json:"reversal_of"`
+		CorrectedBy    []string               `json:"corrected_by"`
+		CorrectionOf   string                 `json:"correction_of"`
+		UnderReview    bool                   `json:"under_review"`
+	} `json:"transactions"`
+}
+
+// jsonRoundTrip выгружает выписку каждого счета в JSON и читает документ
+// обратно в счет
+func (s *simulation) jsonRoundTrip(ctx context.Context, accounts []*models.Account) (RoundTrip, error) {
+	result := RoundTrip{Format: JSONRoundTrip}
+
+	diff := make(fieldDiff)
+	for _, account := range accounts {
+		var buf bytes.Buffer
+		if err := s.accountService(account).ExportStatement(ctx, models.JSONFormat, &buf); err != nil {
+			return result, err
+		}
+
+		var document statementDocument
+		if err := json.Unmarshal(buf.Bytes(), &document); err != nil {
+			return result, err
+		}
+
+		got := &models.Account{ID: document.AccountID, OwnerName: document.OwnerName, Balance: document.Balance}
+		for _, tx := range document.Transactions {
+			got.Transactions = append(got.Transactions, models.Transaction{
+				ID:             tx.ID,
+				Type:           tx.Type,
+				Direction:      tx.Direction,
+				Amount:         tx.Amount,
+				Timestamp:      tx.Timestamp,
+				Message:        tx.Message,
+				TransferID:     tx.TransferID,
+				CounterpartyID: tx.CounterpartyID,
+				Source:         tx.Source,
+				ReversedBy:     tx.ReversedBy,
+				ReversalOf:     tx.ReversalOf,
+				CorrectedBy:    tx.CorrectedBy,
+				RelatedID:      tx.CorrectionOf,
+				UnderReview:    tx.UnderReview,
+			})
+		}
+
+		diff.accounts(account, got)
+		result.Accounts++
+	}
+
+	diff.split(&result)
+	return result, nil
+}
+
+// accountService создает сервис счета для выгрузки выписки
+func (s *simulation) accountService(account *models.Account) interfaces.AccountService {
+	return services.NewAccountService(account, s.storage, s.ledger, services.WithClock(s.clock))
+}
+
+// readStatementCSV читает выписку в CSV в строки с доступом по имени колонки
+func readStatementCSV(r io.Reader) ([]map[string]string, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+
+	header := records[0]
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// fieldDiff множество полей ("Account.Status", "Transaction.Hash"),
+// значения которых после обратной загрузки отличаются
+type fieldDiff map[string]bool
+
+// accounts сравнивает поля счетов и их транзакций, сопоставленных по ID.
+// Version назначает хранилище при сохранении, она не сравнивается.
+func (d fieldDiff) accounts(want, got *models.Account) {
+	d.structs("Account", *want, *got, "Transactions", "Version")
+
+	loaded := make(map[string]models.Transaction, len(got.Transactions))
+	for _, tx := range got.Transactions {
+		loaded[tx.ID] = tx
+	}
+	for _, tx := range want.Transactions {
+		if other, ok := loaded[tx.ID]; ok {
+			d.structs("Transaction", tx, other)
+		} else {
+			d["Account.Transactions"] = true
+		}
+	}
+	if len(loaded) != len(want.Transactions) {
+		d["Account.Transactions"] = true
+	}
+}
+
+// events сравнивает события журнала счета по порядку
+func (d fieldDiff) events(want, got []models.AccountEvent) {
+	if len(want) != len(got) {
+		d["Ledger.Events"] = true
+		return
+	}
+	for i := range want {
+		d.structs("Event", want[i], got[i])
+	}
+}
+
+// structs сравнивает поля двух значений одного типа-структуры, кроме skip.
+// Время сравнивается как момент, пустой и нулевой срезы считаются равными.
+func (d fieldDiff) structs(prefix string, want, got any, skip ...string) {
+	wv, gv := reflect.ValueOf(want), reflect.ValueOf(got)
+	for i := 0; i < wv.NumField(); i++ {
+		name := wv.Type().Field(i).Name
+		if slices.Contains(skip, name) {
+			continue
+		}
+		if !sameValue(wv.Field(i), gv.Field(i)) {
+			d[prefix+"."+name] = true
+		}
+	}
+}
+
+// split раскладывает отличающиеся поля на документированные потери формата
+// и потерю данных, которые формат должен сохранять
+func (d fieldDiff) split(result *RoundTrip) {
+	kept, partial := keptFields[result.Format]
+	for field := range d {
+		if partial && !slices.Contains(kept, field) && field != "Account.Transactions" {
+			result.Dropped = append(result.Dropped, field)
+		} else {
+			result.Lost = append(result.Lost, field)
+		}
+	}
+
+	sort.Strings(result.Dropped)
+	sort.Strings(result.Lost)
+}
+
+// sameValue сравнивает значения полей
+func sameValue(want, got reflect.Value) bool {
+	if t, ok := want.Interface().(time.Time); ok {
+		return t.Equal(got.Interface().(time.Time))
+	}
+	if kind := want.Kind(); (kind == reflect.Slice || kind == reflect.Map) && want.Len() == 0 && got.Len() == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(want.Interface(), got.Interface())
+}
@@ -0,0 +1,49 @@
+package simtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"bankapp/sim"
+)
+
+// Check выполняет прогон sim.Run в тесте и завершает тест при нарушении
+// инварианта, выводя последние операции прогона
+func Check(t testing.TB, opts sim.Options) sim.Report {
+	t.Helper()
+
+	report, err := sim.Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("прогон (seed %d): %v", opts.Seed, err)
+	}
+
+	if err := report.Err(); err != nil {
+		for _, step := range report.Trace {
+			t.Logf("%s", step)
+		}
+		t.Fatal(err)
+	}
+
+	return report
+}
+
+// CheckRoundTrips выполняет проверку выгрузок sim.RunRoundTrips в тесте и
+// завершает тест при потере данных, которые формат должен сохранять
+func CheckRoundTrips(t testing.TB, opts sim.Options) sim.RoundTripReport {
+	t.Helper()
+
+	report, err := sim.RunRoundTrips(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("проверка выгрузок (seed %d): %v", opts.Seed, err)
+	}
+
+	for _, format := range report.Formats {
+		t.Logf("%s: не переносятся %s", format.Format, strings.Join(format.Dropped, ", "))
+	}
+	if err := report.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return report
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"bankapp/i18n"
+	"bankapp/models"
+	"strings"
+)
+
+// transactionAnnotation пояснение к строке выписки, чтобы пара встречных
+// записей не выглядела необъяснимой: исправленная операция ссылается на
+// компенсирующую запись, а компенсирующая - на исходную. Пустая строка,
+// если пояснять нечего.
+func transactionAnnotation(tr *i18n.Translator, tx models.Transaction) string {
+	var notes []string
+	if tx.UnderReview {
+		notes = append(notes, tr.T("на проверке"))
+	}
+	if tx.ReversedBy != "" {
+		notes = append(notes, tr.Sprintf("сторнирована операцией %s", tx.ReversedBy))
+	}
+	if tx.ReversalOf != "" {
+		notes = append(notes, tr.Sprintf("сторно операции %s", tx.ReversalOf))
+	}
+	if len(tx.CorrectedBy) > 0 {
+		notes = append(notes, tr.Sprintf("скорректирована операцией %s", strings.Join(tx.CorrectedBy, ", ")))
+	}
+	if tx.Type == models.FeeCorrectionTransaction && tx.RelatedID != "" {
+		notes = append(notes, tr.Sprintf("корректировка комиссии %s", tx.RelatedID))
+	}
+
+	return strings.Join(notes, "; ")
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// StatementEncryption алгоритм шифрования выписок
+const StatementEncryption = "X25519-HKDF-SHA256-AES256GCM"
+
+// statementKeyInfo контекст вывода ключа, отделяющий выписки от других применений ключа
+const statementKeyInfo = "bankapp statement v1"
+
+// WithStatementSender задает канал доставки выписок клиенту
+func WithStatementSender(sender interfaces.StatementSender) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.statements = sender
+	}
+}
+
+// SetStatementKey сохраняет открытый ключ X25519, которым будут шифроваться
+// отправляемые выписки. Пустой ключ отключает шифрование.
+func (s *AccountServiceImpl) SetStatementKey(ctx context.Context, publicKey []byte) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "set_statement_key", 0, "", err)
+	}()
+
+	if len(publicKey) > 0 {
+		if _, err := ecdh.X25519().NewPublicKey(publicKey); err != nil {
+			return errors.ErrInvalidStatementKey
+		}
+	}
+
+	s.account.StatementKey = publicKey
+	return s.saveAccount(ctx)
+}
+
+// HasStatementKey сообщает, загружен ли ключ шифрования выписок
+func (s *AccountServiceImpl) HasStatementKey() bool {
+	return len(s.account.StatementKey) > 0
+}
+
+// DeliverStatement формирует выписку в указанном формате и отправляет ее
+// клиенту. Если клиент загрузил ключ, выписка шифруется и посредники
+// видят только получателя и формат.
+func (s *AccountServiceImpl) DeliverStatement(ctx context.Context, format models.ExportFormat) (delivery models.StatementDelivery, err error) {
+	defer func() {
+		s.auditOperation(ctx, "deliver_statement", 0, string(format), err)
+	}()
+
+	if s.statements == nil {
+		return delivery, errors.ErrNoStatementSender
+	}
+
+	var buf bytes.Buffer
+	if err := s.ExportStatement(ctx, format, &buf); err != nil {
+		return delivery, err
+	}
+
+	delivery = models.StatementDelivery{
+		AccountID: s.account.ID,
+		Format:    format,
+		CreatedAt: s.now(),
+		Content:   buf.Bytes(),
+	}
+
+	if s.HasStatementKey() {
+		envelope, err := EncryptStatement(s.account.StatementKey, delivery.Content)
+		if err != nil {
+			return delivery, err
+		}
+		delivery.Content = nil
+		delivery.Envelope = &envelope
+	}
+
+	return delivery, s.statements.SendStatement(ctx, delivery)
+}
+
+// EncryptStatement шифрует данные открытым ключом X25519 получателя
+func EncryptStatement(publicKey, plaintext []byte) (models.EncryptedEnvelope, error) {
+	recipient, err := ecdh.X25519().NewPublicKey(publicKey)
+	if err != nil {
+		return models.EncryptedEnvelope{}, errors.ErrInvalidStatementKey
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return models.EncryptedEnvelope{}, err
+	}
+
+	secret, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return models.EncryptedEnvelope{}, err
+	}
+
+	aead, err := statementCipher(secret, ephemeral.PublicKey().Bytes(), publicKey)
+	if err != nil {
+		return models.EncryptedEnvelope{}, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return models.EncryptedEnvelope{}, err
+	}
+
+	return models.EncryptedEnvelope{
+		Algorithm:    StatementEncryption,
+		EphemeralKey: ephemeral.PublicKey().Bytes(),
+		Nonce:        nonce,
+		Ciphertext:   aead.Seal(nil, nonce, plaintext, nil),
+	}, nil
+}
+
+// DecryptStatement расшифровывает выписку закрытым ключом X25519 получателя
+func DecryptStatement(privateKey []byte, envelope models.EncryptedEnvelope) ([]byte, error) {
+	if envelope.Algorithm != StatementEncryption {
+		return nil, fmt.Errorf("неизвестный алгоритм шифрования %q", envelope.Algorithm)
+	}
+
+	recipient, err := ecdh.X25519().NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, errors.ErrInvalidStatementKey
+	}
+
+	ephemeral, err := ecdh.X25519().NewPublicKey(envelope.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := recipient.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := statementCipher(secret, envelope.EphemeralKey, recipient.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, envelope.Nonce, envelope.Ciphertext, nil)
+}
+
+// statementCipher выводит ключ AES-256-GCM из общего секрета, привязывая его
+// к обоим открытым ключам
+func statementCipher(secret, ephemeralKey, recipientKey []byte) (cipher.AEAD, error) {
+	salt := append(append([]byte{}, ephemeralKey...), recipientKey...)
+	key, err := hkdf.Key(sha256.New, secret, salt, statementKeyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// WebhookStatementSender отправляет выписки POST-запросом с JSON на внешний URL
+type WebhookStatementSender struct {
+	webhook *WebhookNotifier
+}
+
+// NewWebhookStatementSender создает канал доставки выписок на url с теми же
+// повторами, что и у уведомлений
+func NewWebhookStatementSender(url string, client *http.Client, retries int) interfaces.StatementSender {
+	return &WebhookStatementSender{
+		webhook: &WebhookNotifier{
+			url:     url,
+			client:  client,
+			retries: retries,
+		},
+	}
+}
+
+// SendStatement отправляет выписку с повторами
+func (s *WebhookStatementSender) SendStatement(ctx context.Context, delivery models.StatementDelivery) error {
+	body, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	return s.webhook.post(ctx, body, "")
+}
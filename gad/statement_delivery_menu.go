@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"strings"
+
+	"bankapp/errors"
+	"bankapp/models"
+	"bankapp/services"
+)
+
+// deliverStatement отправляет выписку текущего счета по внешнему каналу
+func (app *BankApp) deliverStatement(ctx context.Context) {
+	app.printf("Введите формат (csv/json, Enter - %s): ", app.prefs.StatementFormat)
+	app.scanner.Scan()
+	format := models.ExportFormat(strings.ToLower(strings.TrimSpace(app.scanner.Text())))
+	if format == "" {
+		format = app.prefs.StatementFormat
+	}
+
+	delivery, err := app.currentAccount.DeliverStatement(ctx, format)
+	if err != nil {
+		app.printf("Ошибка при отправке выписки: %v\n", err)
+		return
+	}
+
+	if delivery.Envelope != nil {
+		app.println("Выписка зашифрована и отправлена")
+		return
+	}
+	app.println("Выписка отправлена без шифрования")
+}
+
+// editStatementKey загружает или удаляет открытый ключ шифрования выписок
+func (app *BankApp) editStatementKey(ctx context.Context) {
+	if app.currentAccount.HasStatementKey() {
+		app.println("Ключ шифрования выписок загружен")
+	} else {
+		app.println("Ключ шифрования выписок не загружен")
+	}
+
+	input := app.readLine("Ключ X25519 (base64, \"-\" - удалить): ")
+	if input == "" {
+		return
+	}
+
+	var key []byte
+	if input != "-" {
+		decoded, err := base64.StdEncoding.DecodeString(input)
+		if err != nil {
+			app.printf("Ошибка: %v\n", errors.ErrInvalidStatementKey)
+			return
+		}
+		key = decoded
+	}
+
+	if err := app.currentAccount.SetStatementKey(ctx, key); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	if key == nil {
+		app.println("Ключ удален")
+		return
+	}
+	app.println("Ключ сохранен, выписки будут шифроваться")
+}
+
+// renderStatement сохраняет оформленную выписку за период в HTML-файл
+// для отправки клиенту
+func (app *BankApp) renderStatement(ctx context.Context) {
+	from, err := app.readOptionalDate("Начало периода (ГГГГ-ММ-ДД, Enter - с открытия счета): ")
+	if err != nil {
+		return
+	}
+
+	to, err := app.readOptionalDate("Конец периода включительно (ГГГГ-ММ-ДД, Enter - по сегодня): ")
+	if err != nil {
+		return
+	}
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	path := app.readLine("Введите путь к файлу: ")
+	if path == "" {
+		app.println("Путь к файлу не может быть пустым")
+		return
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		app.printf("Ошибка при создании файла: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	renderer := app.statementRenderer(services.HTMLStatementFormat)
+	if err := renderer.Render(ctx, app.currentAccount.BuildStatement(ctx, from, to), file); err != nil {
+		app.printf("Ошибка при экспорте: %v\n", err)
+		return
+	}
+
+	app.printf("Выписка сохранена в %s\n", path)
+}
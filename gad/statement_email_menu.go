@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"bankapp/interfaces"
+	"bankapp/services"
+)
+
+// newStatementMailer создает отправку выписок письмом с подписями на языке
+// и в формате дат текущего пользователя
+func (app *BankApp) newStatementMailer() *services.StatementMailer {
+	return services.NewStatementMailer(app.emailSender,
+		app.statementRenderer(services.TextStatementFormat),
+		app.statementRenderer(services.HTMLStatementFormat),
+		app.tr)
+}
+
+// statementRenderer создает оформление выписки в формате format по
+// настройкам конфигурации. Если шаблон стал недоступен после запуска,
+// используется оформление по умолчанию.
+func (app *BankApp) statementRenderer(format string) interfaces.StatementRenderer {
+	renderer, err := services.NewStatementRenderer(format, app.tr, app.prefs.DateFormat, app.currency, app.statementFormats[format])
+	if err == nil {
+		return renderer
+	}
+
+	app.logger.Warn("ошибка оформления выписки, используется оформление по умолчанию",
+		"format", format,
+		"error", err.Error())
+	if format == services.HTMLStatementFormat {
+		return services.NewHTMLStatementRenderer(app.tr, app.prefs.DateFormat, app.currency)
+	}
+
+	return services.NewTextStatementRenderer(app.tr, app.prefs.DateFormat, app.currency)
+}
+
+// sendMonthlyStatements при запуске досылает подписанным счетам выписки
+// за прошлый месяц
+func (app *BankApp) sendMonthlyStatements(ctx context.Context) {
+	now := app.clock.Now()
+	period := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -1, 0)
+
+	report, err := services.SendMonthlyStatements(ctx, app.storage, app.newStatementMailer(), app.clock, period)
+	if err != nil {
+		app.printf("Ошибка ежемесячной рассылки выписок: %v\n", err)
+		return
+	}
+
+	for _, result := range report.Results {
+		if result.Error != "" {
+			app.printf("Выписка для счета %s не отправлена на %s: %s\n", result.AccountID, result.Email, result.Error)
+		}
+	}
+	if report.Sent+report.Failed > 0 {
+		app.printf("Выписки за %s: отправлено %d, ошибок %d\n", report.Period, report.Sent, report.Failed)
+	}
+}
+
+// manageStatementEmail настраивает адрес для выписок и отправляет выписку письмом
+func (app *BankApp) manageStatementEmail(ctx context.Context) {
+	if account, err := app.storage.LoadAccount(ctx, app.currentAccount.AccountID()); err == nil {
+		if account.StatementEmail == "" {
+			app.println("Адрес для выписок не указан")
+		} else {
+			app.printf("Адрес для выписок: %s\n", account.StatementEmail)
+			if account.MonthlyStatements {
+				app.println("Ежемесячная рассылка включена")
+			}
+		}
+	}
+
+	app.println("1. Изменить адрес и ежемесячную рассылку")
+	app.println("2. Отправить выписку за период")
+	app.print("Выберите опцию: ")
+	app.scanner.Scan()
+
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "1":
+		app.editStatementEmail(ctx)
+	case "2":
+		app.emailStatement(ctx)
+	default:
+		app.println("Неверный выбор. Попробуйте снова.")
+	}
+}
+
+// editStatementEmail сохраняет адрес для выписок и подписку на рассылку
+func (app *BankApp) editStatementEmail(ctx context.Context) {
+	email := app.readLine("Адрес электронной почты (Enter - отключить): ")
+
+	monthly := false
+	if email != "" {
+		app.print("Присылать выписку ежемесячно? (y/n): ")
+		app.scanner.Scan()
+		monthly = strings.ToLower(strings.TrimSpace(app.scanner.Text())) == "y"
+	}
+
+	if err := app.currentAccount.SetStatementEmail(ctx, email, monthly); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	if email == "" {
+		app.println("Отправка выписок по почте отключена")
+		return
+	}
+	app.println("Адрес для выписок сохранен")
+}
+
+// emailStatement отправляет выписку за период на адрес счета
+func (app *BankApp) emailStatement(ctx context.Context) {
+	from, err := app.readOptionalDate("Начало периода (ГГГГ-ММ-ДД, Enter - с открытия счета): ")
+	if err != nil {
+		return
+	}
+
+	to, err := app.readOptionalDate("Конец периода включительно (ГГГГ-ММ-ДД, Enter - по сегодня): ")
+	if err != nil {
+		return
+	}
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	app.print("Добавить HTML-версию? (y/n): ")
+	app.scanner.Scan()
+	html := strings.ToLower(strings.TrimSpace(app.scanner.Text())) == "y"
+
+	if err := app.currentAccount.EmailStatement(ctx, from, to, html); err != nil {
+		app.printf("Ошибка при отправке выписки: %v\n", err)
+		return
+	}
+
+	app.println("Выписка отправлена по электронной почте")
+}
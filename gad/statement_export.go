@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/i18n"
+	"bankapp/models"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statementJSON представление выписки для экспорта в JSON
+type statementJSON struct {
+	AccountID    string            `json:"account_id"`
+	OwnerName    string            `json:"owner_name"`
+	Balance      float64           `json:"balance"`
+	Transactions []transactionJSON `json:"transactions"`
+}
+
+// transactionJSON представление транзакции для экспорта в JSON
+type transactionJSON struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Direction      string    `json:"direction,omitempty"`
+	Amount         float64   `json:"amount"`
+	Timestamp      time.Time `json:"timestamp"`
+	Message        string    `json:"message"`
+	TransferID     string    `json:"transfer_id,omitempty"`
+	CounterpartyID string    `json:"counterparty_id,omitempty"`
+	Source         string    `json:"source,omitempty"`
+	ReversedBy     string    `json:"reversed_by,omitempty"`
+	ReversalOf     string    `json:"reversal_of,omitempty"`
+	CorrectedBy    []string  `json:"corrected_by,omitempty"`
+	CorrectionOf   string    `json:"correction_of,omitempty"`
+	UnderReview    bool      `json:"under_review,omitempty"`
+
+	Attachments []attachmentJSON `json:"attachments,omitempty"`
+}
+
+// attachmentJSON представление вложения для экспорта в JSON
+type attachmentJSON struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Reference string `json:"reference,omitempty"`
+	Size      int    `json:"size,omitempty"`
+}
+
+// BuildStatement собирает выписку за период [from, to). Нулевой from - с
+// открытия счета, нулевой to - по текущий момент.
+func (s *AccountServiceImpl) BuildStatement(ctx context.Context, from, to time.Time) models.Statement {
+	return buildStatement(s.account, from, to, s.now())
+}
+
+// buildStatement собирает выписку по счету за период [from, to),
+// сформированную в момент now
+func buildStatement(account *models.Account, from, to, now time.Time) models.Statement {
+	statement := models.Statement{
+		AccountID:   account.ID,
+		OwnerName:   account.OwnerName,
+		From:        from,
+		To:          to,
+		GeneratedAt: now,
+	}
+	if statement.From.IsZero() {
+		statement.From = account.CreatedAt
+	}
+	if statement.To.IsZero() {
+		statement.To = statement.GeneratedAt
+	}
+
+	for _, tx := range account.Transactions {
+		switch {
+		case tx.Timestamp.Before(statement.From):
+			statement.OpeningBalance += tx.SignedAmount()
+		case tx.Timestamp.Before(statement.To):
+			statement.Transactions = append(statement.Transactions, tx)
+		}
+	}
+
+	statement.ClosingBalance = statement.OpeningBalance
+	for _, tx := range statement.Transactions {
+		statement.ClosingBalance += tx.SignedAmount()
+	}
+
+	return statement
+}
+
+// ExportStatement выгружает выписку в указанном формате. Выписка не
+// переносит настройки счета (лимиты, статус, PIN), RelatedID (кроме
+// корректировок комиссий в JSON), хеши цепочки, разбивку по купюрам,
+// оценку риска, разметку и содержимое вложений. В CSV нет полей счета,
+// Source и ссылок на сторно и корректировки; в JSON нет даты открытия.
+// Какие поля теряются при обратной загрузке, показывает sim.RunRoundTrips.
+func (s *AccountServiceImpl) ExportStatement(ctx context.Context, format models.ExportFormat, w io.Writer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	switch format {
+	case models.CSVFormat:
+		return s.exportCSV(w)
+	case models.JSONFormat:
+		return s.exportJSON(w)
+	default:
+		return errors.ErrUnsupportedFormat
+	}
+}
+
+// exportCSV пишет транзакции счета в CSV с заголовком
+func (s *AccountServiceImpl) exportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, tx := range s.account.Transactions {
+		if err := cw.Write(s.csvRecord(tx)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvHeader заголовок выгрузки транзакций в CSV
+var csvHeader = []string{"id", "timestamp", "type", "direction", "amount", "message", "transfer_id", "counterparty_id", "attachments", "annotation"}
+
+// csvRecord строка CSV для транзакции
+func (s *AccountServiceImpl) csvRecord(tx models.Transaction) []string {
+	return []string{
+		tx.ID,
+		tx.Timestamp.Format(time.RFC3339),
+		string(tx.Type),
+		string(tx.Direction),
+		strconv.FormatFloat(tx.Amount, 'f', 2, 64),
+		tx.Message,
+		tx.TransferID,
+		tx.CounterpartyID,
+		attachmentList(s.tr, tx.Attachments),
+		transactionAnnotation(s.tr, tx),
+	}
+}
+
+// exportJSON пишет выписку одним JSON-документом
+func (s *AccountServiceImpl) exportJSON(w io.Writer) error {
+	statement := statementJSON{
+		AccountID:    s.account.ID,
+		OwnerName:    s.account.OwnerName,
+		Balance:      s.account.Balance,
+		Transactions: make([]transactionJSON, 0, len(s.account.Transactions)),
+	}
+
+	for _, tx := range s.account.Transactions {
+		statement.Transactions = append(statement.Transactions, toTransactionJSON(tx))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(statement)
+}
+
+// toTransactionJSON преобразует транзакцию в JSON-представление
+func toTransactionJSON(tx models.Transaction) transactionJSON {
+	result := transactionJSON{
+		ID:             tx.ID,
+		Type:           string(tx.Type),
+		Direction:      string(tx.Direction),
+		Amount:         tx.Amount,
+		Timestamp:      tx.Timestamp,
+		Message:        tx.Message,
+		TransferID:     tx.TransferID,
+		CounterpartyID: tx.CounterpartyID,
+		Source:         string(tx.Source),
+		ReversedBy:     tx.ReversedBy,
+		ReversalOf:     tx.ReversalOf,
+		CorrectedBy:    tx.CorrectedBy,
+		UnderReview:    tx.UnderReview,
+		Attachments:    toAttachmentsJSON(tx.Attachments),
+	}
+	if tx.Type == models.FeeCorrectionTransaction {
+		result.CorrectionOf = tx.RelatedID
+	}
+
+	return result
+}
+
+// attachmentList перечисляет вложения транзакции через точку с запятой
+func attachmentList(tr *i18n.Translator, attachments []models.Attachment) string {
+	labels := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		labels = append(labels, attachmentLabel(tr, attachment))
+	}
+
+	return strings.Join(labels, "; ")
+}
+
+// toAttachmentsJSON преобразует вложения в JSON-представление
+func toAttachmentsJSON(attachments []models.Attachment) []attachmentJSON {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	result := make([]attachmentJSON, 0, len(attachments))
+	for _, attachment := range attachments {
+		result = append(result, attachmentJSON{
+			ID:        attachment.ID,
+			Name:      attachment.Name,
+			Reference: attachment.Reference,
+			Size:      attachment.Size,
+		})
+	}
+
+	return result
+}
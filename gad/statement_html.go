@@ -0,0 +1,46 @@
+package services
+
+import (
+	"bankapp/i18n"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"html/template"
+)
+
+// htmlStatementTemplate разметка выписки по умолчанию; подписи переводятся функцией T
+var htmlStatementTemplate = template.Must(template.New("statement").Parse(`<!DOCTYPE html>
+<html lang="{{.Locale}}">
+<head>
+<meta charset="utf-8">
+<title>{{call .T "Выписка по счету"}} {{.AccountID}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+td.amount { text-align: right; white-space: nowrap; }
+.summary td { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{call .T "Выписка по счету"}}</h1>
+<p>{{call .T "Владелец"}}: {{.OwnerName}}<br>
+{{call .T "Счет"}}: {{.AccountID}}<br>
+{{call .T "Период"}}: {{.From}} - {{.To}}</p>
+<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+<tr class="summary"><td colspan="{{.SummarySpan}}">{{call .T "Остаток на начало периода"}}</td><td class="amount">{{.OpeningBalance}}</td></tr>
+{{range .Rows}}<tr>{{range .Cells}}<td{{if .Numeric}} class="amount"{{end}}>{{.Value}}{{if .Note}}<br><small>{{.Note}}</small>{{end}}</td>{{end}}</tr>
+{{end}}<tr class="summary"><td colspan="{{.SummarySpan}}">{{call .T "Остаток на конец периода"}}</td><td class="amount">{{.ClosingBalance}}</td></tr>
+</table>
+<p>{{call .T "Сформировано"}}: {{.GeneratedAt}}</p>
+</body>
+</html>
+`))
+
+// NewHTMLStatementRenderer создает оформление выписки в HTML по шаблону
+// по умолчанию с подписями на языке переводчика tr
+func NewHTMLStatementRenderer(tr *i18n.Translator, dateFormat models.DateFormat, currency string) interfaces.StatementRenderer {
+	// Настройки по умолчанию всегда корректны
+	renderer, _ := NewStatementRenderer(HTMLStatementFormat, tr, dateFormat, currency, models.StatementLayout{})
+	return renderer
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/i18n"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Форматы выписки, оформление которых настраивается шаблоном
+const (
+	TextStatementFormat = "text"
+	HTMLStatementFormat = "html"
+)
+
+// defaultStatementPrecision знаков после запятой в суммах выписки по умолчанию
+const defaultStatementPrecision = 2
+
+// maxStatementPrecision наибольшее число знаков после запятой в суммах выписки
+const maxStatementPrecision = 6
+
+// statementColumn колонка таблицы операций: подпись и значение в строке
+type statementColumn struct {
+	title   string
+	numeric bool
+	value   func(row statementRow) string
+}
+
+// statementColumns колонки, доступные для выбора в оформлении выписки
+var statementColumns = map[string]statementColumn{
+	"date":    {title: "Дата", value: func(row statementRow) string { return row.Date }},
+	"type":    {title: "Тип", value: func(row statementRow) string { return string(row.Type) }},
+	"message": {title: "Описание", value: func(row statementRow) string { return row.Message }},
+	"note":    {title: "Пояснение", value: func(row statementRow) string { return row.Annotation }},
+	"amount":  {title: "Сумма", numeric: true, value: func(row statementRow) string { return row.Amount }},
+	"balance": {title: "Остаток", numeric: true, value: func(row statementRow) string { return row.Balance }},
+}
+
+// defaultStatementColumns колонки выписки по умолчанию для каждого формата
+var defaultStatementColumns = map[string][]string{
+	TextStatementFormat: {"date", "type", "amount", "balance", "message"},
+	HTMLStatementFormat: {"date", "type", "message", "amount", "balance"},
+}
+
+// statementExecutor разобранный шаблон выписки, текстовый или HTML
+type statementExecutor interface {
+	Execute(w io.Writer, data any) error
+}
+
+// TemplateStatementRenderer оформляет выписку шаблоном. Шаблон получает
+// statementView: подписи переводятся через {{call .T "..."}}, строки
+// операций доступны как поля и как ячейки выбранных колонок.
+type TemplateStatementRenderer struct {
+	tr         *i18n.Translator
+	dateFormat models.DateFormat
+	currency   string
+	format     string
+	columns    []string
+	width      int
+	precision  int
+	template   statementExecutor
+}
+
+// NewStatementRenderer создает оформление выписки в формате format (text
+// или html) по настройкам layout. Пустые настройки - колонки и шаблон
+// формата по умолчанию. Шаблон из файла читается при создании.
+func NewStatementRenderer(format string, tr *i18n.Translator, dateFormat models.DateFormat, currency string, layout models.StatementLayout) (interfaces.StatementRenderer, error) {
+	columns, ok := defaultStatementColumns[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: формат %q", errors.ErrInvalidLayout, format)
+	}
+
+	if len(layout.Columns) > 0 {
+		columns = layout.Columns
+	}
+	for _, name := range columns {
+		if _, ok := statementColumns[name]; !ok {
+			return nil, fmt.Errorf("%w: колонка %q", errors.ErrInvalidLayout, name)
+		}
+	}
+
+	if layout.Width < 0 || layout.Precision < 0 || layout.Precision > maxStatementPrecision {
+		return nil, fmt.Errorf("%w: ширина или точность", errors.ErrInvalidLayout)
+	}
+
+	precision := layout.Precision
+	if precision == 0 {
+		precision = defaultStatementPrecision
+	}
+
+	tmpl, err := parseStatementTemplate(format, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateStatementRenderer{
+		tr:         tr,
+		dateFormat: dateFormat,
+		currency:   currency,
+		format:     format,
+		columns:    columns,
+		width:      layout.Width,
+		precision:  precision,
+		template:   tmpl,
+	}, nil
+}
+
+// parseStatementTemplate разбирает шаблон из настроек: текст Template,
+// файл TemplateFile или шаблон формата по умолчанию
+func parseStatementTemplate(format string, layout models.StatementLayout) (statementExecutor, error) {
+	source := layout.Template
+	if layout.TemplateFile != "" {
+		if source != "" {
+			return nil, fmt.Errorf("%w: задан и template, и template_file", errors.ErrInvalidLayout)
+		}
+
+		data, err := os.ReadFile(layout.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrInvalidLayout, err)
+		}
+		source = string(data)
+	}
+
+	if source == "" {
+		if format == HTMLStatementFormat {
+			return htmlStatementTemplate, nil
+		}
+		return textStatementTemplate, nil
+	}
+
+	var tmpl statementExecutor
+	var err error
+	if format == HTMLStatementFormat {
+		tmpl, err = htmltemplate.New("statement").Parse(source)
+	} else {
+		tmpl, err = template.New("statement").Parse(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrInvalidLayout, err)
+	}
+
+	return tmpl, nil
+}
+
+// ValidateStatementFormats проверяет настройки оформления выписок по форматам
+func ValidateStatementFormats(formats map[string]models.StatementLayout) error {
+	for format, layout := range formats {
+		if _, err := NewStatementRenderer(format, nil, models.ISODateFormat, "", layout); err != nil {
+			return fmt.Errorf("%s: %w", format, err)
+		}
+	}
+
+	return nil
+}
+
+// statementView данные выписки, подготовленные для шаблона. Header -
+// подписи выбранных колонок, HeaderLine - их строка в текстовой выписке,
+// если задана ширина колонок.
+type statementView struct {
+	T              func(string) string
+	Locale         string
+	AccountID      string
+	OwnerName      string
+	From           string
+	To             string
+	OpeningBalance string
+	ClosingBalance string
+	GeneratedAt    string
+	Header         []string
+	HeaderLine     string
+	SummarySpan    int
+	Rows           []statementRow
+}
+
+// statementRow строка таблицы операций с остатком после операции и
+// пояснением к сторно и корректировкам. Note - пояснение, если оно не
+// выведено отдельной колонкой.
+type statementRow struct {
+	Date       string
+	Type       models.TransactionType
+	Message    string
+	Note       string
+	Annotation string
+	Amount     string
+	Balance    string
+	Cells      []statementCell
+	Line       string
+}
+
+// statementCell ячейка выбранной колонки; пояснение Note выводится в
+// ячейке описания HTML-выписки
+type statementCell struct {
+	Value   string
+	Numeric bool
+	Note    string
+}
+
+// Render записывает выписку в w
+func (r *TemplateStatementRenderer) Render(ctx context.Context, statement models.Statement, w io.Writer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return r.template.Execute(w, r.view(statement))
+}
+
+// view готовит данные выписки для шаблона
+func (r *TemplateStatementRenderer) view(statement models.Statement) statementView {
+	layout := r.dateFormat.Layout()
+	view := statementView{
+		T:              r.tr.T,
+		Locale:         r.tr.Locale(),
+		AccountID:      statement.AccountID,
+		OwnerName:      statement.OwnerName,
+		From:           statement.From.Format(layout),
+		To:             statement.To.Format(layout),
+		OpeningBalance: r.money(statement.OpeningBalance),
+		ClosingBalance: r.money(statement.ClosingBalance),
+		GeneratedAt:    statement.GeneratedAt.Format(layout),
+		SummarySpan:    max(len(r.columns)-1, 1),
+	}
+
+	noteColumn := false
+	headers := make([]string, 0, len(r.columns))
+	for _, name := range r.columns {
+		column := statementColumns[name]
+		view.Header = append(view.Header, r.tr.T(column.title))
+		headers = append(headers, r.cell(r.tr.T(column.title), column.numeric))
+		noteColumn = noteColumn || name == "note"
+	}
+	if r.width > 0 {
+		view.HeaderLine = strings.Join(headers, " | ")
+	}
+
+	balance := statement.OpeningBalance
+	for _, tx := range statement.Transactions {
+		balance += tx.SignedAmount()
+		row := statementRow{
+			Date:       tx.Timestamp.Format(layout),
+			Type:       tx.Type,
+			Message:    tx.Message,
+			Annotation: transactionAnnotation(r.tr, tx),
+			Amount:     fmt.Sprintf("%+.*f", r.precision, tx.SignedAmount()),
+			Balance:    r.money(balance),
+		}
+		if !noteColumn {
+			row.Note = row.Annotation
+		}
+
+		values := make([]string, 0, len(r.columns))
+		for _, name := range r.columns {
+			column := statementColumns[name]
+			cell := statementCell{Value: column.value(row), Numeric: column.numeric}
+			if name == "message" {
+				cell.Note = row.Note
+			}
+			row.Cells = append(row.Cells, cell)
+			values = append(values, r.cell(cell.Value, cell.Numeric))
+		}
+		row.Line = strings.Join(values, " | ")
+
+		view.Rows = append(view.Rows, row)
+	}
+
+	return view
+}
+
+// cell выравнивает значение текстовой ячейки по ширине колонки: числа
+// вправо, остальное влево; длинное значение обрезается
+func (r *TemplateStatementRenderer) cell(value string, numeric bool) string {
+	if r.width <= 0 || r.format != TextStatementFormat {
+		return value
+	}
+
+	if runes := []rune(value); len(runes) > r.width {
+		value = string(runes[:r.width])
+	}
+	if numeric {
+		return fmt.Sprintf("%*s", r.width, value)
+	}
+
+	return fmt.Sprintf("%-*s", r.width, value)
+}
+
+// money форматирует сумму с кодом валюты
+func (r *TemplateStatementRenderer) money(amount float64) string {
+	return fmt.Sprintf("%.*f %s", r.precision, amount, r.currency)
+}
+
+// WithStatementRenderer задает оформление выписки, выводимой GetStatement
+func WithStatementRenderer(renderer interfaces.StatementRenderer) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.renderer = renderer
+	}
+}
+
+// renderStatement оформляет выписку за всю историю счета шаблоном сервиса
+func (s *AccountServiceImpl) renderStatement(ctx context.Context) string {
+	var sb strings.Builder
+	if err := s.renderer.Render(ctx, buildStatement(s.account, time.Time{}, time.Time{}, s.now()), &sb); err != nil {
+		return s.tr.Sprintf("Ошибка: %v\n", err)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"bankapp/i18n"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"text/template"
+)
+
+// textStatementTemplate текстовая выписка по умолчанию, например для тела
+// письма; подписи переводятся функцией T
+var textStatementTemplate = template.Must(template.New("statement").Parse(`{{call .T "Выписка по счету"}}
+========================================
+{{call .T "Владелец"}}: {{.OwnerName}}
+{{call .T "Счет"}}: {{.AccountID}}
+{{call .T "Период"}}: {{.From}} - {{.To}}
+========================================
+{{if .HeaderLine}}{{.HeaderLine}}
+{{end}}{{call .T "Остаток на начало периода"}}: {{.OpeningBalance}}
+{{range .Rows}}{{.Line}}{{if .Note}} [{{.Note}}]{{end}}
+{{end}}{{call .T "Остаток на конец периода"}}: {{.ClosingBalance}}
+{{call .T "Сформировано"}}: {{.GeneratedAt}}
+`))
+
+// NewTextStatementRenderer создает текстовое оформление выписки по шаблону
+// по умолчанию с подписями на языке переводчика tr
+func NewTextStatementRenderer(tr *i18n.Translator, dateFormat models.DateFormat, currency string) interfaces.StatementRenderer {
+	// Настройки по умолчанию всегда корректны
+	renderer, _ := NewStatementRenderer(TextStatementFormat, tr, dateFormat, currency, models.StatementLayout{})
+	return renderer
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+// benchPageSize размер страницы в операциях постраничного чтения прогона
+const benchPageSize = 20
+
+// BenchmarkStorage прогоняет по хранилищу стандартную смешанную нагрузку
+// (60% чтений счета, 30% чтений с записью, 10% постраничных списков)
+// ступенями по 1, 2, 4... воркеров до opts.MaxWorkers. Максимальная
+// устойчивая пропускная способность - лучший TPS среди ступеней без ошибок
+// с p99 не выше opts.P99Target.
+func BenchmarkStorage(ctx context.Context, storage interfaces.Storage, opts models.BenchOptions) (models.BenchReport, error) {
+	ids := make([]string, opts.Accounts)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("BENCH-%06d", i)
+		account := &models.Account{ID: ids[i], OwnerName: "bench", Status: models.ActiveStatus}
+		if err := storage.SaveAccount(ctx, account); err != nil {
+			return models.BenchReport{}, err
+		}
+	}
+
+	var report models.BenchReport
+	for workers := 1; workers <= opts.MaxWorkers; workers *= 2 {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		step := benchStep(ctx, storage, ids, workers, opts.Duration)
+		report.Steps = append(report.Steps, step)
+		if step.Sustainable(opts.P99Target) {
+			report.MaxTPS = max(report.MaxTPS, step.TPS)
+		}
+	}
+
+	return report, nil
+}
+
+// benchStep выполняет одну ступень прогона с заданным числом воркеров
+func benchStep(ctx context.Context, storage interfaces.Storage, ids []string, workers int, duration time.Duration) models.BenchStep {
+	deadline := time.Now().Add(duration)
+
+	var mu sync.Mutex
+	var latencies []time.Duration
+	var errCount int
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var local []time.Duration
+			var localErrors int
+			for time.Now().Before(deadline) && ctx.Err() == nil {
+				began := time.Now()
+				if err := benchOperation(ctx, storage, ids); err != nil {
+					localErrors++
+				}
+				local = append(local, time.Since(began))
+			}
+
+			mu.Lock()
+			latencies = append(latencies, local...)
+			errCount += localErrors
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	slices.Sort(latencies)
+	return models.BenchStep{
+		Workers: workers,
+		Ops:     len(latencies),
+		Errors:  errCount,
+		TPS:     float64(len(latencies)) / elapsed.Seconds(),
+		P50:     percentile(latencies, 50),
+		P95:     percentile(latencies, 95),
+		P99:     percentile(latencies, 99),
+	}
+}
+
+// benchOperation выполняет одну случайную операцию смешанной нагрузки
+func benchOperation(ctx context.Context, storage interfaces.Storage, ids []string) error {
+	id := ids[rand.IntN(len(ids))]
+
+	switch n := rand.IntN(100); {
+	case n < 60:
+		_, err := storage.LoadAccount(ctx, id)
+		return err
+	case n < 90:
+		account, err := storage.LoadAccount(ctx, id)
+		if err != nil {
+			return err
+		}
+		account.Balance++
+		// Конфликт версий при одновременной записи счета - штатный исход
+		// оптимистической блокировки, а не сбой хранилища
+		if err := storage.SaveAccount(ctx, account); !stderrors.Is(err, errors.ErrConcurrentModification) {
+			return err
+		}
+		return nil
+	default:
+		_, _, err := storage.ListAccounts(ctx, rand.IntN(len(ids)), benchPageSize)
+		return err
+	}
+}
+
+// percentile возвращает p-й перцентиль отсортированных задержек
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	index := (len(sorted)*p+99)/100 - 1
+	return sorted[max(index, 0)]
+}
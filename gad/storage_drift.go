@@ -0,0 +1,77 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"sort"
+)
+
+// CompareStorages сверяет основное хранилище с репликой: наличие счетов,
+// балансы и количество транзакций. Хранилища только читаются.
+func CompareStorages(ctx context.Context, primary, replica interfaces.Storage, clock models.Clock) (models.DriftReport, error) {
+	report := models.DriftReport{CheckedAt: clock.Now()}
+
+	primaryAccounts, _, err := primary.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return report, fmt.Errorf("основное хранилище: %w", err)
+	}
+	replicaAccounts, _, err := replica.ListAccounts(ctx, 0, 0)
+	if err != nil {
+		return report, fmt.Errorf("реплика: %w", err)
+	}
+	report.PrimaryAccounts = len(primaryAccounts)
+	report.ReplicaAccounts = len(replicaAccounts)
+
+	replicaByID := make(map[string]*models.Account, len(replicaAccounts))
+	for _, account := range replicaAccounts {
+		replicaByID[account.ID] = account
+	}
+
+	for _, account := range primaryAccounts {
+		replicated, ok := replicaByID[account.ID]
+		delete(replicaByID, account.ID)
+
+		drift := models.StorageDrift{
+			AccountID:      account.ID,
+			PrimaryBalance: account.Balance,
+			PrimaryTxCount: len(account.Transactions),
+		}
+		if !ok {
+			drift.MissingInReplica = true
+			drift.Problem = "счет отсутствует в реплике"
+			report.Drifts = append(report.Drifts, drift)
+			continue
+		}
+
+		drift.ReplicaBalance = replicated.Balance
+		drift.ReplicaTxCount = len(replicated.Transactions)
+		switch {
+		case !sameAmount(account.Balance, replicated.Balance):
+			drift.Problem = fmt.Sprintf("баланс %.2f, в реплике %.2f", account.Balance, replicated.Balance)
+		case len(account.Transactions) != len(replicated.Transactions):
+			drift.Problem = fmt.Sprintf("транзакций %d, в реплике %d", len(account.Transactions), len(replicated.Transactions))
+		default:
+			continue
+		}
+		report.Drifts = append(report.Drifts, drift)
+	}
+
+	// Оставшиеся счета есть только в реплике
+	for _, replicated := range replicaByID {
+		report.Drifts = append(report.Drifts, models.StorageDrift{
+			AccountID:        replicated.ID,
+			Problem:          "счет отсутствует в основном хранилище",
+			ReplicaBalance:   replicated.Balance,
+			ReplicaTxCount:   len(replicated.Transactions),
+			MissingInPrimary: true,
+		})
+	}
+
+	sort.Slice(report.Drifts, func(i, j int) bool {
+		return report.Drifts[i].AccountID < report.Drifts[j].AccountID
+	})
+
+	return report, nil
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"bankapp/errors"
+	"bankapp/interfaces"
+)
+
+// Driver создает хранилище по строке подключения вида scheme://...
+type Driver func(dsn string, options OpenOptions) (interfaces.Storage, error)
+
+// OpenOptions параметры, передаваемые драйверу при открытии хранилища
+type OpenOptions struct {
+	// EncryptionKeys 32-байтовые ключи шифрования данных на диске: первым
+	// данные шифруются, остальные используются для чтения после ротации.
+	// Драйверы, не хранящие данные на диске, их игнорируют.
+	EncryptionKeys [][]byte
+	// MigratePlaintext разрешает прочитать незашифрованный файл при заданных
+	// ключах; файл сразу перезаписывается зашифрованным. Нужен однократно при
+	// включении шифрования, в остальное время незашифрованный файл - ошибка.
+	MigratePlaintext bool
+}
+
+// OpenOption настройка открытия хранилища
+type OpenOption func(*OpenOptions)
+
+// WithEncryptionKeys задает ключи шифрования данных на диске, текущий - первый
+func WithEncryptionKeys(keys ...[]byte) OpenOption {
+	return func(o *OpenOptions) {
+		o.EncryptionKeys = keys
+	}
+}
+
+// WithPlaintextMigration разрешает однократно зашифровать незашифрованный файл
+func WithPlaintextMigration() OpenOption {
+	return func(o *OpenOptions) {
+		o.MigratePlaintext = true
+	}
+}
+
+var (
+	driversMu sync.RWMutex
+	// drivers драйверы по схемам DSN; встроенные - хранилище в памяти и в файле
+	drivers = map[string]Driver{
+		"memory": func(string, OpenOptions) (interfaces.Storage, error) {
+			return NewMemoryStorage(), nil
+		},
+		"file": openFileDriver,
+	}
+)
+
+// Register регистрирует драйвер хранилища для схемы DSN. Сторонние пакеты
+// вызывают его из init; повторная регистрация схемы - ошибка программы.
+func Register(scheme string, driver Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	if driver == nil {
+		panic("storage: Register: пустой драйвер для схемы " + scheme)
+	}
+	if _, exists := drivers[scheme]; exists {
+		panic("storage: Register: схема " + scheme + " уже зарегистрирована")
+	}
+
+	drivers[scheme] = driver
+}
+
+// Drivers возвращает зарегистрированные схемы в алфавитном порядке
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	return driverNames()
+}
+
+// Lookup возвращает драйвер для схемы DSN
+func Lookup(dsn string) (Driver, error) {
+	scheme, _, ok := strings.Cut(dsn, "://")
+	if !ok || scheme == "" {
+		return nil, fmt.Errorf("%w: %q", errors.ErrInvalidDSN, dsn)
+	}
+
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	driver, exists := drivers[scheme]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s (доступны: %s)", errors.ErrUnknownDriver, scheme, strings.Join(driverNames(), ", "))
+	}
+
+	return driver, nil
+}
+
+// Open создает хранилище драйвером, выбранным по схеме DSN
+// (memory://, file:///path, postgres://...)
+func Open(dsn string, opts ...OpenOption) (interfaces.Storage, error) {
+	driver, err := Lookup(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	var options OpenOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return driver(dsn, options)
+}
+
+// driverNames возвращает схемы без блокировки. Вызывается под driversMu.
+func driverNames() []string {
+	schemes := make([]string, 0, len(drivers))
+	for scheme := range drivers {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+
+	return schemes
+}
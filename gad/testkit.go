@@ -0,0 +1,221 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"bankapp/interfaces"
+	"bankapp/models"
+	"bankapp/storage"
+)
+
+// FaultyStorage хранилище для тестов кода, построенного на AccountService:
+// обертка над хранилищем с внедрением отказов. Отказ можно задать для
+// N-го сохранения счета или для следующего вызова любого метода.
+type FaultyStorage struct {
+	storage interfaces.Storage
+
+	mu     sync.Mutex
+	saves  int
+	onSave map[int]error
+	next   map[string]error
+}
+
+// NewFaultyStorage создает хранилище в памяти с внедрением отказов
+func NewFaultyStorage() *FaultyStorage {
+	return WrapStorage(storage.NewMemoryStorage())
+}
+
+// WrapStorage добавляет внедрение отказов к хранилищу storage
+func WrapStorage(storage interfaces.Storage) *FaultyStorage {
+	return &FaultyStorage{
+		storage: storage,
+		onSave:  make(map[int]error),
+		next:    make(map[string]error),
+	}
+}
+
+// FailSave задает ошибку для n-го (с 1) вызова SaveAccount с момента создания
+func (s *FaultyStorage) FailSave(n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onSave[n] = err
+}
+
+// FailNext задает ошибку для следующего вызова метода method, например
+// "LoadAccount"; ошибка возвращается один раз
+func (s *FaultyStorage) FailNext(method string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next[method] = err
+}
+
+// Saves возвращает количество вызовов SaveAccount, включая неудачные
+func (s *FaultyStorage) Saves() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.saves
+}
+
+// fault возвращает ошибку, заданную для текущего вызова метода
+func (s *FaultyStorage) fault(method string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if method == "SaveAccount" {
+		s.saves++
+		if err, ok := s.onSave[s.saves]; ok {
+			delete(s.onSave, s.saves)
+			return err
+		}
+	}
+
+	if err, ok := s.next[method]; ok {
+		delete(s.next, method)
+		return err
+	}
+
+	return nil
+}
+
+// SaveAccount сохраняет счет
+func (s *FaultyStorage) SaveAccount(ctx context.Context, account *models.Account) error {
+	if err := s.fault("SaveAccount"); err != nil {
+		return err
+	}
+
+	return s.storage.SaveAccount(ctx, account)
+}
+
+// LoadAccount загружает счет по ID
+func (s *FaultyStorage) LoadAccount(ctx context.Context, accountID string) (*models.Account, error) {
+	if err := s.fault("LoadAccount"); err != nil {
+		return nil, err
+	}
+
+	return s.storage.LoadAccount(ctx, accountID)
+}
+
+// GetAllAccounts возвращает все счета
+func (s *FaultyStorage) GetAllAccounts(ctx context.Context) ([]*models.Account, error) {
+	if err := s.fault("GetAllAccounts"); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetAllAccounts(ctx)
+}
+
+// ListAccounts возвращает страницу счетов и общее количество счетов
+func (s *FaultyStorage) ListAccounts(ctx context.Context, offset, limit int) ([]*models.Account, int, error) {
+	if err := s.fault("ListAccounts"); err != nil {
+		return nil, 0, err
+	}
+
+	return s.storage.ListAccounts(ctx, offset, limit)
+}
+
+// ListAccountsByScope возвращает страницу счетов из выборки scope и их количество
+func (s *FaultyStorage) ListAccountsByScope(ctx context.Context, scope models.AccountScope, offset, limit int) ([]*models.Account, int, error) {
+	if err := s.fault("ListAccountsByScope"); err != nil {
+		return nil, 0, err
+	}
+
+	return s.storage.ListAccountsByScope(ctx, scope, offset, limit)
+}
+
+// SaveUser сохраняет пользователя
+func (s *FaultyStorage) SaveUser(ctx context.Context, user *models.User) error {
+	if err := s.fault("SaveUser"); err != nil {
+		return err
+	}
+
+	return s.storage.SaveUser(ctx, user)
+}
+
+// LoadUser загружает пользователя по ID
+func (s *FaultyStorage) LoadUser(ctx context.Context, userID string) (*models.User, error) {
+	if err := s.fault("LoadUser"); err != nil {
+		return nil, err
+	}
+
+	return s.storage.LoadUser(ctx, userID)
+}
+
+// FindUserByUsername ищет пользователя по имени
+func (s *FaultyStorage) FindUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	if err := s.fault("FindUserByUsername"); err != nil {
+		return nil, err
+	}
+
+	return s.storage.FindUserByUsername(ctx, username)
+}
+
+// GetAllUsers возвращает всех пользователей
+func (s *FaultyStorage) GetAllUsers(ctx context.Context) ([]*models.User, error) {
+	if err := s.fault("GetAllUsers"); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetAllUsers(ctx)
+}
+
+// SequentialIDs детерминированный генератор идентификаторов: префикс и
+// порядковый номер для каждого префикса (TX1, TX2, ACC1, ...)
+type SequentialIDs struct {
+	mu   sync.Mutex
+	next map[string]int
+}
+
+// NewSequentialIDs создает генератор, нумерующий каждый префикс с 1
+func NewSequentialIDs() models.IDGenerator {
+	return &SequentialIDs{next: make(map[string]int)}
+}
+
+// NewID возвращает следующий идентификатор с префиксом prefix
+func (g *SequentialIDs) NewID(prefix string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.next[prefix]++
+	return fmt.Sprintf("%s%d", prefix, g.next[prefix])
+}
+
+// FakeClock часы для тестов: время стоит на месте, пока его не передвинут
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock создает часы, показывающие время start
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now возвращает текущее время часов
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Set переводит часы на время t
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = t
+}
+
+// Advance переводит часы вперед на d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,63 @@
+package testkit
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// updateGoldenEnv переменная окружения, при значении 1 эталонные файлы
+// перезаписываются полученным результатом вместо сравнения
+const updateGoldenEnv = "BANKAPP_UPDATE_GOLDEN"
+
+// TB часть testing.TB, нужная эталонным проверкам; пакет не импортирует
+// testing, чтобы его можно было подключать вне тестов
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+// AssertGolden сравнивает got с содержимым эталонного файла path
+func AssertGolden(t TB, path string, got []byte) {
+	t.Helper()
+
+	if os.Getenv(updateGoldenEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("создание каталога эталона: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("запись эталона %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("чтение эталона %s (%s=1 создает его): %v", path, updateGoldenEnv, err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Fatalf("результат не совпадает с эталоном %s (%s=1 обновляет его)\n--- получено ---\n%s\n--- ожидалось ---\n%s",
+			path, updateGoldenEnv, got, want)
+	}
+}
+
+// AssertStatementGolden оформляет выписку renderer и сравнивает результат с
+// эталоном path. Время формирования выписки заменяется концом периода,
+// чтобы результат не зависел от момента запуска теста.
+func AssertStatementGolden(t TB, renderer interfaces.StatementRenderer, statement models.Statement, path string) {
+	t.Helper()
+
+	statement.GeneratedAt = statement.To
+
+	var buf bytes.Buffer
+	if err := renderer.Render(context.Background(), statement, &buf); err != nil {
+		t.Fatalf("оформление выписки: %v", err)
+	}
+
+	AssertGolden(t, path, buf.Bytes())
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxCategoryLength = 32
+	maxTagLength      = 32
+	maxTags           = 10
+	maxNoteLength     = 200
+)
+
+type detailsKey struct{}
+
+// WithTransactionDetails возвращает контекст, операции в котором проводятся
+// с указанными категорией, тегами и заметкой
+func WithTransactionDetails(ctx context.Context, details models.TransactionDetails) context.Context {
+	return context.WithValue(ctx, detailsKey{}, details)
+}
+
+// transactionDetails извлекает из контекста разметку операции и приводит ее к каноническому виду
+func transactionDetails(ctx context.Context) (models.TransactionDetails, error) {
+	details, _ := ctx.Value(detailsKey{}).(models.TransactionDetails)
+	return normalizeDetails(details)
+}
+
+// normalizeDetails проверяет разметку: категория и теги приводятся к нижнему
+// регистру, повторяющиеся теги и ведущий # отбрасываются
+func normalizeDetails(details models.TransactionDetails) (models.TransactionDetails, error) {
+	normalized := models.TransactionDetails{
+		Category: strings.ToLower(strings.TrimSpace(details.Category)),
+		Note:     strings.TrimSpace(details.Note),
+	}
+
+	if utf8.RuneCountInString(normalized.Category) > maxCategoryLength {
+		return models.TransactionDetails{}, errors.ErrInvalidCategory
+	}
+
+	if utf8.RuneCountInString(normalized.Note) > maxNoteLength {
+		return models.TransactionDetails{}, errors.ErrNoteTooLong
+	}
+
+	for _, tag := range details.Tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" || slices.Contains(normalized.Tags, tag) {
+			continue
+		}
+
+		if utf8.RuneCountInString(tag) > maxTagLength || strings.ContainsFunc(tag, unicode.IsSpace) {
+			return models.TransactionDetails{}, errors.ErrInvalidTag
+		}
+
+		normalized.Tags = append(normalized.Tags, tag)
+	}
+
+	if len(normalized.Tags) > maxTags {
+		return models.TransactionDetails{}, errors.ErrInvalidTag
+	}
+
+	return normalized, nil
+}
+
+// setDetails переносит разметку на транзакцию
+func setDetails(tx *models.Transaction, details models.TransactionDetails) {
+	tx.Category = details.Category
+	tx.Tags = details.Tags
+	tx.Note = details.Note
+}
+
+// EditTransactionDetails заменяет категорию, теги и заметку уже проведенной транзакции
+func (s *AccountServiceImpl) EditTransactionDetails(ctx context.Context, transactionID string, details models.TransactionDetails) (err error) {
+	defer func() {
+		s.auditOperation(ctx, "edit_transaction_details", 0, "транзакция "+transactionID, err)
+	}()
+
+	details, err = normalizeDetails(details)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.findTransaction(transactionID)
+	if err != nil {
+		return err
+	}
+
+	setDetails(tx, details)
+	return s.saveAccount(ctx)
+}
+
+// FilterTransactions возвращает страницу транзакций счета, подходящих под
+// фильтр (категория, тег, период, сумма, текст), и общее количество совпадений
+func (s *AccountServiceImpl) FilterTransactions(ctx context.Context, filter models.TransactionFilter) ([]models.Transaction, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var matches []models.Transaction
+	for _, tx := range s.account.Transactions {
+		if matchesFilter(tx, filter) {
+			matches = append(matches, tx)
+		}
+	}
+
+	start, end := models.PageBounds(len(matches), filter.Offset, filter.Limit)
+	return matches[start:end], len(matches), nil
+}
+
+// SpendingByCategory суммирует списания за период [from, to) по категориям,
+// от больших трат к меньшим; сторнированные операции не учитываются
+func (s *AccountServiceImpl) SpendingByCategory(ctx context.Context, from, to time.Time) []models.CategorySpending {
+	totals := make(map[string]*models.CategorySpending)
+	for _, tx := range s.account.Transactions {
+		if tx.Direction != models.DebitEntry || tx.ReversedBy != "" {
+			continue
+		}
+
+		if tx.Timestamp.Before(from) || !tx.Timestamp.Before(to) {
+			continue
+		}
+
+		total, ok := totals[tx.Category]
+		if !ok {
+			total = &models.CategorySpending{Category: tx.Category}
+			totals[tx.Category] = total
+		}
+		total.Amount += tx.Amount
+		total.Count++
+	}
+
+	summary := make([]models.CategorySpending, 0, len(totals))
+	for _, total := range totals {
+		summary = append(summary, *total)
+	}
+
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Amount != summary[j].Amount {
+			return summary[i].Amount > summary[j].Amount
+		}
+		return summary[i].Category < summary[j].Category
+	})
+
+	return summary
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"bankapp/errors"
+	"bankapp/models"
+	"bankapp/services"
+)
+
+// withTransactionDetails запрашивает категорию, теги и заметку операции
+// и возвращает контекст, в котором операция будет проведена
+func (app *BankApp) withTransactionDetails(ctx context.Context) context.Context {
+	details := models.TransactionDetails{
+		Category: app.readLine("Категория (Enter - без категории): "),
+		Tags:     strings.Fields(app.readLine("Теги через пробел (Enter - без тегов): ")),
+		Note:     app.readLine("Заметка (Enter - без заметки): "),
+	}
+
+	return services.WithTransactionDetails(ctx, details)
+}
+
+// manageTransactionDetails показывает меню категорий, тегов и заметок
+func (app *BankApp) manageTransactionDetails(ctx context.Context) {
+	app.printHeader("Категории, теги и заметки")
+	app.println("1. Выписка по категории или тегу")
+	app.println("2. Расходы по категориям")
+	app.println("3. Изменить категорию, теги и заметку транзакции")
+	app.println("4. Назад")
+	app.print("Выберите опцию: ")
+	app.scanner.Scan()
+
+	switch strings.TrimSpace(app.scanner.Text()) {
+	case "1":
+		app.filterStatement(ctx)
+	case "2":
+		app.showSpending(ctx)
+	case "3":
+		app.editTransactionDetails(ctx)
+	}
+}
+
+// filterStatement выводит транзакции текущего счета по категории, тегу и периоду
+func (app *BankApp) filterStatement(ctx context.Context) {
+	var filter models.TransactionFilter
+	var err error
+
+	filter.Category = app.readLine("Категория (Enter - любая): ")
+	filter.Tag = app.readLine("Тег (Enter - любой): ")
+
+	if filter.From, err = app.readOptionalDate("Дата с (ГГГГ-ММ-ДД, Enter - без ограничения): "); err != nil {
+		return
+	}
+	if filter.To, err = app.readOptionalDate("Дата по (ГГГГ-ММ-ДД, Enter - без ограничения): "); err != nil {
+		return
+	}
+	if !filter.To.IsZero() {
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	transactions, total, err := app.currentAccount.FilterTransactions(ctx, filter)
+	if err != nil {
+		app.printf("Ошибка при поиске: %v\n", err)
+		return
+	}
+
+	if total == 0 {
+		app.println("Транзакции не найдены")
+		return
+	}
+
+	var sum float64
+	for _, tx := range transactions {
+		app.printf("%s | %s | %.2f | %s | %s | %s\n",
+			app.formatTime(tx.Timestamp), tx.ID, tx.Amount, tx.Category, strings.Join(tx.Tags, " "), tx.Note)
+		sum += tx.SignedAmount()
+	}
+	app.printf("Транзакций: %d, итого: %.2f %s\n", total, sum, app.currency)
+}
+
+// showSpending показывает расходы текущего счета по категориям за период
+func (app *BankApp) showSpending(ctx context.Context) {
+	from, err := app.readOptionalDate("Дата с (ГГГГ-ММ-ДД, Enter - начало месяца): ")
+	if err != nil {
+		return
+	}
+	to, err := app.readOptionalDate("Дата по (ГГГГ-ММ-ДД, Enter - сегодня): ")
+	if err != nil {
+		return
+	}
+
+	now := app.clock.Now()
+	if from.IsZero() {
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	}
+	if to.IsZero() {
+		to = now
+	}
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+
+	summary := app.currentAccount.SpendingByCategory(ctx, from, to)
+	if len(summary) == 0 {
+		app.println("Списаний за период нет")
+		return
+	}
+
+	var total float64
+	for _, spending := range summary {
+		category := spending.Category
+		if category == "" {
+			category = app.tr.T("без категории")
+		}
+		app.printf("%-20s %10.2f (операций: %d)\n", category, spending.Amount, spending.Count)
+		total += spending.Amount
+	}
+	app.printf("Всего списаний: %.2f %s\n", total, app.currency)
+}
+
+// editTransactionDetails меняет категорию, теги и заметку проведенной транзакции
+func (app *BankApp) editTransactionDetails(ctx context.Context) {
+	transactionID := app.readLine("Введите ID транзакции: ")
+
+	transactions, _, err := app.currentAccount.FilterTransactions(ctx, models.TransactionFilter{})
+	if err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+
+	var details models.TransactionDetails
+	found := false
+	for _, tx := range transactions {
+		if tx.ID == transactionID {
+			details = models.TransactionDetails{Category: tx.Category, Tags: tx.Tags, Note: tx.Note}
+			found = true
+		}
+	}
+	if !found {
+		app.printf("Ошибка: %v\n", errors.ErrTransactionNotFound)
+		return
+	}
+
+	if input := app.readLine("Категория (Enter - без изменений, - - убрать): "); input != "" {
+		details.Category = clearable(input)
+	}
+	if input := app.readLine("Теги через пробел (Enter - без изменений, - - убрать): "); input != "" {
+		details.Tags = strings.Fields(clearable(input))
+	}
+	if input := app.readLine("Заметка (Enter - без изменений, - - убрать): "); input != "" {
+		details.Note = clearable(input)
+	}
+
+	if err := app.currentAccount.EditTransactionDetails(ctx, transactionID, details); err != nil {
+		app.printf("Ошибка: %v\n", err)
+		return
+	}
+	app.println("Готово")
+}
+
+// clearable превращает "-" в пустое значение
+func clearable(input string) string {
+	if input == "-" {
+		return ""
+	}
+	return input
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// builtinTransactionTypes встроенные типы, которые нельзя зарегистрировать заново
+var builtinTransactionTypes = map[models.TransactionType]bool{
+	models.DepositTransaction:           true,
+	models.WithdrawTransaction:          true,
+	models.TransferTransaction:          true,
+	models.LedgerTransaction:            true,
+	models.FeeTransaction:               true,
+	models.StatusTransaction:            true,
+	models.ReversalTransaction:          true,
+	models.FeeCorrectionTransaction:     true,
+	models.BalanceCorrectionTransaction: true,
+	models.MaintenanceFee:               true,
+	models.OverdraftLimitTransaction:    true,
+}
+
+// transactionTypePattern код типа: заглавные латинские буквы, цифры и подчеркивания
+var transactionTypePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// TransactionTypeRegistry реестр типов транзакций, заданных оператором
+// в дополнение к встроенным (например, SUBSIDY или PENALTY)
+type TransactionTypeRegistry struct {
+	types map[models.TransactionType]models.TransactionTypeInfo
+	order []models.TransactionType
+}
+
+// NewTransactionTypeRegistry создает реестр из описаний типов,
+// предварительно проверенных ValidateTransactionTypes
+func NewTransactionTypeRegistry(types []models.TransactionTypeInfo) *TransactionTypeRegistry {
+	r := &TransactionTypeRegistry{
+		types: make(map[models.TransactionType]models.TransactionTypeInfo, len(types)),
+	}
+
+	for _, info := range types {
+		r.types[info.Type] = info
+		r.order = append(r.order, info.Type)
+	}
+
+	return r
+}
+
+// ValidateTransactionTypes проверяет описания пользовательских типов транзакций
+func ValidateTransactionTypes(types []models.TransactionTypeInfo) error {
+	seen := make(map[models.TransactionType]bool, len(types))
+	for _, info := range types {
+		switch {
+		case !transactionTypePattern.MatchString(string(info.Type)):
+			return fmt.Errorf("%w: код %q", errors.ErrInvalidTxType, info.Type)
+		case builtinTransactionTypes[info.Type] || seen[info.Type]:
+			return fmt.Errorf("%w: тип %s уже существует", errors.ErrInvalidTxType, info.Type)
+		case strings.TrimSpace(info.Name) == "":
+			return fmt.Errorf("%w: у типа %s нет названия", errors.ErrInvalidTxType, info.Type)
+		case info.Direction != models.CreditEntry && info.Direction != models.DebitEntry:
+			return fmt.Errorf("%w: направление %q типа %s", errors.ErrInvalidTxType, info.Direction, info.Type)
+		}
+
+		seen[info.Type] = true
+	}
+
+	return nil
+}
+
+// Types возвращает зарегистрированные типы в порядке регистрации
+func (r *TransactionTypeRegistry) Types() []models.TransactionTypeInfo {
+	if r == nil {
+		return nil
+	}
+
+	types := make([]models.TransactionTypeInfo, 0, len(r.order))
+	for _, t := range r.order {
+		types = append(types, r.types[t])
+	}
+
+	return types
+}
+
+// Lookup возвращает описание зарегистрированного типа
+func (r *TransactionTypeRegistry) Lookup(t models.TransactionType) (models.TransactionTypeInfo, bool) {
+	if r == nil {
+		return models.TransactionTypeInfo{}, false
+	}
+
+	info, ok := r.types[t]
+	return info, ok
+}
+
+// Name возвращает отображаемое имя типа; для встроенных типов - их код
+func (r *TransactionTypeRegistry) Name(t models.TransactionType) string {
+	if info, ok := r.Lookup(t); ok {
+		return info.Name
+	}
+
+	return string(t)
+}
+
+// WithTransactionTypes подключает реестр пользовательских типов к выпискам
+func WithTransactionTypes(types *TransactionTypeRegistry) AccountOption {
+	return func(s *AccountServiceImpl) {
+		s.types = types
+	}
+}
+
+// statementGroup итог группы пользовательских типов в выписке
+type statementGroup struct {
+	name  string
+	total float64
+}
+
+// statementGroups суммирует транзакции пользовательских типов по группам
+// в порядке первого появления группы в истории счета
+func (s *AccountServiceImpl) statementGroups() []statementGroup {
+	var groups []statementGroup
+	index := make(map[string]int)
+	for _, tx := range s.account.Transactions {
+		info, ok := s.types.Lookup(tx.Type)
+		if !ok || info.Group == "" {
+			continue
+		}
+
+		i, exists := index[info.Group]
+		if !exists {
+			i = len(groups)
+			index[info.Group] = i
+			groups = append(groups, statementGroup{name: info.Group})
+		}
+		groups[i].total += tx.SignedAmount()
+	}
+
+	return groups
+}
+
+// PostCustomTransaction проводит по счету транзакцию зарегистрированного
+// пользовательского типа: сумма зачисляется или списывается в зависимости
+// от направления типа и записывается в журнал событий как корректировка
+func PostCustomTransaction(ctx context.Context, storage interfaces.Storage, ledger interfaces.LedgerStorage, clock models.Clock,
+	types *TransactionTypeRegistry, accountID string, txType models.TransactionType, amount float64, memo string) (models.Transaction, error) {
+	info, ok := types.Lookup(txType)
+	if !ok {
+		return models.Transaction{}, fmt.Errorf("%w: %s", errors.ErrUnknownTxType, txType)
+	}
+
+	if amount <= 0 {
+		return models.Transaction{}, errors.ErrInvalidAmount
+	}
+
+	account, err := storage.LoadAccount(ctx, accountID)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+
+	if account.Status == models.ClosedStatus {
+		return models.Transaction{}, errors.ErrAccountClosed
+	}
+
+	service := &AccountServiceImpl{
+		account: account,
+		storage: storage,
+		ledger:  ledger,
+		clock:   clock,
+	}
+
+	transaction := models.Transaction{
+		ID:        service.newID("TX"),
+		Type:      txType,
+		Amount:    amount,
+		Timestamp: service.now(),
+		Message:   strings.TrimSpace(fmt.Sprintf("%s %.2f %s", info.Name, amount, memo)),
+		Direction: info.Direction,
+	}
+
+	account.Balance += transaction.SignedAmount()
+	account.Transactions = append(account.Transactions, transaction)
+
+	if err := storage.SaveAccount(ctx, account); err != nil {
+		return models.Transaction{}, err
+	}
+
+	return transaction, recordEvent(ctx, ledger, account.ID, models.AdjustmentEvent, transaction.SignedAmount(), transaction.ID, transaction.Timestamp)
+}
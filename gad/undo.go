@@ -0,0 +1,157 @@
+package services
+
+import (
+	"bankapp/errors"
+	"bankapp/models"
+	"context"
+	"time"
+)
+
+// defaultUndoWindow время после операции, в течение которого ее можно отменить
+const defaultUndoWindow = 60 * time.Second
+
+// WithUndoWindow задает время, в течение которого последнюю операцию можно
+// отменить; нулевое значение оставляет время по умолчанию
+func WithUndoWindow(window time.Duration) AccountOption {
+	return func(s *AccountServiceImpl) {
+		if window > 0 {
+			s.undoWindow = window
+		}
+	}
+}
+
+// UndoLast отменяет последнюю операцию счета - пополнение, снятие или
+// исходящий перевод - сторнированием вместе с комиссией за нее. Отмена
+// возможна только в течение окна отмены и только пока после операции не было
+// других операций по счету (и по счету получателя для перевода), а средства,
+// которые нужно вернуть, не удержаны и не потрачены. Сторно операции и
+// комиссии сохраняются вместе. Возвращает отмененную операцию.
+func (s *AccountServiceImpl) UndoLast(ctx context.Context) (undone models.Transaction, err error) {
+	defer func() {
+		s.auditOperation(ctx, "undo_last", undone.Amount, "транзакция "+undone.ID, err)
+	}()
+
+	err = s.retryOnConflict(ctx, func() error {
+		undone, err = s.undoLast(ctx)
+		return err
+	})
+	return undone, err
+}
+
+// undoLast отменяет последнюю операцию по свежей копии счета; UndoLast
+// повторяет отмену при конфликте версий
+func (s *AccountServiceImpl) undoLast(ctx context.Context) (models.Transaction, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Transaction{}, err
+	}
+
+	if err := s.checkVersion(ctx); err != nil {
+		return models.Transaction{}, err
+	}
+
+	if err := checkOperable(s.account); err != nil {
+		return models.Transaction{}, err
+	}
+
+	operation, fee, err := s.lastOperation()
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	undone := *operation
+
+	window := s.undoWindow
+	if window == 0 {
+		window = defaultUndoWindow
+	}
+	if s.now().Sub(operation.Timestamp) > window {
+		return undone, errors.ErrUndoExpired
+	}
+
+	// Возврат пополнения списывает средства, они должны быть доступны
+	if operation.Direction == models.CreditEntry && s.account.AvailableFundsAt(s.now()) < operation.Amount {
+		return undone, errors.ErrUndoBlocked
+	}
+
+	if operation.Type == models.TransferTransaction {
+		if err := s.checkRecipientUntouched(ctx, operation); err != nil {
+			return undone, err
+		}
+	}
+
+	if fee != "" {
+		feeTx, err := s.findTransaction(fee)
+		if err != nil {
+			return undone, err
+		}
+		if err := checkReversible(feeTx); err != nil {
+			return undone, err
+		}
+	}
+
+	others, err := s.postReversal(ctx, operation)
+	if err != nil {
+		return undone, err
+	}
+
+	// Сторно операции добавило запись на счет, комиссия ищется заново
+	if fee != "" {
+		feeTx, err := s.findTransaction(fee)
+		if err != nil {
+			return undone, err
+		}
+		s.reverseLeg(s.account, feeTx, "")
+	}
+
+	return undone, s.saveAccount(ctx, others...)
+}
+
+// lastOperation находит последнюю клиентскую операцию счета и ID комиссии
+// за нее. Операция должна быть последней записью счета, не считая этой комиссии.
+func (s *AccountServiceImpl) lastOperation() (*models.Transaction, string, error) {
+	transactions := s.account.Transactions
+
+	last := len(transactions) - 1
+	fee := ""
+	if last >= 1 && transactions[last].Type == models.FeeTransaction &&
+		transactions[last].RelatedID == transactions[last-1].ID {
+		fee = transactions[last].ID
+		last--
+	}
+	if last < 0 {
+		return nil, "", errors.ErrNothingToUndo
+	}
+
+	operation := &transactions[last]
+	if operation.ReversedBy != "" {
+		return nil, "", errors.ErrNothingToUndo
+	}
+
+	switch {
+	case operation.Type == models.DepositTransaction, operation.Type == models.WithdrawTransaction:
+	case operation.Type == models.TransferTransaction && operation.Direction == models.DebitEntry:
+	default:
+		return nil, "", errors.ErrNothingToUndo
+	}
+
+	return operation, fee, nil
+}
+
+// checkRecipientUntouched проверяет, что получатель перевода еще не
+// распорядился средствами: зачисление - его последняя запись, и сумма доступна
+func (s *AccountServiceImpl) checkRecipientUntouched(ctx context.Context, transfer *models.Transaction) error {
+	recipient, err := s.storage.LoadAccount(ctx, transfer.CounterpartyID)
+	if err != nil {
+		return err
+	}
+
+	transactions := recipient.Transactions
+	if len(transactions) == 0 || transactions[len(transactions)-1].TransferID != transfer.TransferID {
+		return errors.ErrUndoBlocked
+	}
+
+	if recipient.AvailableFundsAt(s.now()) < transfer.Amount {
+		return errors.ErrUndoBlocked
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookBackoff пауза перед первой повторной отправкой, далее удваивается
+const webhookBackoff = 200 * time.Millisecond
+
+// WebhookNotifier отправляет уведомления POST-запросом с JSON на внешний URL
+type WebhookNotifier struct {
+	url     string
+	client  *http.Client
+	retries int
+}
+
+// NewWebhookNotifier создает подписчика, отправляющего уведомления на url.
+// При сетевой ошибке или ответе 5xx отправка повторяется до retries раз.
+func NewWebhookNotifier(url string, client *http.Client, retries int) interfaces.Observer {
+	return &WebhookNotifier{
+		url:     url,
+		client:  client,
+		retries: retries,
+	}
+}
+
+// Notify отправляет уведомление с повторами
+func (n *WebhookNotifier) Notify(ctx context.Context, notification models.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	return n.post(ctx, body, notification.ID)
+}
+
+// post отправляет тело запроса, повторяя попытки с растущей паузой.
+// Непустой idempotencyKey передается в заголовке Idempotency-Key, чтобы
+// получатель мог отбросить повторы.
+func (n *WebhookNotifier) post(ctx context.Context, body []byte, idempotencyKey string) error {
+	backoff := webhookBackoff
+	for attempt := 0; ; attempt++ {
+		err := n.send(ctx, body, idempotencyKey)
+		if err == nil || attempt >= n.retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// send выполняет одну попытку отправки
+func (n *WebhookNotifier) send(ctx context.Context, body []byte, idempotencyKey string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	if isReplay(ctx) {
+		req.Header.Set("X-Webhook-Replay", "true")
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("webhook %s: ответ %s", n.url, resp.Status)
+	}
+
+	return nil
+}
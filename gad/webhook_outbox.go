@@ -0,0 +1,87 @@
+package services
+
+import (
+	"bankapp/interfaces"
+	"bankapp/models"
+	"context"
+)
+
+// replayKey ключ контекста, отмечающий повторную доставку
+type replayKey struct{}
+
+// isReplay сообщает, выполняется ли повторная доставка уведомления
+func isReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayKey{}).(bool)
+	return replay
+}
+
+// OutboxNotifier сохраняет каждое уведомление и результат его доставки
+// в журнал исходящих уведомлений, чтобы их можно было доставить повторно
+type OutboxNotifier struct {
+	outbox interfaces.OutboxStorage
+	next   interfaces.Observer
+	clock  models.Clock
+}
+
+// NewOutboxNotifier создает подписчика, доставляющего уведомления через next
+// с записью в журнал outbox
+func NewOutboxNotifier(outbox interfaces.OutboxStorage, next interfaces.Observer, clock models.Clock) interfaces.Observer {
+	return &OutboxNotifier{
+		outbox: outbox,
+		next:   next,
+		clock:  clock,
+	}
+}
+
+// Notify доставляет уведомление и записывает результат
+func (n *OutboxNotifier) Notify(ctx context.Context, notification models.Notification) error {
+	record := models.OutboxRecord{Notification: notification}
+	err := deliverOutboxRecord(ctx, n.next, n.clock, &record)
+
+	if saveErr := n.outbox.SaveOutboxRecord(ctx, record); saveErr != nil && err == nil {
+		return saveErr
+	}
+	return err
+}
+
+// ReplayOutbox повторно доставляет сохраненные уведомления, отобранные
+// фильтром. Уведомления отправляются с прежними ID, поэтому получатель,
+// уже обработавший событие, может отбросить повтор.
+func ReplayOutbox(ctx context.Context, outbox interfaces.OutboxStorage, observer interfaces.Observer, clock models.Clock, filter models.OutboxFilter) (models.ReplayReport, error) {
+	records, err := outbox.ListOutboxRecords(ctx, filter)
+	if err != nil {
+		return models.ReplayReport{}, err
+	}
+
+	report := models.ReplayReport{Matched: len(records)}
+	ctx = context.WithValue(ctx, replayKey{}, true)
+	for _, record := range records {
+		if err := deliverOutboxRecord(ctx, observer, clock, &record); err != nil {
+			report.Failed = append(report.Failed, record.Notification.ID)
+		} else {
+			report.Delivered++
+		}
+
+		if err := outbox.SaveOutboxRecord(ctx, record); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// deliverOutboxRecord выполняет одну доставку и отмечает ее в записи
+// временем clock
+func deliverOutboxRecord(ctx context.Context, observer interfaces.Observer, clock models.Clock, record *models.OutboxRecord) error {
+	record.Attempts++
+
+	err := observer.Notify(ctx, record.Notification)
+	if err != nil {
+		record.LastError = err.Error()
+		return err
+	}
+
+	record.DeliveredAt = clock.Now()
+	record.LastError = ""
+	return nil
+}
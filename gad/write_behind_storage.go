@@ -0,0 +1,432 @@
+package storage
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"bankapp/errors"
+	"bankapp/interfaces"
+	"bankapp/models"
+)
+
+// slowWriteThreshold средняя длительность записи, начиная с которой
+// сохранения счетов откладываются и пишутся пачками
+const slowWriteThreshold = 5 * time.Millisecond
+
+// WriteBehindStorage обертка над хранилищем, откладывающая SaveAccount.
+// Пока хранилище отвечает быстро, записи идут напрямую; когда средняя
+// длительность записи превышает slowWriteThreshold, счета копятся в буфере
+// и сбрасываются при накоплении maxBatch штук или не позже чем через maxDelay.
+// Чтение счета видит еще не сброшенные изменения. Счет, который не удалось
+// записать, остается в буфере и пишется повторно при следующем сбросе, не
+// задерживая остальные. Если повтор не поможет (конфликт версий), запись
+// отбрасывается: чтение идет в хранилище, а следующее сохранение счета
+// возвращает эту ошибку, чтобы вызывающий перечитал счет. Ошибки сброса
+// по счетам возвращают Flush и Close и показывает FlushErrors. SaveAccounts не откладывается: счета пишутся
+// сразу одной записью, поверх их отложенных изменений. Журнал событий,
+// псевдонимы и аудит берутся у обернутого хранилища, если оно их держит,
+// иначе хранятся в памяти. Перед завершением работы нужно вызвать Close,
+// чтобы сбросить буфер.
+type WriteBehindStorage struct {
+	storage  interfaces.Storage
+	journal  interfaces.JournalStorage
+	maxBatch int
+	maxDelay time.Duration
+
+	mu        sync.Mutex
+	pending   map[string]*pendingAccount
+	order     []string
+	failed    map[string]error
+	dropped   map[string]error
+	avgWrite  time.Duration
+	closed    bool
+	done      chan struct{}
+	flusherWG sync.WaitGroup
+}
+
+// pendingAccount отложенная запись счета: копия счета в том виде, в каком
+// ее видят читатели, и версия, с которой счет лежит в хранилище
+type pendingAccount struct {
+	account *models.Account
+	base    int64
+}
+
+// NewWriteBehindStorage оборачивает хранилище отложенной пакетной записью счетов
+func NewWriteBehindStorage(storage interfaces.Storage, maxBatch int, maxDelay time.Duration) *WriteBehindStorage {
+	journal, ok := storage.(interfaces.JournalStorage)
+	if !ok {
+		journal = memoryJournal{
+			ledger:  NewMemoryLedgerStorage(),
+			aliases: NewMemoryAliasStorage(),
+			audit:   NewMemoryAuditStorage(),
+		}
+	}
+
+	s := &WriteBehindStorage{
+		storage:  storage,
+		journal:  journal,
+		maxBatch: maxBatch,
+		maxDelay: maxDelay,
+		pending:  make(map[string]*pendingAccount),
+		failed:   make(map[string]error),
+		dropped:  make(map[string]error),
+		done:     make(chan struct{}),
+	}
+
+	s.flusherWG.Add(1)
+	go s.flushPeriodically()
+
+	return s
+}
+
+// SaveAccount сохраняет счет напрямую или откладывает запись в буфер.
+// Отложенная запись увеличивает версию счета, как и запись в хранилище;
+// повторные сохранения счета, еще лежащего в буфере, объединяются с ней
+// в одну запись и версию не меняют. Конфликт версий с хранилищем
+// проявится при сбросе.
+func (s *WriteBehindStorage) SaveAccount(ctx context.Context, account *models.Account) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.takeDroppedLocked(account.ID); err != nil {
+		return err
+	}
+
+	if len(s.pending) == 0 && s.avgWrite < slowWriteThreshold {
+		return s.write(ctx, account)
+	}
+
+	entry, queued := s.pending[account.ID]
+	switch {
+	case !queued:
+		entry = &pendingAccount{base: account.Version}
+		s.pending[account.ID] = entry
+		s.order = append(s.order, account.ID)
+		account.Version++
+	case entry.account.Version != account.Version:
+		return errors.ErrConcurrentModification
+	}
+
+	entry.account = account.Clone()
+
+	if len(s.pending) >= s.maxBatch {
+		// Ошибки сброса относятся к другим счетам или будут повторены
+		// при следующем сбросе, сам счет уже принят в буфер
+		_ = s.flushLocked(ctx)
+	}
+
+	return nil
+}
+
+// SaveAccounts сохраняет счета одной записью, если обернутое хранилище это
+// умеет, иначе по одному. Отложенные изменения этих счетов сначала
+// сбрасываются; если это не удалось, пакет не пишется.
+func (s *WriteBehindStorage) SaveAccounts(ctx context.Context, accounts ...*models.Account) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, account := range accounts {
+		if err := s.takeDroppedLocked(account.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, account := range accounts {
+		if _, queued := s.pending[account.ID]; !queued {
+			continue
+		}
+
+		err := s.flushEntryLocked(ctx, account.ID)
+		s.order = slices.DeleteFunc(s.order, func(id string) bool {
+			_, queued := s.pending[id]
+			return !queued
+		})
+		if err != nil {
+			return fmt.Errorf("счет %s: %w", account.ID, err)
+		}
+	}
+
+	batch, ok := s.storage.(interfaces.BatchStorage)
+	if !ok {
+		for _, account := range accounts {
+			if err := s.write(ctx, account); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	start := time.Now()
+	err := batch.SaveAccounts(ctx, accounts...)
+	s.avgWrite += (time.Since(start) - s.avgWrite) / 8
+
+	return err
+}
+
+// Ledger возвращает журнал событий обернутого хранилища или журнал в памяти
+func (s *WriteBehindStorage) Ledger() interfaces.LedgerStorage {
+	return s.journal.Ledger()
+}
+
+// Aliases возвращает индекс псевдонимов обернутого хранилища или индекс в памяти
+func (s *WriteBehindStorage) Aliases() interfaces.AliasStorage {
+	return s.journal.Aliases()
+}
+
+// AuditLog возвращает журнал аудита обернутого хранилища или журнал в памяти
+func (s *WriteBehindStorage) AuditLog() interfaces.AuditStorage {
+	return s.journal.AuditLog()
+}
+
+// LoadAccount загружает счет, учитывая еще не сброшенные изменения
+func (s *WriteBehindStorage) LoadAccount(ctx context.Context, accountID string) (*models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, queued := s.pending[accountID]; queued {
+		return entry.account.Clone(), nil
+	}
+
+	return s.storage.LoadAccount(ctx, accountID)
+}
+
+// GetAllAccounts сбрасывает буфер и возвращает все счета
+func (s *WriteBehindStorage) GetAllAccounts(ctx context.Context) ([]*models.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.flushLocked(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetAllAccounts(ctx)
+}
+
+// ListAccounts сбрасывает буфер и возвращает страницу счетов
+func (s *WriteBehindStorage) ListAccounts(ctx context.Context, offset, limit int) ([]*models.Account, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.flushLocked(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	return s.storage.ListAccounts(ctx, offset, limit)
+}
+
+// ListAccountsByScope сбрасывает буфер и возвращает страницу счетов из выборки scope
+func (s *WriteBehindStorage) ListAccountsByScope(ctx context.Context, scope models.AccountScope, offset, limit int) ([]*models.Account, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.flushLocked(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	return s.storage.ListAccountsByScope(ctx, scope, offset, limit)
+}
+
+// SaveUser сохраняет пользователя
+func (s *WriteBehindStorage) SaveUser(ctx context.Context, user *models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.storage.SaveUser(ctx, user)
+}
+
+// LoadUser загружает пользователя по ID
+func (s *WriteBehindStorage) LoadUser(ctx context.Context, userID string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.storage.LoadUser(ctx, userID)
+}
+
+// FindUserByUsername ищет пользователя по имени
+func (s *WriteBehindStorage) FindUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.storage.FindUserByUsername(ctx, username)
+}
+
+// GetAllUsers возвращает всех пользователей
+func (s *WriteBehindStorage) GetAllUsers(ctx context.Context) ([]*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.storage.GetAllUsers(ctx)
+}
+
+// FlushErrors возвращает ошибки последней попытки записи счетов, которые
+// остались в буфере, и ошибки отброшенных записей, о которых еще не узнал
+// вызывающий
+func (s *WriteBehindStorage) FlushErrors() map[string]error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	failed := make(map[string]error, len(s.failed)+len(s.dropped))
+	for id, err := range s.failed {
+		failed[id] = err
+	}
+	for id, err := range s.dropped {
+		failed[id] = err
+	}
+
+	return failed
+}
+
+// Flush сбрасывает отложенные записи в хранилище и возвращает ошибки
+// по счетам, которые записать не удалось
+func (s *WriteBehindStorage) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.flushLocked(ctx)
+}
+
+// Close останавливает фоновый сброс и записывает оставшийся буфер
+func (s *WriteBehindStorage) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errors.ErrStorageClosed
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.done)
+	s.flusherWG.Wait()
+
+	return s.Flush(ctx)
+}
+
+// flushPeriodically сбрасывает буфер каждые maxDelay, ограничивая устаревание данных
+func (s *WriteBehindStorage) flushPeriodically() {
+	defer s.flusherWG.Done()
+
+	ticker := time.NewTicker(s.maxDelay)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			// Ошибки остаются в FlushErrors, счета с временными ошибками -
+			// в буфере до следующего сброса
+			s.mu.Lock()
+			_ = s.flushLocked(context.Background())
+			s.mu.Unlock()
+		}
+	}
+}
+
+// flushLocked записывает буфер в порядке поступления. Счета с временной
+// ошибкой записи остаются в буфере, а запись остальных продолжается;
+// возвращаются ошибки по всем счетам, которые записать не удалось.
+// Вызывается под s.mu.
+func (s *WriteBehindStorage) flushLocked(ctx context.Context) error {
+	var errs []error
+	remaining := s.order[:0]
+	for _, id := range s.order {
+		if err := s.flushEntryLocked(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("счет %s: %w", id, err))
+			if _, queued := s.pending[id]; queued {
+				remaining = append(remaining, id)
+			}
+		}
+	}
+	s.order = remaining
+
+	return stderrors.Join(errs...)
+}
+
+// flushEntryLocked записывает отложенный счет и убирает его из буфера.
+// Запись, которую бесполезно повторять, тоже убирается из буфера и
+// запоминается в s.dropped. Порядок s.order вызывающий обновляет сам.
+// Вызывается под s.mu.
+func (s *WriteBehindStorage) flushEntryLocked(ctx context.Context, id string) error {
+	entry := s.pending[id]
+	account := entry.account.Clone()
+	account.Version = entry.base
+
+	err := s.write(ctx, account)
+	switch {
+	case err == nil:
+		delete(s.failed, id)
+		delete(s.pending, id)
+	case permanentWriteError(err):
+		delete(s.failed, id)
+		delete(s.pending, id)
+		s.dropped[id] = err
+	default:
+		s.failed[id] = err
+	}
+
+	return err
+}
+
+// takeDroppedLocked возвращает и забывает ошибку отброшенной отложенной
+// записи счета. Сервис, получивший ее, перечитывает счет из хранилища,
+// а не сохраняет копию, построенную поверх отброшенных изменений.
+// Вызывается под s.mu.
+func (s *WriteBehindStorage) takeDroppedLocked(id string) error {
+	err, dropped := s.dropped[id]
+	if !dropped {
+		return nil
+	}
+
+	delete(s.dropped, id)
+	return fmt.Errorf("отложенная запись счета %s отброшена: %w", id, err)
+}
+
+// permanentWriteError сообщает, что повтор записи не поможет: хранилище
+// уже содержит другую версию счета
+func permanentWriteError(err error) bool {
+	return stderrors.Is(err, errors.ErrConcurrentModification)
+}
+
+// write сохраняет счет в хранилище и обновляет среднюю длительность записи
+func (s *WriteBehindStorage) write(ctx context.Context, account *models.Account) error {
+	start := time.Now()
+	err := s.storage.SaveAccount(ctx, account)
+	s.avgWrite += (time.Since(start) - s.avgWrite) / 8
+
+	return err
+}
+
+// memoryJournal журнал событий, псевдонимы и аудит в памяти для хранилища,
+// которое само их не держит
+type memoryJournal struct {
+	ledger  interfaces.LedgerStorage
+	aliases interfaces.AliasStorage
+	audit   interfaces.AuditStorage
+}
+
+// Ledger возвращает журнал событий
+func (j memoryJournal) Ledger() interfaces.LedgerStorage {
+	return j.ledger
+}
+
+// Aliases возвращает индекс псевдонимов
+func (j memoryJournal) Aliases() interfaces.AliasStorage {
+	return j.aliases
+}
+
+// AuditLog возвращает журнал аудита
+func (j memoryJournal) AuditLog() interfaces.AuditStorage {
+	return j.audit
+}